@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/TraceApi/api-core/internal/config"
+	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/core/service"
 	"github.com/TraceApi/api-core/internal/platform/bus"
 	"github.com/TraceApi/api-core/internal/platform/cache"
@@ -33,6 +34,10 @@ func main() {
 	// 1. Configuration
 	cfg := config.Load()
 	log := logger.New(cfg.LogLevel, cfg.IsProduction())
+	if err := cfg.RequirePublicBaseURL(); err != nil {
+		log.Error("Invalid configuration", "error", err)
+		return
+	}
 
 	// 2. Database Connection
 	ctx := context.Background()
@@ -66,46 +71,91 @@ func main() {
 		return
 	}
 
-	// 2c. Initialize Event Bus
-	eventBus := bus.NewRedisEventBus(cfg.RedisAddr)
+	// 2c. Initialize Event Bus (access events; passport events go through the outbox, relayed by cmd/api-worker)
+	eventBus := bus.NewRedisStreamBus(redisClient, bus.StreamConfig{MaxLen: int64(cfg.EventStreamMaxLen)}, log)
+	webhookRepo := postgres.NewWebhookRepository(dbPool)
+
+	// 2d. Access Level Vocabulary
+	accessLevels, err := domain.NewAccessLevels(cfg.AccessLevels)
+	if err != nil {
+		log.Error("Invalid ACCESS_LEVELS", "error", err)
+		return
+	}
 
 	// 3. Dependency Injection (Wiring)
 	// Repo -> Service -> Handler
 	passportRepo := postgres.NewPassportRepository(dbPool)
+	schemaRegistry := service.NewCachedSchemaRegistry(postgres.NewSchemaRegistry(dbPool), 0)
+	grantRepo := postgres.NewGrantRepository(dbPool)
+	accessRequestRepo := postgres.NewAccessRequestRepository(dbPool)
 
 	// Inject Cache into Service
-	passportSvc, err := service.NewPassportService(passportRepo, redisStore, blobStore, eventBus, log)
+	passportSvc, err := service.NewPassportService(passportRepo, redisStore, blobStore, schemaRegistry, grantRepo, authRepo, accessLevels, cfg.PublicBaseURL, log)
 	if err != nil {
 		log.Error("Failed to initialize service", "error", err)
 		return
 	}
 
-	passportHandler := rest.NewPassportHandler(passportSvc, log)
+	jobSvc := service.NewJobService(postgres.NewJobRepository(dbPool), log)
+	passportHandler := rest.NewPassportHandler(passportSvc, jobSvc, log)
+	jobHandler := rest.NewJobHandler(jobSvc, log)
+	grantSvc := service.NewGrantService(grantRepo, passportRepo, eventBus, accessLevels, log)
+	grantHandler := rest.NewGrantHandler(grantSvc, log)
+	accessRequestHandler := rest.NewAccessRequestHandler(service.NewAccessRequestService(accessRequestRepo, passportRepo, grantSvc, eventBus, accessLevels, log), log)
+	linkHandler := rest.NewLinkHandler(service.NewLinkService(postgres.NewLinkRepository(dbPool), passportRepo, log), log)
+	webhookHandler := rest.NewWebhookHandler(service.NewWebhookService(webhookRepo, log), log)
+	analyticsHandler := rest.NewAnalyticsHandler(service.NewAnalyticsService(postgres.NewScanRepository(dbPool), log), log)
+	schemaHandler := rest.NewSchemaHandler(service.NewSchemaService(schemaRegistry, accessLevels, log), log)
+
+	// Event streams hold a connection in a blocking read: give them their own pool
+	streamReader := bus.NewRedisStreamBus(cache.NewRedisClient(cfg.RedisAddr), bus.StreamConfig{}, log)
+	eventHandler := rest.NewEventHandler(service.NewEventStreamService(streamReader, log), log)
 
 	// 4. Router Setup
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:3001", "https://traceapi.eu", "https://console.traceapi.eu", "https://portal.traceapi.eu"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Last-Event-ID"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	// Public Routes
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(60 * time.Second))
+
+		// Public Routes
+		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		})
+
+		// Protected Routes
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.HybridAuthMiddleware(cfg.JWTSecret, authRepo, log))
+			passportHandler.RegisterRoutes(r)
+			grantHandler.RegisterRoutes(r)
+			accessRequestHandler.RegisterRoutes(r)
+			linkHandler.RegisterRoutes(r)
+			jobHandler.RegisterRoutes(r)
+			webhookHandler.RegisterRoutes(r)
+			analyticsHandler.RegisterRoutes(r)
+
+			// Admin Routes (Schema Registry)
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireAdmin(cfg.AdminTenantIDs))
+				schemaHandler.RegisterRoutes(r)
+			})
+		})
 	})
 
-	// Protected Routes
+	// Streaming Routes (long-lived, no request timeout)
 	r.Group(func(r chi.Router) {
 		r.Use(authMiddleware.HybridAuthMiddleware(cfg.JWTSecret, authRepo, log))
-		passportHandler.RegisterRoutes(r)
+		eventHandler.RegisterRoutes(r)
 	})
 
 	log.Info("Starting server", "port", cfg.Port)
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/TraceApi/api-core/internal/config"
+	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/core/service"
 	"github.com/TraceApi/api-core/internal/platform/bus"
 	"github.com/TraceApi/api-core/internal/platform/cache"
@@ -34,6 +35,10 @@ func main() {
 	// 1. Config
 	cfg := config.Load()
 	log := logger.New(cfg.LogLevel, cfg.IsProduction())
+	if err := cfg.RequirePublicBaseURL(); err != nil {
+		log.Error("Invalid configuration", "error", err)
+		return
+	}
 
 	// 2. Infrastructure
 	ctx := context.Background()
@@ -69,19 +74,38 @@ func main() {
 		return
 	}
 
-	// Initialize Event Bus (Resolver doesn't publish, but service requires it)
-	eventBus := bus.NewRedisEventBus(cfg.RedisAddr)
+	// Initialize Event Bus (access requests are published from the resolver)
+	eventBus := bus.NewRedisStreamBus(redisClient, bus.StreamConfig{MaxLen: int64(cfg.EventStreamMaxLen)}, log)
+
+	// Access Level Vocabulary (must match the ingest API)
+	accessLevels, err := domain.NewAccessLevels(cfg.AccessLevels)
+	if err != nil {
+		log.Error("Invalid ACCESS_LEVELS", "error", err)
+		return
+	}
 
 	// 3. Wiring (Identical to Ingest, but we use different handlers)
 	repo := postgres.NewPassportRepository(dbPool)
-	svc, err := service.NewPassportService(repo, redisStore, blobStore, eventBus, log)
+	schemaRegistry := service.NewCachedSchemaRegistry(postgres.NewSchemaRegistry(dbPool), 0)
+	grantRepo := postgres.NewGrantRepository(dbPool)
+	svc, err := service.NewPassportService(repo, redisStore, blobStore, schemaRegistry, grantRepo, authRepo, accessLevels, cfg.PublicBaseURL, log)
 	if err != nil {
 		log.Error("Failed to initialize service", "error", err)
 		return
 	}
 
-	handler := rest.NewResolverHandler(svc, authRepo, log, cfg)
-	passportHandler := rest.NewPassportHandler(svc, log)
+	grantSvc := service.NewGrantService(grantRepo, repo, eventBus, accessLevels, log)
+	accessRequestSvc := service.NewAccessRequestService(postgres.NewAccessRequestRepository(dbPool), repo, grantSvc, eventBus, accessLevels, log)
+
+	linkSvc := service.NewLinkService(postgres.NewLinkRepository(dbPool), repo, log)
+
+	// Scan analytics: hits are buffered and written in batches, off the request path
+	scanRecorder := service.NewScanRecorder(postgres.NewScanRepository(dbPool), service.ScanRecorderConfig{}, log)
+	go scanRecorder.Run(ctx)
+
+	handler := rest.NewResolverHandler(svc, linkSvc, authRepo, scanRecorder, log, cfg)
+	accessRequestHandler := rest.NewAccessRequestHandler(accessRequestSvc, log)
+	passportHandler := rest.NewPassportHandler(svc, nil, log)
 
 	// 4. Router
 	r := chi.NewRouter()
@@ -117,6 +141,9 @@ func main() {
 	r.Group(func(r chi.Router) {
 		r.Use(authMiddleware.HybridAuthMiddleware(cfg.JWTSecret, authRepo, log))
 		passportHandler.RegisterRoutes(r)
+
+		// Third parties asking owners for restricted data
+		accessRequestHandler.RegisterResolverRoutes(r)
 	})
 
 	// 5. Start
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/TraceApi/api-core/internal/config"
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/TraceApi/api-core/internal/platform/bus"
+	"github.com/TraceApi/api-core/internal/platform/cache"
+	"github.com/TraceApi/api-core/internal/platform/logger"
+	"github.com/TraceApi/api-core/internal/platform/storage/postgres"
+	"github.com/TraceApi/api-core/internal/platform/storage/s3"
+	"github.com/TraceApi/api-core/internal/platform/webhook"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func main() {
+	// 1. Configuration
+	cfg := config.Load()
+	log := logger.New(cfg.LogLevel, cfg.IsProduction())
+	if err := cfg.RequirePublicBaseURL(); err != nil {
+		log.Error("Invalid configuration", "error", err)
+		return
+	}
+
+	// Stop claiming jobs on SIGINT/SIGTERM; running jobs are handed back to the queue
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 2. Infrastructure (same as the ingest API: jobs run its use-cases)
+	dbPool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Error("Unable to connect to database", "error", err)
+		return
+	}
+	defer dbPool.Close()
+
+	redisClient := cache.NewRedisClient(cfg.RedisAddr)
+	redisStore := cache.NewRedisStore(redisClient)
+	authRepo := cache.NewRedisAuthRepository(redisClient, dbPool)
+
+	blobStore, err := s3.NewBlobStore(ctx, s3.Config{
+		Endpoint:  cfg.S3Endpoint,
+		Region:    cfg.S3Region,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+	})
+	if err != nil {
+		log.Error("Failed to initialize blob store", "error", err)
+		return
+	}
+
+	eventBus := bus.NewRedisStreamBus(redisClient, bus.StreamConfig{MaxLen: int64(cfg.EventStreamMaxLen)}, log)
+
+	accessLevels, err := domain.NewAccessLevels(cfg.AccessLevels)
+	if err != nil {
+		log.Error("Invalid ACCESS_LEVELS", "error", err)
+		return
+	}
+
+	// 3. Wiring
+	passportRepo := postgres.NewPassportRepository(dbPool)
+	passportSvc, err := service.NewPassportService(passportRepo, redisStore, blobStore, service.NewCachedSchemaRegistry(postgres.NewSchemaRegistry(dbPool), 0), postgres.NewGrantRepository(dbPool), authRepo, accessLevels, cfg.PublicBaseURL, log)
+	if err != nil {
+		log.Error("Failed to initialize service", "error", err)
+		return
+	}
+
+	handlers := map[domain.JobType]ports.JobHandler{
+		domain.JobTypeImport:        service.NewImportJob(passportSvc),
+		domain.JobTypePublish:       service.NewPublishJob(passportSvc),
+		domain.JobTypeSearchReindex: service.NewSearchReindexJob(passportSvc),
+	}
+
+	hostname, _ := os.Hostname()
+	workerID := fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	worker := service.NewJobWorker(postgres.NewJobRepository(dbPool), handlers, service.WorkerConfig{
+		ID:          workerID,
+		Concurrency: cfg.WorkerConcurrency,
+	}, log)
+
+	relay := service.NewOutboxRelay(postgres.NewOutboxRepository(dbPool), eventBus, service.RelayConfig{}, log)
+
+	webhookRepo := postgres.NewWebhookRepository(dbPool)
+	fanout := service.NewWebhookFanout(webhookRepo, log)
+	dispatcher := service.NewWebhookDispatcher(webhookRepo, webhook.NewHTTPSender(), service.DispatcherConfig{}, log)
+
+	scanRollup := service.NewScanRollup(postgres.NewScanRepository(dbPool), service.RollupConfig{}, log)
+
+	// 4. Run
+	log.Info("Starting worker", "concurrency", cfg.WorkerConcurrency)
+	var wg sync.WaitGroup
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		worker.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		relay.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		// Lifecycle events become webhook deliveries (one "webhooks" consumer per worker)
+		if err := eventBus.Subscribe(ctx, "webhooks", workerID, fanout.Channels(), fanout.Handle); err != nil {
+			log.Error("Webhook fan-out stopped", "error", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		scanRollup.Run(ctx)
+	}()
+	wg.Wait()
+	log.Info("Worker stopped")
+}
@@ -20,6 +20,7 @@ import (
 
 func main() {
 	tenantID := flag.String("tenant", "manufacturer-001", "The Tenant ID to associate with this key")
+	accessLevel := flag.String("level", "", "Access level of the key holder (e.g. legitimate_interest, notified_body, commission)")
 	flag.Parse()
 
 	// 1. Generate a random 32-byte hex string (64 chars) as the API Key
@@ -42,6 +43,9 @@ func main() {
 	fmt.Println("\n=== Redis Setup Command ===")
 	fmt.Println("Run this command in your Redis instance to register the key:")
 	fmt.Printf("SET auth:apikey:%s \"%s\"\n", apiKeyHash, *tenantID)
+	if *accessLevel != "" {
+		fmt.Printf("SET auth:apikey:level:%s \"%s\"\n", apiKeyHash, *accessLevel)
+	}
 	fmt.Println("\n=== Curl Example ===")
 	fmt.Printf("curl -v -H \"Authorization: Bearer %s\" http://localhost:8080/health\n", apiKey)
 }
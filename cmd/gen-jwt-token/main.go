@@ -10,6 +10,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"time"
 
@@ -20,12 +21,18 @@ func main() {
 	// Must match the secret in internal/config/config.go
 	secret := []byte("super-secret-dev-key-do-not-use-in-prod")
 
+	accessLevel := flag.String("level", "", "Access level claim (e.g. legitimate_interest, notified_body, commission)")
+	flag.Parse()
+
 	claims := jwt.MapClaims{
 		"sub":             "manufacturer-001",
 		"manufacturer_id": "manufacturer-001",
 		"exp":             time.Now().Add(24 * time.Hour).Unix(),
 		"iat":             time.Now().Unix(),
 	}
+	if *accessLevel != "" {
+		claims["access_level"] = *accessLevel
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(secret)
@@ -10,7 +10,11 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -28,6 +32,26 @@ type Config struct {
 	S3AccessKey string
 	S3SecretKey string
 	S3Bucket    string
+
+	// Public resolver origin recorded in published passports and encoded in QR codes
+	// (e.g. https://tapi.eu); required by the services that publish or resolve passports
+	PublicBaseURL string
+
+	// Tenants allowed to manage the schema registry
+	AdminTenantIDs []string
+
+	// Access level vocabulary, lowest first (empty = Annex XIII defaults)
+	AccessLevels []string
+
+	// Jobs run in parallel by each cmd/api-worker process
+	WorkerConcurrency int
+
+	// Entries kept per event stream (approximate; 0 = bus default)
+	EventStreamMaxLen int
+
+	// Request header carrying the viewer's country for scan analytics, set by the CDN or proxy
+	// (e.g. CF-IPCountry); empty = countries are not recorded
+	ScanCountryHeader string
 }
 
 // Load returns the application configuration from environment variables
@@ -45,6 +69,16 @@ func Load() *Config {
 		S3AccessKey: getEnv("S3_ACCESS_KEY", "minio_admin"),
 		S3SecretKey: getEnv("S3_SECRET_KEY", "minio_password"),
 		S3Bucket:    getEnv("S3_BUCKET", "passports"),
+
+		PublicBaseURL: getEnv("PUBLIC_BASE_URL", ""),
+
+		AdminTenantIDs: getEnvList("ADMIN_TENANT_IDS"),
+		AccessLevels:   getEnvList("ACCESS_LEVELS"),
+
+		WorkerConcurrency: getEnvInt("WORKER_CONCURRENCY", 4),
+		EventStreamMaxLen: getEnvInt("EVENT_STREAM_MAXLEN", 100000),
+
+		ScanCountryHeader: getEnv("SCAN_COUNTRY_HEADER", ""),
 	}
 }
 
@@ -55,6 +89,38 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvInt reads an integer, falling back on missing or malformed values.
+func getEnvInt(key string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return n
+	}
+	return fallback
+}
+
+// getEnvList reads a comma-separated list, ignoring empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// RequirePublicBaseURL checks that PUBLIC_BASE_URL is an absolute http(s) origin. It is never
+// guessed from requests: QR codes and envelopes are cached and printed for years.
+func (c *Config) RequirePublicBaseURL() error {
+	if c.PublicBaseURL == "" {
+		return errors.New("PUBLIC_BASE_URL is required")
+	}
+	u, err := url.Parse(c.PublicBaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("PUBLIC_BASE_URL %q is not an http(s) URL", c.PublicBaseURL)
+	}
+	return nil
+}
+
 func (c *Config) IsProduction() bool {
 	return strings.ToLower(c.Environment) == "production"
 }
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccessLevel is the audience a passport field is disclosed to (Battery Regulation Annex XIII).
+type AccessLevel string
+
+const (
+	AccessPublic             AccessLevel = "public"              // Consumers, general public
+	AccessLegitimateInterest AccessLevel = "legitimate_interest" // Repairers, recyclers, remanufacturers
+	AccessNotifiedBody       AccessLevel = "notified_body"       // Notified bodies, market surveillance, customs
+	AccessCommission         AccessLevel = "commission"          // European Commission
+
+	// AccessRestricted is the legacy binary marker. It means the first level above public.
+	AccessRestricted AccessLevel = "restricted"
+)
+
+// DefaultAccessLevels is the Annex XIII vocabulary, lowest first.
+var DefaultAccessLevels = []AccessLevel{AccessPublic, AccessLegitimateInterest, AccessNotifiedBody, AccessCommission}
+
+// AccessLevels is an ordered access vocabulary: each level sees everything the lower ones see.
+type AccessLevels struct {
+	levels []AccessLevel
+	rank   map[AccessLevel]int
+}
+
+// NewAccessLevels builds a vocabulary from level names, lowest first. An empty list
+// yields DefaultAccessLevels. The first level must be "public".
+func NewAccessLevels(names []string) (*AccessLevels, error) {
+	levels := DefaultAccessLevels
+	if len(names) > 0 {
+		levels = make([]AccessLevel, 0, len(names))
+		for _, n := range names {
+			levels = append(levels, AccessLevel(strings.ToLower(strings.TrimSpace(n))))
+		}
+	}
+
+	if len(levels) < 2 {
+		return nil, fmt.Errorf("%w: at least two access levels are required", ErrInvalidInput)
+	}
+	if levels[0] != AccessPublic {
+		return nil, fmt.Errorf("%w: the lowest access level must be %q", ErrInvalidInput, AccessPublic)
+	}
+
+	rank := make(map[AccessLevel]int, len(levels)+1)
+	for i, l := range levels {
+		if l == "" {
+			return nil, fmt.Errorf("%w: empty access level", ErrInvalidInput)
+		}
+		if _, dup := rank[l]; dup {
+			return nil, fmt.Errorf("%w: duplicate access level %q", ErrInvalidInput, l)
+		}
+		rank[l] = i
+	}
+	if _, defined := rank[AccessRestricted]; !defined {
+		rank[AccessRestricted] = 1
+	}
+
+	return &AccessLevels{levels: levels, rank: rank}, nil
+}
+
+// MustDefaultAccessLevels returns the Annex XIII vocabulary.
+func MustDefaultAccessLevels() *AccessLevels {
+	l, err := NewAccessLevels(nil)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Rank returns the position of a level (0 = public) and whether it is part of the vocabulary.
+func (a *AccessLevels) Rank(level AccessLevel) (int, bool) {
+	r, ok := a.rank[AccessLevel(strings.ToLower(string(level)))]
+	return r, ok
+}
+
+// ViewerRank returns the rank a viewer claiming this level gets. Unknown levels fail closed to public.
+func (a *AccessLevels) ViewerRank(level AccessLevel) int {
+	r, _ := a.Rank(level)
+	return r
+}
+
+// Levels returns the vocabulary, lowest first.
+func (a *AccessLevels) Levels() []AccessLevel {
+	return append([]AccessLevel(nil), a.levels...)
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending  AccessRequestStatus = "PENDING"
+	AccessRequestApproved AccessRequestStatus = "APPROVED"
+	AccessRequestDenied   AccessRequestStatus = "DENIED"
+)
+
+// AccessRequest is a third party asking a manufacturer for restricted data on one passport.
+// Approval creates a time-limited, passport-scoped AccessGrant.
+type AccessRequest struct {
+	ID                uuid.UUID           `json:"id" db:"id"`
+	PassportID        uuid.UUID           `json:"passportId" db:"passport_id"`
+	ManufacturerID    string              `json:"manufacturerId" db:"manufacturer_id"` // Owner deciding the request
+	RequesterTenantID string              `json:"requesterTenantId" db:"requester_tenant_id"`
+	Purpose           string              `json:"purpose" db:"purpose"`
+	AccessLevel       AccessLevel         `json:"accessLevel" db:"access_level"`
+	Status            AccessRequestStatus `json:"status" db:"status"`
+	CreatedAt         time.Time           `json:"createdAt" db:"created_at"`
+	DecidedAt         *time.Time          `json:"decidedAt,omitempty" db:"decided_at"`
+	DecisionNote      string              `json:"decisionNote,omitempty" db:"decision_note"`
+	GrantID           *uuid.UUID          `json:"grantId,omitempty" db:"grant_id"` // Set on approval
+}
+
+// AccessRequestInput is what a third party submits on the resolver.
+type AccessRequestInput struct {
+	Purpose     string      `json:"purpose"`
+	AccessLevel AccessLevel `json:"accessLevel,omitempty"` // Defaults to the first level above public
+}
+
+// AccessDecision is the owner's answer to an access request.
+type AccessDecision struct {
+	Note      string     `json:"note,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // Approval only; defaults to 30 days
+}
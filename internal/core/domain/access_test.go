@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAccessLevels(t *testing.T) {
+	levels, err := domain.NewAccessLevels(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DefaultAccessLevels, levels.Levels())
+
+	// "restricted" is an alias for the first tier above public
+	rank, ok := levels.Rank(domain.AccessRestricted)
+	assert.True(t, ok)
+	assert.Equal(t, 1, rank)
+
+	custom, err := domain.NewAccessLevels([]string{"public", " Partner ", "regulator"})
+	assert.NoError(t, err)
+	rank, ok = custom.Rank("partner")
+	assert.True(t, ok)
+	assert.Equal(t, 1, rank)
+	assert.Equal(t, 0, custom.ViewerRank("commission"), "unknown levels fail closed")
+
+	for _, invalid := range [][]string{{"public"}, {"partner", "public"}, {"public", "a", "a"}, {"public", ""}} {
+		_, err := domain.NewAccessLevels(invalid)
+		assert.Error(t, err, "%v", invalid)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PassportEnvelope is the canonical published document (schemas/master-envelope.json).
+// It is what gets uploaded to blob storage and hashed when a passport is published.
+type PassportEnvelope struct {
+	PassportID      uuid.UUID            `json:"passportId"`
+	PublicURL       string               `json:"publicUrl,omitempty"`
+	Status          PassportStatus       `json:"status"`
+	ProductCategory ProductCategory      `json:"productCategory"`
+	Manufacturer    EnvelopeManufacturer `json:"manufacturer"`
+	IssuanceDate    time.Time            `json:"issuanceDate"`
+	Payload         json.RawMessage      `json:"payload"`
+}
+
+// EnvelopeManufacturer identifies the economic operator placing the product on the market.
+type EnvelopeManufacturer struct {
+	Name       string `json:"name"`
+	DUNSNumber string `json:"dunsNumber"`
+	Country    string `json:"country"`
+}
+
+// TenantProfile is the registered identity of a tenant (organisation).
+type TenantProfile struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	DUNSNumber string `json:"dunsNumber"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-2
+}
@@ -24,6 +24,12 @@ var (
 	// ErrPassportAlreadyPublished is returned when trying to publish a passport that is already published.
 	ErrPassportAlreadyPublished = errors.New("passport already published")
 
+	// ErrPassportRevoked is returned when trying to modify a passport that has been revoked.
+	ErrPassportRevoked = errors.New("passport has been revoked")
+
+	// ErrForbidden is returned when the caller does not own the requested resource.
+	ErrForbidden = errors.New("forbidden")
+
 	// ErrInternal is returned when an unexpected error occurs.
 	ErrInternal = errors.New("internal error")
 )
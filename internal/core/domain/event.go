@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventMessage is an event read back from the event bus by a subscriber.
+type EventMessage struct {
+	ID      string          // Position in the channel; unchanged when the event is delivered again
+	Channel string          // e.g. "events:passport_published"
+	Payload json.RawMessage // The published event, JSON-encoded
+}
+
+// Envelope attributes of the lifecycle events (CloudEvents 1.0, JSON format).
+const (
+	EventSpecVersion = "1.0"
+	EventSource      = "urn:traceapi:api-core"
+)
+
+// EventType names a lifecycle event and the version of its data. A breaking change to the
+// data gets a new type (".v2"), published alongside the old one until consumers have moved.
+// The catalogue is documented in docs/events.md.
+type EventType string
+
+const (
+	EventPassportCreated    EventType = "eu.traceapi.passport.created.v1"
+	EventPassportUpdated    EventType = "eu.traceapi.passport.updated.v1"
+	EventPassportPublished  EventType = "eu.traceapi.passport.published.v1"
+	EventPassportRevoked    EventType = "eu.traceapi.passport.revoked.v1"
+	EventPassportSuperseded EventType = "eu.traceapi.passport.superseded.v1"
+	EventAccessGranted      EventType = "eu.traceapi.access.granted.v1"
+	EventAccessRevoked      EventType = "eu.traceapi.access.revoked.v1"
+
+	EventAccessRequested       EventType = "eu.traceapi.access.requested.v1"
+	EventAccessRequestApproved EventType = "eu.traceapi.access.request_approved.v1"
+	EventAccessRequestDenied   EventType = "eu.traceapi.access.request_denied.v1"
+)
+
+// EventTypes lists the catalogue, in a fixed order: stream cursors are positional, so new types
+// are appended.
+var EventTypes = []EventType{
+	EventPassportCreated,
+	EventPassportUpdated,
+	EventPassportPublished,
+	EventPassportRevoked,
+	EventAccessGranted,
+	EventAccessRevoked,
+	EventPassportSuperseded,
+	EventAccessRequested,
+	EventAccessRequestApproved,
+	EventAccessRequestDenied,
+}
+
+// eventChannels maps each event type to the event bus channel it is published on.
+var eventChannels = map[EventType]string{
+	EventPassportCreated:    "events:passport_created",
+	EventPassportUpdated:    "events:passport_updated",
+	EventPassportPublished:  "events:passport_published",
+	EventPassportRevoked:    "events:passport_revoked",
+	EventPassportSuperseded: "events:passport_superseded",
+	EventAccessGranted:      "events:access_granted",
+	EventAccessRevoked:      "events:access_grant_revoked",
+
+	EventAccessRequested:       "events:access_requested",
+	EventAccessRequestApproved: "events:access_request_approved",
+	EventAccessRequestDenied:   "events:access_request_denied",
+}
+
+// Channel returns the event bus channel of the event type.
+func (t EventType) Channel() string {
+	return eventChannels[t]
+}
+
+// StreamEvent is a lifecycle event streamed live to its tenant.
+type StreamEvent struct {
+	Cursor  string // Resumes the stream right after this event
+	Type    EventType
+	Payload json.RawMessage // The Event envelope, as published
+}
+
+// Event is the envelope of every lifecycle event: a CloudEvents 1.0 event in JSON format, with
+// the owning tenant as the "tenant" extension attribute.
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              uuid.UUID       `json:"id"`
+	Source          string          `json:"source"`
+	Type            EventType       `json:"type"`
+	Time            time.Time       `json:"time"`
+	Subject         string          `json:"subject"` // ID of the passport (or grant, or request) concerned
+	Tenant          string          `json:"tenant"`  // Manufacturer owning the subject
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// NewEvent wraps the data of an event in its envelope.
+func NewEvent(eventType EventType, tenant, subject string, data interface{}, at time.Time) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s data: %w", eventType, err)
+	}
+	return Event{
+		SpecVersion:     EventSpecVersion,
+		ID:              uuid.New(),
+		Source:          EventSource,
+		Type:            eventType,
+		Time:            at,
+		Subject:         subject,
+		Tenant:          tenant,
+		DataContentType: "application/json",
+		Data:            raw,
+	}, nil
+}
+
+// PassportEventData is the data of the passport events: the passport as it is after the change.
+type PassportEventData struct {
+	PassportID        uuid.UUID       `json:"passportId"`
+	ProductCategory   ProductCategory `json:"productCategory"`
+	Status            PassportStatus  `json:"status"`
+	Level             PassportLevel   `json:"level,omitempty"`
+	GTIN              string          `json:"gtin,omitempty"`
+	BatchNumber       string          `json:"batchNumber,omitempty"`
+	SerialNumber      string          `json:"serialNumber,omitempty"`
+	Version           int             `json:"version"`
+	PreviousVersionID *uuid.UUID      `json:"previousVersionId,omitempty"`
+	SchemaVersion     string          `json:"schemaVersion,omitempty"`
+	ImmutabilityHash  string          `json:"immutabilityHash,omitempty"` // Once published
+	PublishedAt       *time.Time      `json:"publishedAt,omitempty"`
+	SupersededByID    *uuid.UUID      `json:"supersededBy,omitempty"` // Once superseded
+	Revocation        *Revocation     `json:"revocation,omitempty"`   // Once revoked
+}
+
+// NewPassportEvent describes the current state of a passport as an event of the given type.
+func NewPassportEvent(eventType EventType, p *Passport, at time.Time) (Event, error) {
+	data := PassportEventData{
+		PassportID:        p.ID,
+		ProductCategory:   p.ProductCategory,
+		Status:            p.Status,
+		Level:             p.Level,
+		GTIN:              p.GTIN,
+		BatchNumber:       p.BatchNumber,
+		SerialNumber:      p.SerialNumber,
+		Version:           max(p.Version, 1),
+		PreviousVersionID: p.PreviousVersionID,
+		SchemaVersion:     p.SchemaVersion,
+		ImmutabilityHash:  p.ImmutabilityHash,
+		PublishedAt:       p.PublishedAt,
+		SupersededByID:    p.SupersededByID,
+		Revocation:        p.Revocation,
+	}
+	return NewEvent(eventType, p.ManufacturerID, p.ID.String(), data, at)
+}
+
+// AccessGrantEventData is the data of the access events.
+type AccessGrantEventData struct {
+	GrantID         uuid.UUID       `json:"grantId"`
+	GranteeTenantID string          `json:"granteeTenantId"`
+	Scope           GrantScope      `json:"scope"`
+	PassportID      *uuid.UUID      `json:"passportId,omitempty"`
+	ProductCategory ProductCategory `json:"productCategory,omitempty"`
+	AccessLevel     AccessLevel     `json:"accessLevel"`
+	ExpiresAt       time.Time       `json:"expiresAt"`
+	RevokedAt       *time.Time      `json:"revokedAt,omitempty"`
+}
+
+// NewAccessGrantEvent describes the current state of a grant as an event of the given type.
+func NewAccessGrantEvent(eventType EventType, g *AccessGrant, at time.Time) (Event, error) {
+	data := AccessGrantEventData{
+		GrantID:         g.ID,
+		GranteeTenantID: g.GranteeTenantID,
+		Scope:           g.Scope,
+		PassportID:      g.PassportID,
+		ProductCategory: g.ProductCategory,
+		AccessLevel:     g.AccessLevel,
+		ExpiresAt:       g.ExpiresAt,
+		RevokedAt:       g.RevokedAt,
+	}
+	return NewEvent(eventType, g.ManufacturerID, g.ID.String(), data, at)
+}
+
+// AccessRequestEventData is the data of the access request events.
+type AccessRequestEventData struct {
+	RequestID         uuid.UUID           `json:"requestId"`
+	PassportID        uuid.UUID           `json:"passportId"`
+	RequesterTenantID string              `json:"requesterTenantId"`
+	Purpose           string              `json:"purpose"`
+	AccessLevel       AccessLevel         `json:"accessLevel"`
+	Status            AccessRequestStatus `json:"status"`
+	DecidedAt         *time.Time          `json:"decidedAt,omitempty"`
+	GrantID           *uuid.UUID          `json:"grantId,omitempty"` // Once approved
+}
+
+// NewAccessRequestEvent describes the current state of an access request as an event of the
+// given type.
+func NewAccessRequestEvent(eventType EventType, r *AccessRequest, at time.Time) (Event, error) {
+	data := AccessRequestEventData{
+		RequestID:         r.ID,
+		PassportID:        r.PassportID,
+		RequesterTenantID: r.RequesterTenantID,
+		Purpose:           r.Purpose,
+		AccessLevel:       r.AccessLevel,
+		Status:            r.Status,
+		DecidedAt:         r.DecidedAt,
+		GrantID:           r.GrantID,
+	}
+	return NewEvent(eventType, r.ManufacturerID, r.ID.String(), data, at)
+}
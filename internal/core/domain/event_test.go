@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassport_RecordEvent(t *testing.T) {
+	publishedAt := time.Date(2025, 11, 28, 9, 30, 0, 0, time.UTC)
+	p := &Passport{
+		ID:               uuid.New(),
+		ProductCategory:  CategoryBattery,
+		Status:           StatusPublished,
+		ManufacturerID:   "mfg-1",
+		Version:          2,
+		ImmutabilityHash: "abc123",
+		PublishedAt:      &publishedAt,
+	}
+
+	require.NoError(t, p.RecordEvent(EventPassportPublished))
+	require.Len(t, p.Events, 1)
+	assert.Equal(t, "events:passport_published", p.Events[0].Channel)
+	assert.Equal(t, p.ID, p.Events[0].PassportID)
+
+	// The payload is a CloudEvents envelope carrying the passport state
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal(p.Events[0].Payload, &event))
+	assert.Equal(t, "1.0", event["specversion"])
+	assert.Equal(t, p.Events[0].ID.String(), event["id"])
+	assert.Equal(t, EventSource, event["source"])
+	assert.Equal(t, "eu.traceapi.passport.published.v1", event["type"])
+	assert.Equal(t, p.ID.String(), event["subject"])
+	assert.Equal(t, "mfg-1", event["tenant"])
+	assert.NotEmpty(t, event["time"])
+	assert.Equal(t, map[string]interface{}{
+		"passportId":       p.ID.String(),
+		"productCategory":  "BATTERY_INDUSTRIAL",
+		"status":           "PUBLISHED",
+		"version":          float64(2),
+		"immutabilityHash": "abc123",
+		"publishedAt":      "2025-11-28T09:30:00Z",
+	}, event["data"])
+}
+
+func TestEventType_Channel(t *testing.T) {
+	channels := make(map[string]bool)
+	for _, eventType := range EventTypes {
+		assert.NotEmpty(t, eventType.Channel(), eventType)
+		assert.False(t, channels[eventType.Channel()], eventType)
+		channels[eventType.Channel()] = true
+	}
+}
+
+func TestNewAccessRequestEvent(t *testing.T) {
+	decidedAt := time.Date(2025, 11, 28, 9, 30, 0, 0, time.UTC)
+	grantID := uuid.New()
+	r := &AccessRequest{
+		ID:                uuid.New(),
+		PassportID:        uuid.New(),
+		ManufacturerID:    "mfg-1",
+		RequesterTenantID: "recycler-1",
+		Purpose:           "Dismantling",
+		AccessLevel:       AccessLegitimateInterest,
+		Status:            AccessRequestApproved,
+		DecidedAt:         &decidedAt,
+		DecisionNote:      "internal",
+		GrantID:           &grantID,
+	}
+
+	event, err := NewAccessRequestEvent(EventAccessRequestApproved, r, decidedAt)
+
+	require.NoError(t, err)
+	assert.Equal(t, "events:access_request_approved", event.Type.Channel())
+	assert.Equal(t, r.ID.String(), event.Subject)
+	assert.Equal(t, "mfg-1", event.Tenant)
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(event.Data, &data))
+	assert.Equal(t, map[string]interface{}{
+		"requestId":         r.ID.String(),
+		"passportId":        r.PassportID.String(),
+		"requesterTenantId": "recycler-1",
+		"purpose":           "Dismantling",
+		"accessLevel":       "legitimate_interest",
+		"status":            "APPROVED",
+		"decidedAt":         "2025-11-28T09:30:00Z",
+		"grantId":           grantID.String(),
+	}, data)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GrantScope is the set of passports an access grant covers.
+type GrantScope string
+
+const (
+	GrantScopePassport     GrantScope = "PASSPORT"     // A single product, through all its revisions
+	GrantScopeCategory     GrantScope = "CATEGORY"     // Every passport of one product category
+	GrantScopeManufacturer GrantScope = "MANUFACTURER" // Every passport of the manufacturer
+)
+
+// AccessGrant lets another tenant (e.g. a recycler) read a manufacturer's passports
+// up to AccessLevel, until it expires or is revoked.
+type AccessGrant struct {
+	ID              uuid.UUID       `json:"id" db:"id"`
+	ManufacturerID  string          `json:"manufacturerId" db:"manufacturer_id"` // Grantor
+	GranteeTenantID string          `json:"granteeTenantId" db:"grantee_tenant_id"`
+	Scope           GrantScope      `json:"scope" db:"scope"`
+	PassportID      *uuid.UUID      `json:"passportId,omitempty" db:"passport_id"`           // PASSPORT scope only
+	ProductCategory ProductCategory `json:"productCategory,omitempty" db:"product_category"` // CATEGORY scope only
+	AccessLevel     AccessLevel     `json:"accessLevel" db:"access_level"`
+	ExpiresAt       time.Time       `json:"expiresAt" db:"expires_at"`
+	CreatedAt       time.Time       `json:"createdAt" db:"created_at"`
+	RevokedAt       *time.Time      `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+// IsActive reports whether the grant is neither revoked nor expired at the given time.
+func (g *AccessGrant) IsActive(now time.Time) bool {
+	return g.RevokedAt == nil && now.Before(g.ExpiresAt)
+}
+
+// Covers reports whether the grant applies to the passport, given the IDs of every revision of it
+// (a PASSPORT grant names one revision, but the product keeps it as it is revised).
+func (g *AccessGrant) Covers(p *Passport, revisions []uuid.UUID) bool {
+	if p.ManufacturerID != g.ManufacturerID {
+		return false
+	}
+	switch g.Scope {
+	case GrantScopeManufacturer:
+		return true
+	case GrantScopeCategory:
+		return p.ProductCategory == g.ProductCategory
+	case GrantScopePassport:
+		return g.PassportID != nil && slices.Contains(revisions, *g.PassportID)
+	}
+	return false
+}
+
+// GrantRequest is the input for creating an access grant.
+type GrantRequest struct {
+	GranteeTenantID string          `json:"granteeTenantId"`
+	Scope           GrantScope      `json:"scope"`
+	PassportID      *uuid.UUID      `json:"passportId,omitempty"`
+	ProductCategory ProductCategory `json:"productCategory,omitempty"`
+	AccessLevel     AccessLevel     `json:"accessLevel,omitempty"` // Defaults to the first level above public
+	ExpiresAt       time.Time       `json:"expiresAt"`
+}
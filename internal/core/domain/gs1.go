@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProductIdentifiers are the GS1 keys a passport is reachable by (GS1 Digital Link).
+// A GTIN alone identifies a model, GTIN + batch a lot, GTIN + serial a single item.
+type ProductIdentifiers struct {
+	GTIN         string `json:"gtin,omitempty"`         // AI (01), stored as GTIN-14
+	BatchNumber  string `json:"batchNumber,omitempty"`  // AI (10)
+	SerialNumber string `json:"serialNumber,omitempty"` // AI (21)
+}
+
+// maxGS1ValueLength is the maximum length of AI (10) and AI (21) values.
+const maxGS1ValueLength = 20
+
+// gs1CharacterSet82 is GS1 AI encodable character set 82 (GS1 General Specifications 7.11).
+const gs1CharacterSet82 = `!"%&'()*+,-./0123456789:;<=>?ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz`
+
+// IsZero reports whether no identifier was given.
+func (ids ProductIdentifiers) IsZero() bool {
+	return ids.GTIN == "" && ids.BatchNumber == "" && ids.SerialNumber == ""
+}
+
+// Normalize validates the identifiers and returns them with the GTIN padded to 14 digits.
+// Batch and serial numbers qualify a GTIN and cannot be used on their own.
+func (ids ProductIdentifiers) Normalize() (ProductIdentifiers, error) {
+	if ids.IsZero() {
+		return ids, nil
+	}
+	if ids.GTIN == "" {
+		return ids, fmt.Errorf("%w: batch and serial numbers require a GTIN", ErrInvalidInput)
+	}
+
+	gtin, err := NormalizeGTIN(ids.GTIN)
+	if err != nil {
+		return ids, err
+	}
+	ids.GTIN = gtin
+
+	for name, value := range map[string]string{"batch number": ids.BatchNumber, "serial number": ids.SerialNumber} {
+		if value == "" {
+			continue
+		}
+		if len(value) > maxGS1ValueLength {
+			return ids, fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidInput, name, maxGS1ValueLength)
+		}
+		if strings.Trim(value, gs1CharacterSet82) != "" {
+			return ids, fmt.Errorf("%w: %s contains characters outside the GS1 character set", ErrInvalidInput, name)
+		}
+	}
+	return ids, nil
+}
+
+// NormalizeGTIN validates a GTIN-8, -12, -13 or -14 (length and check digit) and
+// returns it as the 14-digit form used in GS1 Digital Link URIs.
+func NormalizeGTIN(gtin string) (string, error) {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("%w: a GTIN has 8, 12, 13 or 14 digits", ErrInvalidInput)
+	}
+	for _, c := range gtin {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("%w: a GTIN only contains digits", ErrInvalidInput)
+		}
+	}
+
+	gtin = strings.Repeat("0", 14-len(gtin)) + gtin
+	if want := GS1CheckDigit(gtin[:13]); int(gtin[13]-'0') != want {
+		return "", fmt.Errorf("%w: invalid GTIN check digit (expected %d)", ErrInvalidInput, want)
+	}
+	return gtin, nil
+}
+
+// Bounds of a GS1 company prefix.
+const (
+	minCompanyPrefixLength = 4
+	maxCompanyPrefixLength = 12
+)
+
+// CompanyPrefixCandidates lists the GS1 company prefixes a normalized GTIN may have been
+// allocated under, longest first. The prefix follows the indicator digit of the 14-digit form.
+func CompanyPrefixCandidates(gtin string) []string {
+	if len(gtin) != 14 {
+		return nil
+	}
+	candidates := make([]string, 0, maxCompanyPrefixLength-minCompanyPrefixLength+1)
+	for n := maxCompanyPrefixLength; n >= minCompanyPrefixLength; n-- {
+		candidates = append(candidates, gtin[1:1+n])
+	}
+	return candidates
+}
+
+// GS1CheckDigit computes the mod-10 check digit of a numeric GS1 key without its check digit.
+func GS1CheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		// Weights alternate 3, 1, 3... starting from the rightmost digit
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"09506000134352", "09506000134352", false},
+		{"9506000134352", "09506000134352", false}, // GTIN-13
+		{"614141000036", "00614141000036", false},  // GTIN-12 (UPC-A)
+		{"96385074", "00000096385074", false},      // GTIN-8
+		{"09506000134353", "", true},               // wrong check digit
+		{"0950600013435", "", true},                // wrong length
+		{"0950600013435X", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizeGTIN(tt.in)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidInput), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProductIdentifiers_Normalize(t *testing.T) {
+	ids, err := ProductIdentifiers{GTIN: "9506000134352", SerialNumber: "ABC/123-x"}.Normalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "09506000134352", ids.GTIN)
+
+	for name, bad := range map[string]ProductIdentifiers{
+		"serial without gtin": {SerialNumber: "1"},
+		"serial too long":     {GTIN: "09506000134352", SerialNumber: "123456789012345678901"},
+		"lot with space":      {GTIN: "09506000134352", BatchNumber: "LOT 1"},
+		"non ascii serial":    {GTIN: "09506000134352", SerialNumber: "née"},
+	} {
+		_, err := bad.Normalize()
+		assert.True(t, errors.Is(err, ErrInvalidInput), name)
+	}
+}
+
+func TestCompanyPrefixCandidates(t *testing.T) {
+	candidates := CompanyPrefixCandidates("09506000134352")
+	assert.Len(t, candidates, 9)
+	assert.Equal(t, "950600013435", candidates[0])
+	assert.Equal(t, "9506", candidates[8])
+	assert.Nil(t, CompanyPrefixCandidates("9506000134352"))
+}
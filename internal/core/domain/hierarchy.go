@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PassportLevel is the granularity a passport describes. Batch and item passports may
+// reference a coarser parent and inherit its attributes.
+type PassportLevel string
+
+const (
+	LevelModel PassportLevel = "MODEL" // Product model: the data shared by every unit
+	LevelBatch PassportLevel = "BATCH" // Production batch of a model
+	LevelItem  PassportLevel = "ITEM"  // Single unit (default)
+)
+
+// CanInheritFrom reports whether a passport of this level may have a parent of the given level.
+func (l PassportLevel) CanInheritFrom(parent PassportLevel) bool {
+	switch l {
+	case LevelBatch:
+		return parent == LevelModel
+	case LevelItem:
+		return parent == LevelModel || parent == LevelBatch
+	}
+	return false
+}
+
+// PassportHierarchy places a new passport in the model/batch/item hierarchy.
+type PassportHierarchy struct {
+	Level    PassportLevel `json:"level,omitempty"` // Defaults to LevelItem
+	ParentID *uuid.UUID    `json:"parentId,omitempty"`
+}
+
+// Normalize defaults the level and checks it against the parent and the GS1 keys:
+// a model has neither parent nor batch/serial number, a batch has no serial number.
+func (h PassportHierarchy) Normalize(ids ProductIdentifiers) (PassportHierarchy, error) {
+	h.Level = PassportLevel(strings.ToUpper(strings.TrimSpace(string(h.Level))))
+	if h.Level == "" {
+		h.Level = LevelItem
+	}
+
+	switch h.Level {
+	case LevelModel:
+		if h.ParentID != nil {
+			return h, fmt.Errorf("%w: a model passport cannot have a parent", ErrInvalidInput)
+		}
+		if ids.BatchNumber != "" || ids.SerialNumber != "" {
+			return h, fmt.Errorf("%w: a model passport is identified by its GTIN only", ErrInvalidInput)
+		}
+	case LevelBatch:
+		if ids.SerialNumber != "" {
+			return h, fmt.Errorf("%w: a batch passport cannot have a serial number", ErrInvalidInput)
+		}
+	case LevelItem:
+	default:
+		return h, fmt.Errorf("%w: level must be MODEL, BATCH or ITEM", ErrInvalidInput)
+	}
+	return h, nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPassportHierarchy_Normalize(t *testing.T) {
+	parent := uuid.New()
+	gtin := ProductIdentifiers{GTIN: "09506000134352"}
+	batch := ProductIdentifiers{GTIN: "09506000134352", BatchNumber: "L1"}
+	item := ProductIdentifiers{GTIN: "09506000134352", SerialNumber: "S1"}
+
+	tests := []struct {
+		name      string
+		hierarchy PassportHierarchy
+		ids       ProductIdentifiers
+		wantLevel PassportLevel
+		wantErr   bool
+	}{
+		{"defaults to item", PassportHierarchy{}, item, LevelItem, false},
+		{"case insensitive", PassportHierarchy{Level: "model"}, gtin, LevelModel, false},
+		{"batch of a model", PassportHierarchy{Level: LevelBatch, ParentID: &parent}, batch, LevelBatch, false},
+		{"item of a batch", PassportHierarchy{ParentID: &parent}, item, LevelItem, false},
+		{"model with parent", PassportHierarchy{Level: LevelModel, ParentID: &parent}, gtin, "", true},
+		{"model with serial", PassportHierarchy{Level: LevelModel}, item, "", true},
+		{"model with batch", PassportHierarchy{Level: LevelModel}, batch, "", true},
+		{"batch with serial", PassportHierarchy{Level: LevelBatch}, item, "", true},
+		{"unknown level", PassportHierarchy{Level: "PALLET"}, gtin, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.hierarchy.Normalize(tt.ids)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidInput), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLevel, got.Level)
+		})
+	}
+}
+
+func TestPassportLevel_CanInheritFrom(t *testing.T) {
+	assert.True(t, LevelItem.CanInheritFrom(LevelModel))
+	assert.True(t, LevelItem.CanInheritFrom(LevelBatch))
+	assert.True(t, LevelBatch.CanInheritFrom(LevelModel))
+	assert.False(t, LevelBatch.CanInheritFrom(LevelBatch))
+	assert.False(t, LevelItem.CanInheritFrom(LevelItem))
+	assert.False(t, LevelModel.CanInheritFrom(LevelModel))
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ImportFormat is the encoding of a bulk import body.
+type ImportFormat string
+
+const (
+	ImportFormatNDJSON ImportFormat = "ndjson" // One ImportRecord per line
+	ImportFormatCSV    ImportFormat = "csv"    // Header row, then one passport per row (see ColumnMapping)
+)
+
+// Mapping targets for the GS1 keys of a CSV row. Every other target is a JSON pointer into the attributes.
+const (
+	ImportTargetGTIN   = "gtin"
+	ImportTargetLot    = "lot"
+	ImportTargetSerial = "serial"
+)
+
+// ImportRecord is one NDJSON line: the GS1 keys of the passport and its attributes.
+type ImportRecord struct {
+	GTIN         string          `json:"gtin,omitempty"`
+	BatchNumber  string          `json:"lot,omitempty"`
+	SerialNumber string          `json:"serial,omitempty"`
+	Attributes   json.RawMessage `json:"attributes"`
+}
+
+// ColumnType is the JSON type a CSV cell is converted to.
+type ColumnType string
+
+const (
+	ColumnString  ColumnType = "string"
+	ColumnNumber  ColumnType = "number"
+	ColumnInteger ColumnType = "integer"
+	ColumnBoolean ColumnType = "boolean"
+	ColumnJSON    ColumnType = "json" // The cell holds a JSON value (e.g. an array)
+)
+
+// ColumnTarget is where a CSV column lands: a GS1 key, or a JSON pointer with a type.
+type ColumnTarget struct {
+	Pointer string     `json:"pointer"` // "gtin", "lot", "serial" or a JSON pointer such as "/origin/country"
+	Type    ColumnType `json:"type"`
+}
+
+// MaxImportArrayIndex bounds the numeric tokens of a mapping pointer: arrays are filled up to
+// the index, so a larger one would let a single mapping allocate without limit.
+const MaxImportArrayIndex = 99
+
+// ColumnMapping maps CSV header names to their target. Unmapped columns are ignored.
+type ColumnMapping map[string]ColumnTarget
+
+// ParseColumnTarget parses a mapping target written as "<pointer>[:<type>]",
+// e.g. "/fiberComposition/0/percentage:number". The type defaults to string.
+func ParseColumnTarget(s string) (ColumnTarget, error) {
+	pointer, typ, _ := strings.Cut(strings.TrimSpace(s), ":")
+	target := ColumnTarget{Pointer: pointer, Type: ColumnType(typ)}
+	if target.Type == "" {
+		target.Type = ColumnString
+	}
+
+	switch target.Pointer {
+	case ImportTargetGTIN, ImportTargetLot, ImportTargetSerial:
+		if target.Type != ColumnString {
+			return target, fmt.Errorf("%w: %s is always a string", ErrInvalidInput, target.Pointer)
+		}
+		return target, nil
+	}
+	if !strings.HasPrefix(target.Pointer, "/") || len(target.Pointer) < 2 {
+		return target, fmt.Errorf("%w: mapping target %q must be gtin, lot, serial or a JSON pointer", ErrInvalidInput, s)
+	}
+	for _, token := range strings.Split(target.Pointer[1:], "/") {
+		if index, err := strconv.Atoi(token); err == nil && index > MaxImportArrayIndex {
+			return target, fmt.Errorf("%w: mapping target %q: array index %s is above %d", ErrInvalidInput, s, token, MaxImportArrayIndex)
+		}
+	}
+	switch target.Type {
+	case ColumnString, ColumnNumber, ColumnInteger, ColumnBoolean, ColumnJSON:
+	default:
+		return target, fmt.Errorf("%w: unknown column type %q", ErrInvalidInput, typ)
+	}
+	return target, nil
+}
+
+// ImportRequest describes a bulk import. All records share the category and hierarchy.
+type ImportRequest struct {
+	Category  ProductCategory   `json:"category"`
+	Format    ImportFormat      `json:"format"`
+	Mapping   ColumnMapping     `json:"mapping,omitempty"` // CSV only
+	Hierarchy PassportHierarchy `json:"hierarchy"`
+
+	// Progress, if set, is called after each written batch with the number of lines read so far.
+	Progress func(lines int) `json:"-"`
+}
+
+// ImportLineResult is the outcome of one record. Line numbers count from 1 and include the CSV header.
+type ImportLineResult struct {
+	Line       int        `json:"line"`
+	PassportID *uuid.UUID `json:"passportId,omitempty"`
+	Duplicate  bool       `json:"duplicate,omitempty"` // Same record already imported (idempotency hit)
+	Error      string     `json:"error,omitempty"`
+}
+
+// ImportReport is the per-line outcome of a bulk import.
+type ImportReport struct {
+	Accepted   int                `json:"accepted"`
+	Duplicates int                `json:"duplicates"`
+	Rejected   int                `json:"rejected"`
+	Lines      []ImportLineResult `json:"lines"`
+}
+
+// Add records a line result and updates the counters.
+func (r *ImportReport) Add(line ImportLineResult) {
+	switch {
+	case line.Error != "":
+		r.Rejected++
+	case line.Duplicate:
+		r.Duplicates++
+	default:
+		r.Accepted++
+	}
+	r.Lines = append(r.Lines, line)
+}
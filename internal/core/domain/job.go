@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobType selects the worker handler of a job.
+type JobType string
+
+const (
+	JobTypeImport        JobType = "passport_import"  // Bulk import (params: ImportJobParams, input: the NDJSON/CSV body)
+	JobTypePublish       JobType = "passport_publish" // Bulk publish (params: BulkPublishRequest, no input)
+	JobTypeSearchReindex JobType = "search_reindex"   // Rebuild the full-text search documents (no params, no input)
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "QUEUED"
+	JobRunning   JobStatus = "RUNNING"
+	JobSucceeded JobStatus = "SUCCEEDED"
+	JobFailed    JobStatus = "FAILED"
+	JobCancelled JobStatus = "CANCELLED"
+)
+
+// IsFinal reports whether the job will not run again (unless retried).
+func (s JobStatus) IsFinal() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCancelled
+}
+
+// Job is a long-running operation executed by cmd/api-worker. The input (e.g. an uploaded
+// file) is stored with the job but never returned by the API.
+type Job struct {
+	ID              uuid.UUID       `json:"id" db:"id"`
+	Type            JobType         `json:"type" db:"type"`
+	TenantID        string          `json:"tenantId" db:"tenant_id"`
+	Status          JobStatus       `json:"status" db:"status"`
+	Params          json.RawMessage `json:"params,omitempty" db:"params"`
+	Progress        JobProgress     `json:"progress"`
+	Error           string          `json:"error,omitempty" db:"error"`
+	Attempts        int             `json:"attempts" db:"attempts"`
+	MaxAttempts     int             `json:"maxAttempts" db:"max_attempts"`
+	CancelRequested bool            `json:"cancelRequested,omitempty" db:"cancel_requested"`
+	RunAfter        time.Time       `json:"runAfter" db:"run_after"` // Backoff between attempts
+	CreatedAt       time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updatedAt" db:"updated_at"`
+	StartedAt       *time.Time      `json:"startedAt,omitempty" db:"started_at"`
+	FinishedAt      *time.Time      `json:"finishedAt,omitempty" db:"finished_at"`
+}
+
+// JobProgress counts the units of work (e.g. input lines) of a job. Total is 0 when unknown.
+type JobProgress struct {
+	Done  int `json:"done" db:"progress_done"`
+	Total int `json:"total" db:"progress_total"`
+}
+
+// ImportJobParams are the parameters of a JobTypeImport job.
+type ImportJobParams struct {
+	ManufacturerName string        `json:"manufacturerName"`
+	Request          ImportRequest `json:"request"`
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LinkType is a GS1 Web Vocabulary link relation in compact form (e.g. "gs1:pip").
+type LinkType string
+
+const (
+	LinkTypeDefault            LinkType = "gs1:defaultLink"        // The passport page served by the resolver itself
+	LinkTypePIP                LinkType = "gs1:pip"                // Product information page
+	LinkTypeSustainabilityInfo LinkType = "gs1:sustainabilityInfo" // Sustainability and recycling information
+	LinkTypeInstructions       LinkType = "gs1:instructions"       // Instructions for use, repair or disassembly
+
+	// LinkTypeAll asks the resolver for the whole linkset instead of a redirect.
+	LinkTypeAll LinkType = "all"
+)
+
+// GS1VocabularyURI is the namespace the "gs1:" prefix stands for.
+const GS1VocabularyURI = "https://gs1.org/voc/"
+
+var linkTypeTerm = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+
+// ParseLinkType accepts the compact ("gs1:pip") or full ("https://gs1.org/voc/pip") form
+// of a GS1 link type and returns the compact form.
+func ParseLinkType(s string) (LinkType, error) {
+	s = strings.TrimSpace(s)
+	term := strings.TrimPrefix(strings.TrimPrefix(s, GS1VocabularyURI), "gs1:")
+	if term == s || !linkTypeTerm.MatchString(term) {
+		return "", fmt.Errorf("%w: %q is not a GS1 link type", ErrInvalidInput, s)
+	}
+	return LinkType("gs1:" + term), nil
+}
+
+// URI returns the link relation type used in linksets and Link headers.
+func (t LinkType) URI() string {
+	return GS1VocabularyURI + strings.TrimPrefix(string(t), "gs1:")
+}
+
+// PassportLink is a resolver destination registered by a manufacturer, either for one
+// passport (and its revisions) or for all of its passports.
+type PassportLink struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	ManufacturerID string     `json:"manufacturerId" db:"manufacturer_id"`
+	PassportID     *uuid.UUID `json:"passportId,omitempty" db:"passport_id"` // Nil: every passport of the manufacturer
+	LinkType       LinkType   `json:"linkType" db:"link_type"`
+	Href           string     `json:"href" db:"href"`
+	Title          string     `json:"title" db:"title"`
+	Language       string     `json:"language,omitempty" db:"language"`    // BCP 47 tag; empty means any language
+	MediaType      string     `json:"mediaType,omitempty" db:"media_type"` // e.g. text/html, application/pdf
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// LinkInput is the input for registering a link.
+type LinkInput struct {
+	PassportID *uuid.UUID `json:"passportId,omitempty"`
+	LinkType   string     `json:"linkType"`
+	Href       string     `json:"href"`
+	Title      string     `json:"title"`
+	Language   string     `json:"language,omitempty"`
+	MediaType  string     `json:"mediaType,omitempty"`
+}
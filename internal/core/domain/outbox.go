@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is an event stored in the transactional outbox. It is written in the same
+// transaction as the passport change it announces, then relayed to the event bus: a committed
+// change always produces its event, and a rolled back one never does.
+type OutboxEvent struct {
+	ID            uuid.UUID
+	PassportID    uuid.UUID // Events of one passport are relayed in the order they were written
+	Channel       string    // Event bus channel, e.g. "events:passport_created"
+	Payload       json.RawMessage
+	Attempts      int       // Failed relay attempts so far
+	NextAttemptAt time.Time // Not relayed before
+	LastError     string
+	CreatedAt     time.Time
+}
+
+// RecordEvent attaches a lifecycle event describing the passport as it is now. The repository
+// writes it to the outbox together with the passport's next change.
+func (p *Passport) RecordEvent(eventType EventType) error {
+	now := time.Now().UTC()
+	event, err := NewPassportEvent(eventType, p, now)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	p.Events = append(p.Events, OutboxEvent{
+		ID:            event.ID,
+		PassportID:    p.ID,
+		Channel:       eventType.Channel(),
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+	return nil
+}
@@ -30,9 +30,10 @@ const (
 type PassportStatus string
 
 const (
-	StatusDraft     PassportStatus = "DRAFT"     // Manufacturer is still editing
-	StatusPublished PassportStatus = "PUBLISHED" // Locked and live on the blockchain/S3
-	StatusRevoked   PassportStatus = "REVOKED"   // Recalled or erroneous
+	StatusDraft      PassportStatus = "DRAFT"      // Manufacturer is still editing
+	StatusPublished  PassportStatus = "PUBLISHED"  // Locked and live on the blockchain/S3
+	StatusRevoked    PassportStatus = "REVOKED"    // Recalled or erroneous
+	StatusSuperseded PassportStatus = "SUPERSEDED" // Replaced by a newer published revision
 )
 
 type ContextKey string
@@ -40,6 +41,7 @@ type ContextKey string
 const (
 	ViewContextKey        ContextKey = "view_context"
 	ViewerTenantIDKey     ContextKey = "viewer_tenant_id"
+	ViewerAccessLevelKey  ContextKey = "viewer_access_level" // AccessLevel claimed by the viewer's credential
 	ViewContextRestricted string     = "restricted"
 	ViewContextPublic     string     = "public"
 )
@@ -55,16 +57,73 @@ type Passport struct {
 	ManufacturerID   string `json:"manufacturerId" db:"manufacturer_id"` // e.g., DUNS number or Internal Tenant ID
 	ManufacturerName string `json:"manufacturerName" db:"manufacturer_name"`
 
+	// Recorded from the tenant profile when the passport is published (master envelope)
+	ManufacturerDUNS    string `json:"manufacturerDuns,omitempty" db:"manufacturer_duns"`       // 9-digit D-U-N-S Number
+	ManufacturerCountry string `json:"manufacturerCountry,omitempty" db:"manufacturer_country"` // ISO 3166-1 alpha-2
+
+	// GS1 Keys (GS1 Digital Link). Set at creation and shared by every revision.
+	GTIN         string `json:"gtin,omitempty" db:"gtin"`                  // GTIN-14
+	BatchNumber  string `json:"batchNumber,omitempty" db:"batch_number"`   // Batch/lot, AI (10)
+	SerialNumber string `json:"serialNumber,omitempty" db:"serial_number"` // AI (21)
+
+	// Hierarchy. A batch or item references a PUBLISHED coarser passport and inherits its
+	// attributes (JSON Merge Patch): only its own fields are stored, GetPassport returns the merged view.
+	Level    PassportLevel `json:"level,omitempty" db:"level"`
+	ParentID *uuid.UUID    `json:"parentId,omitempty" db:"parent_id"`
+
 	// The "Payload" is stored as raw JSONB in Postgres.
 	// We do not unmarshal it until we know the Category.
 	Attributes json.RawMessage `json:"attributes" db:"attributes"`
 
+	// SchemaVersion is the category schema version the Attributes were validated against.
+	// Empty for passports created before the schema registry (the embedded bootstrap version).
+	SchemaVersion string `json:"schemaVersion,omitempty" db:"schema_version"`
+
 	// Metadata
 	CreatedAt        time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt        time.Time  `json:"updatedAt" db:"updated_at"`
 	PublishedAt      *time.Time `json:"publishedAt,omitempty" db:"published_at"`
-	ImmutabilityHash string     `json:"immutabilityHash,omitempty" db:"immutability_hash"` // SHA-256 of the Attributes when Published
-	StorageLocation  string     `json:"storageLocation,omitempty" db:"storage_location"`   // S3 URL
+	ImmutabilityHash string     `json:"immutabilityHash,omitempty" db:"immutability_hash"` // SHA-256 of the published envelope
+	StorageLocation  string     `json:"storageLocation,omitempty" db:"storage_location"`   // S3 URL of the published envelope
+
+	// Revisions (Supersede Chain)
+	// A published passport is never edited in place: a new DRAFT revision is cloned from it,
+	// and publishing that revision marks the previous one as SUPERSEDED.
+	Version           int        `json:"version" db:"version"`
+	PreviousVersionID *uuid.UUID `json:"previousVersionId,omitempty" db:"previous_version_id"`
+	SupersededByID    *uuid.UUID `json:"supersededBy,omitempty" db:"superseded_by"`
+
+	// Revocation is only set once the passport has been REVOKED (recall, erroneous data).
+	// The record itself is kept as a public tombstone so old QR codes keep resolving.
+	Revocation *Revocation `json:"revocation,omitempty"`
+
+	// Events recorded with the pending change, written to the outbox in the same transaction
+	// as the passport (cleared once committed). See RecordEvent.
+	Events []OutboxEvent `json:"-"`
+
+	// SearchDocuments replace the passport's full-text search documents with the pending
+	// change (nil leaves them unchanged).
+	SearchDocuments []SearchDocument `json:"-"`
+}
+
+// Identifiers returns the GS1 keys of the passport.
+func (p *Passport) Identifiers() ProductIdentifiers {
+	return ProductIdentifiers{GTIN: p.GTIN, BatchNumber: p.BatchNumber, SerialNumber: p.SerialNumber}
+}
+
+// Revocation explains why and when a published passport was withdrawn.
+type Revocation struct {
+	Reason    string    `json:"reason" db:"revocation_reason"`
+	RevokedAt time.Time `json:"revokedAt" db:"revoked_at"`
+}
+
+// PassportVersion is one entry of a passport's revision history.
+type PassportVersion struct {
+	PassportID       uuid.UUID      `json:"passportId"`
+	Version          int            `json:"version"`
+	Status           PassportStatus `json:"status"`
+	PublishedAt      *time.Time     `json:"publishedAt,omitempty"`
+	ImmutabilityHash string         `json:"immutabilityHash,omitempty"`
 }
 
 // --- The Polymorphic Payloads ---
@@ -112,6 +171,51 @@ type CareInstructions struct {
 	Drying  string `json:"drying"`
 }
 
+// ElectronicAttributes maps to the ESPR requirements for consumer electronics.
+type ElectronicAttributes struct {
+	ProductModel           string                 `json:"productModel"`
+	Brand                  string                 `json:"brand"`
+	SerialNumber           string                 `json:"serialNumber"`
+	DeviceType             string                 `json:"deviceType"` // e.g., "SMARTPHONE", "LAPTOP"
+	Repairability          Repairability          `json:"repairability"`
+	SparePartsAvailability SparePartsAvailability `json:"sparePartsAvailability"`
+	SoftwareSupport        SoftwareSupport        `json:"softwareSupport"`
+	EnergyClass            string                 `json:"energyClass"` // "A" to "G"
+	HazardousSubstances    []HazardousSubstance   `json:"hazardousSubstances"`
+}
+
+type Repairability struct {
+	Score             float64 `json:"repairabilityScore"` // 0-10
+	Class             string  `json:"repairabilityClass"` // "A" to "E"
+	DisassemblySteps  int     `json:"disassemblySteps"`
+	FastenersReusable bool    `json:"fastenersReusable"`
+}
+
+type SparePartsAvailability struct {
+	MinimumYears    int         `json:"minimumYears"`
+	AvailableUntil  string      `json:"availableUntil"` // ISO date
+	MaxDeliveryDays int         `json:"maxDeliveryDays"`
+	Parts           []SparePart `json:"parts"`
+}
+
+type SparePart struct {
+	Name            string `json:"partName"` // "Battery", "Display"
+	UserReplaceable bool   `json:"userReplaceable"`
+}
+
+type SoftwareSupport struct {
+	MinimumYears         int    `json:"minimumYears"`
+	OSUpdatesUntil       string `json:"osUpdatesUntil"`       // ISO date
+	SecurityUpdatesUntil string `json:"securityUpdatesUntil"` // ISO date
+}
+
+type HazardousSubstance struct {
+	Substance     string  `json:"substance"` // "Lead", "DEHP"
+	CASNumber     string  `json:"casNumber"`
+	Concentration float64 `json:"concentration"` // % w/w
+	Location      string  `json:"location"`
+}
+
 // --- Validation Logic ---
 
 // GetBatteryAttributes safely unmarshals the raw JSONB into the struct.
@@ -126,6 +230,18 @@ func (p *Passport) GetBatteryAttributes() (*BatteryAttributes, error) {
 	return &attrs, nil
 }
 
+// GetElectronicAttributes safely unmarshals the raw JSONB into the struct.
+func (p *Passport) GetElectronicAttributes() (*ElectronicAttributes, error) {
+	if p.ProductCategory != CategoryElectronic {
+		return nil, errors.New("passport is not a consumer electronic")
+	}
+	var attrs ElectronicAttributes
+	if err := json.Unmarshal(p.Attributes, &attrs); err != nil {
+		return nil, err
+	}
+	return &attrs, nil
+}
+
 // Validate ensures the generic passport fields are correct.
 // (Detailed schema validation happens at the Service layer using JSON Schema).
 func (p *Passport) Validate() error {
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Page sizes of a passport list.
+const (
+	DefaultPassportPageSize = 100
+	MaxPassportPageSize     = 1000
+)
+
+// PassportSort is the order of a passport list. The ID breaks ties, so pages never overlap.
+type PassportSort string
+
+const (
+	SortCreatedAt    PassportSort = "createdAt"
+	SortUpdatedAt    PassportSort = "updatedAt"
+	SortPublishedAt  PassportSort = "publishedAt" // Drafts (not yet published) come first
+	SortSerialNumber PassportSort = "serialNumber"
+)
+
+func (s PassportSort) IsValid() bool {
+	switch s {
+	case SortCreatedAt, SortUpdatedAt, SortPublishedAt, SortSerialNumber:
+		return true
+	}
+	return false
+}
+
+// Key returns the sort key of a passport, as compared by the database: timestamps in RFC 3339,
+// "-infinity" for a passport never published, "" for one without serial number.
+func (s PassportSort) Key(p *Passport) string {
+	switch s {
+	case SortUpdatedAt:
+		return p.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case SortPublishedAt:
+		if p.PublishedAt == nil {
+			return "-infinity"
+		}
+		return p.PublishedAt.UTC().Format(time.RFC3339Nano)
+	case SortSerialNumber:
+		return p.SerialNumber
+	default:
+		return p.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// PassportFilter narrows a manufacturer's passport list; empty fields do not filter. Date
+// ranges include From and exclude To.
+type PassportFilter struct {
+	Status          PassportStatus
+	ProductCategory ProductCategory
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	PublishedFrom   *time.Time
+	PublishedTo     *time.Time
+	SerialNumber    string
+	Attributes      SearchExpr // Attribute search (see ParseSearchQuery)
+}
+
+// PassportQuery asks for one page of a manufacturer's passports.
+type PassportQuery struct {
+	Filter     PassportFilter
+	Sort       PassportSort
+	Descending bool
+	Limit      int
+	After      *PassportCursor // Continues after the last passport of the previous page
+}
+
+// ParsePassportSort reads a sort parameter: a field, descending when prefixed with "-".
+// Empty means newest first.
+func ParsePassportSort(s string) (PassportSort, bool, error) {
+	if s == "" {
+		return SortCreatedAt, true, nil
+	}
+	sort := PassportSort(strings.TrimPrefix(s, "-"))
+	if !sort.IsValid() {
+		return "", false, fmt.Errorf("%w: unknown sort %q", ErrInvalidInput, s)
+	}
+	return sort, strings.HasPrefix(s, "-"), nil
+}
+
+// Order returns the sort as a parameter ("-createdAt").
+func (q PassportQuery) Order() string {
+	if q.Descending {
+		return "-" + string(q.Sort)
+	}
+	return string(q.Sort)
+}
+
+// PassportCursor is the position of the last passport of a page. It is only valid for the order
+// it was issued for.
+type PassportCursor struct {
+	Order string    `json:"o"`
+	Key   string    `json:"k"`
+	ID    uuid.UUID `json:"i"`
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c PassportCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodePassportCursor reads a token returned by Encode.
+func DecodePassportCursor(token string) (*PassportCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	var c PassportCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	sort, _, err := ParsePassportSort(c.Order)
+	if err != nil || c.Order == "" {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	if sort != SortSerialNumber && c.Key != "-infinity" {
+		if _, err := time.Parse(time.RFC3339Nano, c.Key); err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+		}
+	}
+	return &c, nil
+}
+
+// PassportPage is one page of a passport list.
+type PassportPage struct {
+	Passports []*Passport
+	Next      *PassportCursor // nil on the last page
+}
+
+// PassportSearchRequest is an attribute search over a manufacturer's passports (POST /passports/search).
+type PassportSearchRequest struct {
+	Query    string          `json:"query"` // See ParseSearchQuery
+	Status   PassportStatus  `json:"status,omitempty"`
+	Category ProductCategory `json:"category,omitempty"`
+	Sort     string          `json:"sort,omitempty"`
+	Limit    int             `json:"limit,omitempty"`
+	Cursor   string          `json:"cursor,omitempty"` // nextCursor of the previous page
+}
+
+// PassportQuery parses the search into a passport list query.
+func (r PassportSearchRequest) PassportQuery() (PassportQuery, error) {
+	var q PassportQuery
+	var err error
+
+	if q.Filter.Attributes, err = ParseSearchQuery(r.Query); err != nil {
+		return q, err
+	}
+	q.Filter.Status = PassportStatus(strings.ToUpper(string(r.Status)))
+	q.Filter.ProductCategory = r.Category
+	if q.Sort, q.Descending, err = ParsePassportSort(r.Sort); err != nil {
+		return q, err
+	}
+	q.Limit = r.Limit
+	if r.Cursor != "" {
+		if q.After, err = DecodePassportCursor(r.Cursor); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
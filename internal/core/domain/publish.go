@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxBulkPublishIDs caps the explicit ID list of a bulk publish.
+const MaxBulkPublishIDs = 10000
+
+// BulkPublishRequest selects the passports to publish: an explicit ID list, or a filter over
+// the manufacturer's drafts (e.g. every draft in a category created before a date).
+type BulkPublishRequest struct {
+	IDs           []uuid.UUID     `json:"ids,omitempty"`
+	Category      ProductCategory `json:"category,omitempty"`
+	CreatedBefore *time.Time      `json:"createdBefore,omitempty"`
+
+	// Progress, if set, is called after each committed batch with the passports processed so far.
+	Progress func(done, total int) `json:"-"`
+}
+
+// Validate checks that exactly one selection (IDs or filter) is given.
+func (r BulkPublishRequest) Validate() error {
+	hasFilter := r.Category != "" || r.CreatedBefore != nil
+	switch {
+	case len(r.IDs) > 0 && hasFilter:
+		return fmt.Errorf("%w: give either ids or a filter (category, createdBefore), not both", ErrInvalidInput)
+	case len(r.IDs) == 0 && !hasFilter:
+		return fmt.Errorf("%w: give ids or a filter (category, createdBefore)", ErrInvalidInput)
+	case len(r.IDs) > MaxBulkPublishIDs:
+		return fmt.Errorf("%w: at most %d ids per request", ErrInvalidInput, MaxBulkPublishIDs)
+	}
+	return nil
+}
+
+// PublishOutcome is what happened to one passport of a bulk publish.
+type PublishOutcome string
+
+const (
+	PublishPublished PublishOutcome = "published"
+	PublishSkipped   PublishOutcome = "skipped" // Already published (e.g. by an earlier attempt)
+	PublishFailed    PublishOutcome = "failed"  // Retrying the operation picks it up again
+)
+
+// PublishResult is the outcome of one passport.
+type PublishResult struct {
+	PassportID       uuid.UUID      `json:"passportId"`
+	Outcome          PublishOutcome `json:"outcome"`
+	ImmutabilityHash string         `json:"immutabilityHash,omitempty"`
+	Error            string         `json:"error,omitempty"`
+}
+
+// PublishReport is the per-passport outcome of a bulk publish.
+type PublishReport struct {
+	Published int             `json:"published"`
+	Skipped   int             `json:"skipped"`
+	Failed    int             `json:"failed"`
+	Passports []PublishResult `json:"passports"`
+}
+
+// Add records a passport result and updates the counters.
+func (r *PublishReport) Add(result PublishResult) {
+	switch result.Outcome {
+	case PublishPublished:
+		r.Published++
+	case PublishSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Passports = append(r.Passports, result)
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScanSource is the resolver endpoint a scan hit.
+type ScanSource string
+
+const (
+	ScanSourcePassport ScanSource = "passport" // The passport page (short URL or GS1 Digital Link)
+	ScanSourceQRCode   ScanSource = "qr_code"  // The QR code image
+)
+
+// AgentClass is a coarse class of user agent: the raw header is never stored.
+type AgentClass string
+
+const (
+	AgentMobile  AgentClass = "mobile"
+	AgentDesktop AgentClass = "desktop"
+	AgentBot     AgentClass = "bot" // Crawlers, link previews, command-line clients
+	AgentApp     AgentClass = "app" // Any other client identifying itself
+	AgentUnknown AgentClass = "unknown"
+)
+
+// ClassifyUserAgent maps a User-Agent header to its AgentClass.
+func ClassifyUserAgent(userAgent string) AgentClass {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return AgentUnknown
+	case containsAny(ua, "bot", "crawl", "spider", "slurp", "preview", "curl", "wget", "python", "go-http-client", "okhttp", "headless"):
+		return AgentBot
+	case containsAny(ua, "mobile", "android", "iphone", "ipad"):
+		return AgentMobile
+	case containsAny(ua, "mozilla", "opera"):
+		return AgentDesktop
+	}
+	return AgentApp
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanCountry normalizes a country header (ISO 3166-1 alpha-2, e.g. "DE"); anything else is "".
+func ScanCountry(header string) string {
+	country := strings.ToUpper(strings.TrimSpace(header))
+	if len(country) != 2 || country[0] < 'A' || country[0] > 'Z' || country[1] < 'A' || country[1] > 'Z' {
+		return ""
+	}
+	return country
+}
+
+// ScanReferrer keeps the host of a Referer header ("" if missing or invalid): paths and query
+// strings may carry personal data.
+func ScanReferrer(header string) string {
+	u, err := url.Parse(header)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if len(host) > 255 || net.ParseIP(host) != nil {
+		return ""
+	}
+	return host
+}
+
+// ScanEvent is one resolver hit. It carries no IP address nor raw header.
+type ScanEvent struct {
+	PassportID uuid.UUID
+	Source     ScanSource
+	AgentClass AgentClass
+	Country    string // ISO 3166-1 alpha-2, "" when unknown
+	Referrer   string // Host only, "" when none
+	AccessTier string // Access level of the viewer's credential ("public" for anonymous viewers)
+	OccurredAt time.Time
+}
+
+// ScanBucketSize is the time bucket of scan analytics.
+type ScanBucketSize string
+
+const (
+	ScanBucketDay   ScanBucketSize = "day"
+	ScanBucketWeek  ScanBucketSize = "week" // ISO weeks, starting on Monday
+	ScanBucketMonth ScanBucketSize = "month"
+)
+
+// ScanDimension breaks scan counts down by one attribute of the scans.
+type ScanDimension string
+
+const (
+	ScanBySource     ScanDimension = "source"
+	ScanByAgentClass ScanDimension = "agentClass"
+	ScanByCountry    ScanDimension = "country"
+	ScanByReferrer   ScanDimension = "referrer"
+	ScanByAccessTier ScanDimension = "accessTier"
+)
+
+// MaxScanRange bounds the period of a scan analytics query.
+const MaxScanRange = 2 * 366 * 24 * time.Hour
+
+// ScanQuery asks for a tenant's scan counts over [From, To) (UTC days), per bucket.
+type ScanQuery struct {
+	From       time.Time
+	To         time.Time
+	Bucket     ScanBucketSize
+	GroupBy    ScanDimension // Optional; not available per passport
+	PassportID *uuid.UUID    // Optional: one passport's scans
+}
+
+// Validate checks the query once defaults are applied. Errors wrap ErrInvalidInput.
+func (q ScanQuery) Validate() error {
+	switch q.Bucket {
+	case ScanBucketDay, ScanBucketWeek, ScanBucketMonth:
+	default:
+		return fmt.Errorf("%w: unknown bucket %q", ErrInvalidInput, q.Bucket)
+	}
+	switch q.GroupBy {
+	case "", ScanBySource, ScanByAgentClass, ScanByCountry, ScanByReferrer, ScanByAccessTier:
+	default:
+		return fmt.Errorf("%w: unknown groupBy %q", ErrInvalidInput, q.GroupBy)
+	}
+	if q.GroupBy != "" && q.PassportID != nil {
+		return fmt.Errorf("%w: groupBy is not available for a single passport", ErrInvalidInput)
+	}
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidInput)
+	}
+	if q.To.Sub(q.From) > MaxScanRange {
+		return fmt.Errorf("%w: period longer than two years", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ScanCount is the number of scans of one bucket (and group).
+type ScanCount struct {
+	Start time.Time `json:"start"`
+	Group string    `json:"group,omitempty"` // Value of the groupBy dimension (omitted when unknown)
+	Scans int64     `json:"scans"`
+}
+
+// ScanReport answers a ScanQuery. Buckets without scans are omitted.
+type ScanReport struct {
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Bucket     ScanBucketSize `json:"bucket"`
+	GroupBy    ScanDimension  `json:"groupBy,omitempty"`
+	PassportID *uuid.UUID     `json:"passportId,omitempty"`
+	Counts     []ScanCount    `json:"counts"`
+}
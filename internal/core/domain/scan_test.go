@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyUserAgent(t *testing.T) {
+	tests := map[string]AgentClass{
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148":     AgentMobile,
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36": AgentMobile,
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36":       AgentDesktop,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)":                      AgentBot,
+		"WhatsApp/2.23 link preview": AgentBot,
+		"curl/8.4.0":                 AgentBot,
+		"RecyclerScanner/3.1 (iOS)":  AgentApp,
+		"":                           AgentUnknown,
+	}
+	for userAgent, want := range tests {
+		assert.Equal(t, want, ClassifyUserAgent(userAgent), userAgent)
+	}
+}
+
+func TestScanCountryAndReferrer(t *testing.T) {
+	assert.Equal(t, "DE", ScanCountry(" de "))
+	assert.Equal(t, "", ScanCountry("XX1"))
+	assert.Equal(t, "", ScanCountry("D1"))
+
+	// Only the host survives; IP addresses are dropped
+	assert.Equal(t, "shop.example.com", ScanReferrer("https://Shop.Example.com/orders/42?email=a@b.c"))
+	assert.Equal(t, "", ScanReferrer("http://192.168.1.10/page"))
+	assert.Equal(t, "", ScanReferrer("android-app://com.example"))
+	assert.Equal(t, "", ScanReferrer(""))
+}
+
+func TestScanQuery_Validate(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := uuid.New()
+	valid := ScanQuery{From: from, To: from.AddDate(0, 1, 0), Bucket: ScanBucketWeek, GroupBy: ScanByCountry}
+	assert.NoError(t, valid.Validate())
+
+	invalid := map[string]ScanQuery{
+		"unknown bucket":        {From: from, To: from.AddDate(0, 1, 0), Bucket: "hour"},
+		"unknown dimension":     {From: from, To: from.AddDate(0, 1, 0), Bucket: ScanBucketDay, GroupBy: "ip"},
+		"grouped passport":      {From: from, To: from.AddDate(0, 1, 0), Bucket: ScanBucketDay, GroupBy: ScanBySource, PassportID: &id},
+		"empty period":          {From: from, To: from, Bucket: ScanBucketDay},
+		"period over two years": {From: from, To: from.AddDate(3, 0, 0), Bucket: ScanBucketMonth},
+	}
+	for name, q := range invalid {
+		assert.ErrorIs(t, q.Validate(), ErrInvalidInput, name)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// CategorySchema is one published version of a product category payload schema.
+// Only one version per category is active (used for new passports) at a time;
+// older versions are kept so existing passports can still be filtered and re-validated.
+type CategorySchema struct {
+	Category    ProductCategory `json:"productCategory" db:"product_category"`
+	Version     string          `json:"version" db:"version"` // Semantic version, e.g. "1.2.0"
+	Schema      json.RawMessage `json:"schema" db:"schema"`
+	Active      bool            `json:"active" db:"active"`
+	BuiltIn     bool            `json:"builtIn,omitempty"` // Embedded bootstrap schema (not stored in the registry)
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+	ActivatedAt *time.Time      `json:"activatedAt,omitempty" db:"activated_at"`
+}
+
+var semVerPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)$`)
+
+// IsValidSchemaVersion reports whether v is a plain MAJOR.MINOR.PATCH version.
+func IsValidSchemaVersion(v string) bool {
+	return semVerPattern.MatchString(v)
+}
+
+// CompareSchemaVersions returns -1, 0 or 1 depending on whether a is lower, equal or greater than b.
+// Both versions must be valid (see IsValidSchemaVersion).
+func CompareSchemaVersions(a, b string) int {
+	pa, pb := semVerPattern.FindStringSubmatch(a), semVerPattern.FindStringSubmatch(b)
+	for i := 1; i <= 3; i++ {
+		x, _ := strconv.Atoi(pa[i])
+		y, _ := strconv.Atoi(pb[i])
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
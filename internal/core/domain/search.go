@@ -0,0 +1,317 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Bounds of a search query.
+const (
+	MaxSearchQueryLength = 2000
+	MaxSearchComparisons = 32
+)
+
+// SearchExpr is a parsed attribute search: SearchAnd, SearchOr, SearchNot or SearchComparison.
+//
+// The query language compares attribute paths with values, combined with AND, OR, NOT and
+// parentheses (AND binds tighter than OR):
+//
+//	chemistry = LITHIUM_ION AND carbonFootprint.totalCarbonFootprint < 60
+//	NOT (recycledContent.cobalt >= 10 OR manufacturer.country = "CN")
+//
+// Values are numbers, true, false, null, double-quoted strings, or bare words taken as strings.
+//
+// A comparison only matches passports that have the attribute, whatever the operator: neither
+// "chemistry != LEAD_ACID" nor "NOT chemistry = LEAD_ACID" matches a passport without a chemistry,
+// just as "weight < 10" and "weight >= 10" don't match one without a weight.
+type SearchExpr interface {
+	searchExpr()
+}
+
+type SearchAnd []SearchExpr
+
+type SearchOr []SearchExpr
+
+type SearchNot struct {
+	Expr SearchExpr
+}
+
+// SearchOp is a comparison operator. = and != compare any value; the others compare numbers
+// with numbers and strings with strings, and never match an attribute of another type.
+type SearchOp string
+
+const (
+	SearchEq SearchOp = "="
+	SearchNe SearchOp = "!="
+	SearchLt SearchOp = "<"
+	SearchLe SearchOp = "<="
+	SearchGt SearchOp = ">"
+	SearchGe SearchOp = ">="
+)
+
+// SearchComparison compares the attribute at Path with Value.
+type SearchComparison struct {
+	Path  []string // e.g. ["carbonFootprint", "totalCarbonFootprint"]
+	Op    SearchOp
+	Value any // string, json.Number, bool or nil
+}
+
+func (SearchAnd) searchExpr()        {}
+func (SearchOr) searchExpr()         {}
+func (SearchNot) searchExpr()        {}
+func (SearchComparison) searchExpr() {}
+
+var (
+	searchPathSegment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	searchNumber      = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+// ParseSearchQuery parses a query of the search language. Errors wrap ErrInvalidInput.
+func ParseSearchQuery(query string) (SearchExpr, error) {
+	if len(query) > MaxSearchQueryLength {
+		return nil, fmt.Errorf("%w: search query is longer than %d characters", ErrInvalidInput, MaxSearchQueryLength)
+	}
+	tokens, err := lexSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: search query is empty", ErrInvalidInput)
+	}
+
+	p := &searchParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %s", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+type searchTokenKind int
+
+const (
+	tokenWord   searchTokenKind = iota // Path, keyword or bare value
+	tokenString                        // Double-quoted string
+	tokenOp                            // Comparison operator
+	tokenLParen
+	tokenRParen
+)
+
+type searchToken struct {
+	kind  searchTokenKind
+	text  string // Unquoted for strings
+	start int
+}
+
+func (t searchToken) String() string {
+	if t.kind == tokenString {
+		return fmt.Sprintf("string %q at %d", t.text, t.start)
+	}
+	return fmt.Sprintf("%q at %d", t.text, t.start)
+}
+
+func lexSearchQuery(query string) ([]searchToken, error) {
+	var tokens []searchToken
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, searchToken{kind: tokenLParen, text: "(", start: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, searchToken{kind: tokenRParen, text: ")", start: i})
+			i++
+		case c == '=' || c == '<' || c == '>' || c == '!':
+			op := string(c)
+			if i+1 < len(query) && query[i+1] == '=' {
+				op += "="
+			}
+			switch SearchOp(op) {
+			case SearchEq, SearchNe, SearchLt, SearchLe, SearchGt, SearchGe:
+			default:
+				return nil, fmt.Errorf("%w: search query: unknown operator %q at %d", ErrInvalidInput, op, i)
+			}
+			tokens = append(tokens, searchToken{kind: tokenOp, text: op, start: i})
+			i += len(op)
+		case c == '"':
+			// A JSON string: the closing quote is the first one not escaped
+			end := i + 1
+			for end < len(query) && query[end] != '"' {
+				if query[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(query) {
+				return nil, fmt.Errorf("%w: search query: unterminated string at %d", ErrInvalidInput, i)
+			}
+			var s string
+			if err := json.Unmarshal([]byte(query[i:end+1]), &s); err != nil {
+				return nil, fmt.Errorf("%w: search query: invalid string at %d", ErrInvalidInput, i)
+			}
+			tokens = append(tokens, searchToken{kind: tokenString, text: s, start: i})
+			i = end + 1
+		default:
+			end := i
+			for end < len(query) && !strings.ContainsRune(" \t\n\r()=<>!\"", rune(query[end])) {
+				end++
+			}
+			tokens = append(tokens, searchToken{kind: tokenWord, text: query[i:end], start: i})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type searchParser struct {
+	tokens      []searchToken
+	pos         int
+	comparisons int
+}
+
+func (p *searchParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: search query: %s", ErrInvalidInput, fmt.Sprintf(format, args...))
+}
+
+// keyword consumes the next token if it is the keyword (case-insensitive).
+func (p *searchParser) keyword(kw string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenWord && strings.EqualFold(p.tokens[p.pos].text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *searchParser) parseOr() (SearchExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := SearchOr{left}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return terms, nil
+}
+
+func (p *searchParser) parseAnd() (SearchExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := SearchAnd{left}
+	for p.keyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return terms, nil
+}
+
+func (p *searchParser) parseUnary() (SearchExpr, error) {
+	if p.keyword("NOT") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return SearchNot{Expr: expr}, nil
+	}
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenLParen {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenRParen {
+			return nil, p.errorf("missing ')'")
+		}
+		p.pos++
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *searchParser) parseComparison() (SearchExpr, error) {
+	// 1. Path
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("expected an attribute path at the end")
+	}
+	pathToken := p.tokens[p.pos]
+	if pathToken.kind != tokenWord {
+		return nil, p.errorf("expected an attribute path, got %s", pathToken)
+	}
+	path := strings.Split(pathToken.text, ".")
+	for _, segment := range path {
+		if !searchPathSegment.MatchString(segment) {
+			return nil, p.errorf("invalid attribute path %s", pathToken)
+		}
+	}
+	p.pos++
+
+	// 2. Operator
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenOp {
+		return nil, p.errorf("expected a comparison operator after %s", pathToken)
+	}
+	op := SearchOp(p.tokens[p.pos].text)
+	p.pos++
+
+	// 3. Value
+	if p.pos >= len(p.tokens) || (p.tokens[p.pos].kind != tokenWord && p.tokens[p.pos].kind != tokenString) {
+		return nil, p.errorf("expected a value after %s %s", pathToken.text, op)
+	}
+	valueToken := p.tokens[p.pos]
+	p.pos++
+
+	var value any = valueToken.text
+	if valueToken.kind == tokenWord {
+		switch {
+		case valueToken.text == "true" || valueToken.text == "false":
+			value = valueToken.text == "true"
+		case valueToken.text == "null":
+			value = nil
+		case searchNumber.MatchString(valueToken.text):
+			value = json.Number(valueToken.text)
+		}
+	}
+	if op != SearchEq && op != SearchNe {
+		switch value.(type) {
+		case string, json.Number:
+		default:
+			return nil, p.errorf("%s compares numbers or strings, got %s", op, valueToken)
+		}
+	}
+
+	p.comparisons++
+	if p.comparisons > MaxSearchComparisons {
+		return nil, p.errorf("more than %d comparisons", MaxSearchComparisons)
+	}
+	return SearchComparison{Path: path, Op: op, Value: value}, nil
+}
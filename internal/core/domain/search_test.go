@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	expr, err := ParseSearchQuery(`chemistry = LITHIUM_ION AND carbonFootprint.totalCarbonFootprint < 60 OR NOT (origin != "Made in \"EU\"" and recyclable = true)`)
+
+	require.NoError(t, err)
+	assert.Equal(t, SearchOr{
+		SearchAnd{
+			SearchComparison{Path: []string{"chemistry"}, Op: SearchEq, Value: "LITHIUM_ION"},
+			SearchComparison{Path: []string{"carbonFootprint", "totalCarbonFootprint"}, Op: SearchLt, Value: json.Number("60")},
+		},
+		SearchNot{Expr: SearchAnd{
+			SearchComparison{Path: []string{"origin"}, Op: SearchNe, Value: `Made in "EU"`},
+			SearchComparison{Path: []string{"recyclable"}, Op: SearchEq, Value: true},
+		}},
+	}, expr)
+}
+
+func TestParseSearchQuery_Values(t *testing.T) {
+	tests := []struct {
+		query string
+		want  any
+	}{
+		{`a = -1.5e3`, json.Number("-1.5e3")},
+		{`a = "60"`, "60"},
+		{`a = null`, nil},
+		{`a = 2025-11-28`, "2025-11-28"},
+		{`a >= "2025-11-28"`, "2025-11-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := ParseSearchQuery(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.(SearchComparison).Value)
+		})
+	}
+}
+
+func TestParseSearchQuery_Rejects(t *testing.T) {
+	queries := []string{
+		``,
+		`chemistry`,
+		`chemistry =`,
+		`= LITHIUM_ION`,
+		`chemistry = LITHIUM_ION AND`,
+		`(chemistry = LITHIUM_ION`,
+		`chemistry = LITHIUM_ION)`,
+		`chemistry == LITHIUM_ION`,
+		`chemistry ! LITHIUM_ION`,
+		`a.b-c = 1`,
+		`a..b = 1`,
+		`a = "unterminated`,
+		`a < true`,
+		`a > null`,
+		`a = 1 b = 2`,
+		strings.Repeat(`a = 1 OR `, 32) + `a = 1`,
+		strings.Repeat(`(`, MaxSearchQueryLength+1),
+	}
+
+	for _, query := range queries {
+		_, err := ParseSearchQuery(query)
+		assert.ErrorIs(t, err, ErrInvalidInput, query)
+	}
+}
+
+func TestPassportSearchRequest_PassportQuery(t *testing.T) {
+	req := PassportSearchRequest{Query: `chemistry = LITHIUM_ION`, Status: "published", Sort: "-publishedAt", Limit: 10}
+
+	q, err := req.PassportQuery()
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusPublished, q.Filter.Status)
+	assert.Equal(t, SortPublishedAt, q.Sort)
+	assert.True(t, q.Descending)
+	assert.Equal(t, 10, q.Limit)
+	assert.NotNil(t, q.Filter.Attributes)
+
+	_, err = PassportSearchRequest{Query: `chemistry = LITHIUM_ION`, Cursor: "bogus"}.PassportQuery()
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestParseSearchTerms(t *testing.T) {
+	terms, err := ParseSearchTerms("PowerCell LFP-280, Tröpfel")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"powercell", "lfp", "280", "tröpfel"}, terms)
+
+	for _, text := range []string{"", " - ", strings.Repeat("a ", MaxTextSearchTerms+1)} {
+		_, err := ParseSearchTerms(text)
+		assert.ErrorIs(t, err, ErrInvalidInput, text)
+	}
+}
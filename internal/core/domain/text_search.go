@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Bounds of a full-text search.
+const (
+	DefaultTextSearchLimit = 20
+	MaxTextSearchLimit     = 50
+	MaxTextSearchTerms     = 10
+)
+
+// SearchWeight ranks the text of a schema field in full-text search, "A" (highest) to "D".
+// Fields are selected with the "search" annotation of the category schema.
+type SearchWeight string
+
+const (
+	SearchWeightA SearchWeight = "A" // e.g. model names
+	SearchWeightB SearchWeight = "B" // e.g. product types, materials
+	SearchWeightC SearchWeight = "C" // e.g. suppliers, places, parts
+	SearchWeightD SearchWeight = "D"
+)
+
+// SearchWeights lists the weights, highest first.
+var SearchWeights = []SearchWeight{SearchWeightA, SearchWeightB, SearchWeightC, SearchWeightD}
+
+func (w SearchWeight) IsValid() bool {
+	switch w {
+	case SearchWeightA, SearchWeightB, SearchWeightC, SearchWeightD:
+		return true
+	}
+	return false
+}
+
+// SearchDocument is the searchable text of a passport as seen by viewers of one access rank:
+// text above that rank is left out, so it can never be matched by someone who cannot read it.
+type SearchDocument struct {
+	AccessRank int
+	Text       map[SearchWeight]string
+}
+
+// ParseSearchTerms splits a full-text search into lowercase words (runs of letters and digits),
+// each matched as a prefix. Errors wrap ErrInvalidInput.
+func ParseSearchTerms(text string) ([]string, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil, fmt.Errorf("%w: search text has no words", ErrInvalidInput)
+	}
+	if len(words) > MaxTextSearchTerms {
+		return nil, fmt.Errorf("%w: search text has more than %d words", ErrInvalidInput, MaxTextSearchTerms)
+	}
+	return words, nil
+}
+
+// RankedGrant is an active access grant with the rank of its level. A PASSPORT grant also carries
+// the IDs of every revision of its passport, which it covers as the product is revised.
+type RankedGrant struct {
+	*AccessGrant
+	Rank      int
+	Revisions []uuid.UUID // PASSPORT scope only
+}
+
+// TextSearchQuery is a full-text search on behalf of a viewer. Each passport is matched against
+// its document for the rank the viewer gets on it: OwnerRank on the owner's passports, otherwise
+// the higher of ViewerRank and the grants covering it. Other tenants only find published passports.
+type TextSearchQuery struct {
+	Terms      []string
+	OwnerID    string // Viewer tenant; "" for anonymous viewers
+	OwnerRank  int
+	ViewerRank int
+	Grants     []RankedGrant
+	Limit      int
+}
+
+// TextSearchHit is a passport matching a full-text search.
+type TextSearchHit struct {
+	PassportID uuid.UUID
+	Score      float64
+}
+
+// TextSearchResult is a passport found by full-text search, filtered for the viewer.
+type TextSearchResult struct {
+	Score    float64   `json:"score"`
+	Passport *Passport `json:"passport"`
+}
+
+// ReindexReport is the outcome of rebuilding the search documents of a manufacturer's passports.
+type ReindexReport struct {
+	Indexed int         `json:"indexed"`
+	Failed  []uuid.UUID `json:"failed,omitempty"`
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent is a passport lifecycle event tenants can subscribe to.
+type WebhookEvent string
+
+const (
+	WebhookPassportCreated   WebhookEvent = "passport.created"
+	WebhookPassportUpdated   WebhookEvent = "passport.updated"
+	WebhookPassportPublished WebhookEvent = "passport.published"
+	WebhookPassportRevoked   WebhookEvent = "passport.revoked"
+)
+
+// WebhookEvents lists every event a subscription can select.
+var WebhookEvents = []WebhookEvent{WebhookPassportCreated, WebhookPassportUpdated, WebhookPassportPublished, WebhookPassportRevoked}
+
+// ParseWebhookEvent returns the event named s.
+func ParseWebhookEvent(s string) (WebhookEvent, error) {
+	for _, e := range WebhookEvents {
+		if string(e) == s {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown webhook event %q", ErrInvalidInput, s)
+}
+
+// nonPublicPrefixes are the special-purpose ranges IsPublicWebhookAddress rejects on top of the
+// loopback, link-local, private and multicast ones.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This network"
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // Reserved
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64: may reach any IPv4 address
+}
+
+// IsPublicWebhookAddress reports whether a webhook may be delivered to the address: deliveries
+// must never reach the platform's own network (loopback, link-local such as cloud metadata,
+// private ranges).
+func IsPublicWebhookAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// WebhookSubscription is an endpoint of a tenant notified of its passports' lifecycle events.
+type WebhookSubscription struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	TenantID  string         `json:"tenantId" db:"tenant_id"`
+	URL       string         `json:"url" db:"url"`
+	Events    []WebhookEvent `json:"events" db:"events"`
+	Secret    string         `json:"secret,omitempty" db:"secret"` // Signing key; only returned when the subscription is created
+	CreatedAt time.Time      `json:"createdAt" db:"created_at"`
+}
+
+// Wants reports whether the subscription selected the event.
+func (s *WebhookSubscription) Wants(event WebhookEvent) bool {
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookInput is the input for creating a subscription. No events means all of them.
+type WebhookInput struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"   // Waiting for its first or next attempt
+	DeliveryDelivered DeliveryStatus = "DELIVERED" // The endpoint answered 2xx
+	DeliveryDead      DeliveryStatus = "DEAD"      // Out of attempts (dead-letter list); can be redelivered
+)
+
+// WebhookDelivery is one event sent to one subscription, with its retry state.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	SubscriptionID uuid.UUID       `json:"subscriptionId" db:"subscription_id"`
+	TenantID       string          `json:"tenantId" db:"tenant_id"`
+	Event          WebhookEvent    `json:"event" db:"event"`
+	Payload        json.RawMessage `json:"payload" db:"payload"` // The body sent (signed at every attempt)
+	Status         DeliveryStatus  `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastError      string          `json:"lastError,omitempty" db:"last_error"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty" db:"last_status_code"`
+	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty" db:"delivered_at"`
+}
+
+// WebhookPayload is the JSON body of a delivery. Data is the event as published on the event bus.
+type WebhookPayload struct {
+	DeliveryID uuid.UUID       `json:"deliveryId"`
+	Event      WebhookEvent    `json:"event"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Data       json.RawMessage `json:"data"`
+}
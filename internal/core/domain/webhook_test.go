@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package domain
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicWebhookAddress(t *testing.T) {
+	tests := map[string]bool{
+		"203.0.113.7":      true,
+		"8.8.8.8":          true,
+		"2001:4860::8888":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"0.0.0.0":          false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.0.1":      false,
+		"fd00:ec2::254":    false,
+		"100.64.0.1":       false,
+		"::ffff:127.0.0.1": false,
+		"64:ff9b::a00:1":   false,
+		"224.0.0.1":        false,
+		"255.255.255.255":  false,
+	}
+	for addr, want := range tests {
+		assert.Equal(t, want, IsPublicWebhookAddress(netip.MustParseAddr(addr)), addr)
+	}
+}
@@ -9,10 +9,26 @@
 
 package ports
 
-import "context"
+import (
+	"context"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+)
 
 type AuthRepository interface {
 	ValidateKey(ctx context.Context, apiKeyHash string) (tenantID string, valid bool, err error)
 	GetTenantState(ctx context.Context, tenantID string) (state string, err error)
 	GetTenantName(ctx context.Context, tenantID string) (name string, err error)
+
+	// GetKeyAccessLevel returns the access level granted to an API key ("" if none was assigned).
+	GetKeyAccessLevel(ctx context.Context, apiKeyHash string) (level string, err error)
+}
+
+type TenantRepository interface {
+	// GetTenantProfile returns the registered identity of a tenant; domain.ErrNotFound if it is unknown.
+	GetTenantProfile(ctx context.Context, tenantID string) (*domain.TenantProfile, error)
+
+	// FindCompanyPrefixOwner returns the tenant the GS1 company prefix of a normalized GTIN is
+	// registered to, or "" if no tenant has registered it.
+	FindCompanyPrefixOwner(ctx context.Context, gtin string) (string, error)
 }
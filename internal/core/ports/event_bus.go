@@ -9,8 +9,35 @@
 
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+)
 
 type EventBus interface {
 	Publish(ctx context.Context, channel string, event interface{}) error
 }
+
+// EventHandler processes one event. An error leaves the event unacknowledged and it is
+// delivered again later, so handlers must be idempotent.
+type EventHandler func(ctx context.Context, msg domain.EventMessage) error
+
+// EventSubscriber consumes the event bus durably through consumer groups: each event reaches
+// one consumer of every group, and survives restarts until a handler acknowledges it.
+type EventSubscriber interface {
+	// Subscribe handles the channels' events as consumer of group until ctx is cancelled
+	Subscribe(ctx context.Context, group, consumer string, channels []string, handler EventHandler) error
+}
+
+// EventReader reads the event bus from given positions, outside any consumer group: nothing is
+// acknowledged and every reader sees every event still retained.
+type EventReader interface {
+	// ReadEvents returns the events after the positions (channel -> entry ID), waiting up to
+	// block for the first one. An empty result means nothing arrived in time.
+	ReadEvents(ctx context.Context, positions map[string]string, count int64, block time.Duration) ([]domain.EventMessage, error)
+
+	// LatestPositions returns the position of the last event of each channel ("0-0" if empty).
+	LatestPositions(ctx context.Context, channels []string) (map[string]string, error)
+}
@@ -11,17 +11,25 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/TraceApi/api-core/internal/core/domain" // Adjust module path if needed
 	"github.com/google/uuid"
 )
 
+// The write methods of PassportRepository also store the passports' recorded Events in the
+// outbox, in the same transaction, and clear them once committed.
 type PassportRepository interface {
 	// Save creates or updates a passport
 	Save(ctx context.Context, passport *domain.Passport) error
 
-	// Update updates an existing passport (status, hash, published_at, storage_location)
-	Update(ctx context.Context, passport *domain.Passport) error
+	// SaveBatch inserts new passports in one round trip (all or nothing); returns domain.ErrConflict
+	// if any of them reuses registered GS1 keys
+	SaveBatch(ctx context.Context, passports []*domain.Passport) error
+
+	// Update updates an existing passport (status, hash, published_at, storage_location) if it is
+	// still in the status it was read in; returns domain.ErrConflict if it changed meanwhile
+	Update(ctx context.Context, passport *domain.Passport, expected domain.PassportStatus) error
 
 	// GetByID retrieves a single passport
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Passport, error)
@@ -29,6 +37,175 @@ type PassportRepository interface {
 	// FindByCategory retrieves a page of passports (Basic pagination)
 	FindByCategory(ctx context.Context, category domain.ProductCategory, limit, offset int) ([]*domain.Passport, error)
 
-	// FindByManufacturer retrieves all passports for a specific manufacturer
-	FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.Passport, error)
+	// FindByManufacturer retrieves up to query.Limit of a manufacturer's passports matching the
+	// filter, in the query's order, starting after query.After (keyset pagination)
+	FindByManufacturer(ctx context.Context, manufacturerID string, query domain.PassportQuery) ([]*domain.Passport, error)
+
+	// FindDrafts returns the IDs of a manufacturer's drafts, oldest first, optionally limited to a
+	// category and to those created before a time
+	FindDrafts(ctx context.Context, manufacturerID string, category domain.ProductCategory, createdBefore *time.Time) ([]uuid.UUID, error)
+
+	// PublishBatch records the publication of drafts (status, hash, storage location) and supersedes
+	// their previous revisions in one transaction, with their events; returns domain.ErrConflict if
+	// any is no longer a draft or its previous revision is no longer published
+	PublishBatch(ctx context.Context, passports []*domain.Passport) error
+
+	// FindVersionHistory returns the whole revision chain containing id, oldest first
+	FindVersionHistory(ctx context.Context, id uuid.UUID) ([]domain.PassportVersion, error)
+
+	// FindByIdentifiers returns the first revision of every passport chain registered under
+	// the GS1 keys, unless it is still a draft. A serial number matches whatever the batch
+	// unless a batch is given.
+	FindByIdentifiers(ctx context.Context, ids domain.ProductIdentifiers) ([]uuid.UUID, error)
+
+	// CountPublishedChildren counts the PUBLISHED batches or items inheriting from any of the passports
+	CountPublishedChildren(ctx context.Context, parentIDs []uuid.UUID) (int, error)
+
+	// SaveSearchDocuments replaces the full-text search documents of the passports
+	SaveSearchDocuments(ctx context.Context, passports []*domain.Passport) error
+
+	// SearchText returns up to query.Limit passports matching every term, best match first
+	SearchText(ctx context.Context, query domain.TextSearchQuery) ([]domain.TextSearchHit, error)
+}
+
+type GrantRepository interface {
+	Save(ctx context.Context, grant *domain.AccessGrant) error
+
+	// GetByID returns domain.ErrNotFound if the grant does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessGrant, error)
+
+	// FindByManufacturer returns every grant issued by a manufacturer, newest first
+	FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.AccessGrant, error)
+
+	// FindActiveForPassport returns the unrevoked, unexpired grants of a tenant that cover the passport;
+	// PASSPORT grants on any of its revisions count
+	FindActiveForPassport(ctx context.Context, granteeTenantID string, passport *domain.Passport, revisions []uuid.UUID, now time.Time) ([]*domain.AccessGrant, error)
+
+	// FindActiveForGrantee returns every unrevoked, unexpired grant of a tenant
+	FindActiveForGrantee(ctx context.Context, granteeTenantID string, now time.Time) ([]*domain.AccessGrant, error)
+
+	// Revoke marks a grant as revoked; returns domain.ErrNotFound if it is missing or already revoked
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+}
+
+type AccessRequestRepository interface {
+	// Save stores a new request; returns domain.ErrConflict if the requester already has one pending for the passport
+	Save(ctx context.Context, req *domain.AccessRequest) error
+
+	// GetByID returns domain.ErrNotFound if the request does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessRequest, error)
+
+	// FindByManufacturer returns the requests addressed to a manufacturer, newest first (all statuses if status is empty)
+	FindByManufacturer(ctx context.Context, manufacturerID string, status domain.AccessRequestStatus) ([]*domain.AccessRequest, error)
+
+	// Decide records the decision of a PENDING request; returns domain.ErrConflict if it was already decided
+	Decide(ctx context.Context, req *domain.AccessRequest) error
+}
+
+type LinkRepository interface {
+	// Save stores a new link; returns domain.ErrConflict if the same type, language and media type is already registered at that level
+	Save(ctx context.Context, link *domain.PassportLink) error
+
+	// GetByID returns domain.ErrNotFound if the link does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PassportLink, error)
+
+	// FindByManufacturer returns every link registered by a manufacturer, oldest first
+	FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.PassportLink, error)
+
+	// FindForPassports returns the manufacturer-wide links plus those attached to any of the passports, oldest first
+	FindForPassports(ctx context.Context, manufacturerID string, passportIDs []uuid.UUID) ([]*domain.PassportLink, error)
+
+	// Delete removes a link; returns domain.ErrNotFound if it does not exist
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type JobRepository interface {
+	// Save stores a new job together with its input (may be nil)
+	Save(ctx context.Context, job *domain.Job, input []byte) error
+
+	// GetByID returns domain.ErrNotFound if the job does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+
+	// GetInput returns the input stored with the job
+	GetInput(ctx context.Context, id uuid.UUID) ([]byte, error)
+
+	// GetResult returns the result of a finished job (nil if it has none)
+	GetResult(ctx context.Context, id uuid.UUID) ([]byte, error)
+
+	// FindByTenant returns the most recent jobs of a tenant, newest first
+	FindByTenant(ctx context.Context, tenantID string, limit int) ([]*domain.Job, error)
+
+	// Claim locks the oldest runnable job (queued, or running with an expired lease) for a worker
+	// and marks it RUNNING; returns domain.ErrNotFound if there is none
+	Claim(ctx context.Context, workerID string, lease time.Duration) (*domain.Job, error)
+
+	// Heartbeat extends the lease of a claimed job and records its progress.
+	// It reports whether a cancellation was requested; domain.ErrConflict means the lease was lost.
+	Heartbeat(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration, progress domain.JobProgress) (bool, error)
+
+	// Finish records the outcome of a claimed job: a final status, or QUEUED with a new RunAfter to retry it
+	Finish(ctx context.Context, job *domain.Job, workerID string, result []byte) error
+
+	// RequestCancel cancels a queued job or flags a running one; returns domain.ErrConflict if it is already final
+	RequestCancel(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+
+	// Requeue puts a failed or cancelled job back in the queue; returns domain.ErrConflict otherwise
+	Requeue(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+}
+
+type WebhookRepository interface {
+	SaveSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+
+	// GetSubscription returns domain.ErrNotFound if the subscription does not exist
+	GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error)
+
+	// FindSubscriptions returns the subscriptions of a tenant, oldest first (all events if event is empty)
+	FindSubscriptions(ctx context.Context, tenantID string, event domain.WebhookEvent) ([]*domain.WebhookSubscription, error)
+
+	// DeleteSubscription removes a subscription and its pending deliveries; returns domain.ErrNotFound if it does not exist
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	// SaveDeliveries queues new deliveries; IDs already queued are ignored
+	SaveDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+
+	// GetDelivery returns domain.ErrNotFound if the delivery does not exist
+	GetDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+
+	// FindDeliveries returns the most recent deliveries of a tenant, newest first (all statuses if status is empty)
+	FindDeliveries(ctx context.Context, tenantID string, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+
+	// ClaimDeliveries returns pending deliveries that are due and pushes their next attempt back by lease,
+	// so concurrent dispatchers do not send them twice
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+
+	// RecordAttempt stores the outcome of an attempt (status, attempts, next attempt, last error)
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// Redeliver queues a delivery again with a fresh attempt budget; returns domain.ErrConflict if it is still pending
+	Redeliver(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+}
+
+type OutboxRepository interface {
+	// ClaimEvents returns due events and pushes their next attempt back by lease. Only the oldest
+	// unrelayed event of a passport can be claimed, so each passport's events go out in order.
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error)
+
+	// MarkRelayed removes events handed over to the event bus
+	MarkRelayed(ctx context.Context, ids []uuid.UUID) error
+
+	// RecordFailure stores a failed relay attempt (attempts, next attempt, last error)
+	RecordFailure(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+type ScanRepository interface {
+	// SaveScans appends resolver hits to the scan log
+	SaveScans(ctx context.Context, scans []domain.ScanEvent) error
+
+	// RollupScans moves up to limit of the oldest logged scans into the daily counts (per passport,
+	// and per tenant by dimension), in one transaction; returns how many were moved
+	RollupScans(ctx context.Context, limit int) (int, error)
+
+	// FindScanCounts sums a tenant's daily counts over the query's period, per bucket (and group),
+	// oldest first
+	FindScanCounts(ctx context.Context, manufacturerID string, query domain.ScanQuery) ([]domain.ScanCount, error)
 }
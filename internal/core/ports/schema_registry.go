@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package ports
+
+import (
+	"context"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+)
+
+// SchemaRegistry stores versioned category schemas uploaded at runtime.
+// The schemas embedded in the binary are the bootstrap defaults and are not stored here.
+type SchemaRegistry interface {
+	// Save stores a new (inactive) schema version. Returns ErrConflict if the version exists.
+	Save(ctx context.Context, schema *domain.CategorySchema) error
+
+	// Get retrieves a specific version. Returns ErrNotFound if it does not exist.
+	Get(ctx context.Context, category domain.ProductCategory, version string) (*domain.CategorySchema, error)
+
+	// GetActive retrieves the version used for new passports. Returns ErrNotFound if none is active.
+	GetActive(ctx context.Context, category domain.ProductCategory) (*domain.CategorySchema, error)
+
+	// List retrieves all stored versions of a category, newest first
+	List(ctx context.Context, category domain.ProductCategory) ([]*domain.CategorySchema, error)
+
+	// Activate makes a version the active one, deactivating the previous active version
+	Activate(ctx context.Context, category domain.ProductCategory, version string) error
+
+	// Deactivate leaves a category without an active version, so the built-in one applies again
+	Deactivate(ctx context.Context, category domain.ProductCategory) error
+}
@@ -11,21 +11,181 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/google/uuid"
 )
 
 type PassportService interface {
-	// CreatePassport takes raw JSON input and the intended category.
+	// CreatePassport takes raw JSON input, the intended category, optional GS1 keys and the
+	// passport's place in the model/batch/item hierarchy (a child only supplies its own fields).
 	// It returns the created Passport (with ID) or a validation error.
-	CreatePassport(ctx context.Context, manufacturerID string, manufacturerName string, category domain.ProductCategory, payload []byte) (*domain.Passport, error)
+	CreatePassport(ctx context.Context, manufacturerID string, manufacturerName string, category domain.ProductCategory, ids domain.ProductIdentifiers, hierarchy domain.PassportHierarchy, payload []byte) (*domain.Passport, error)
+
+	// ImportPassports creates DRAFT passports from an NDJSON or CSV body. Each record goes through
+	// the same checks and idempotency hashing as CreatePassport; the report lists the outcome per line.
+	ImportPassports(ctx context.Context, manufacturerID string, manufacturerName string, req domain.ImportRequest, body io.Reader) (*domain.ImportReport, error)
 
 	GetPassport(ctx context.Context, id uuid.UUID) (*domain.Passport, error)
 
-	PublishPassport(ctx context.Context, id uuid.UUID) (*domain.Passport, error)
+	PublishPassport(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.Passport, error)
+
+	// PublishPassports publishes many drafts of a manufacturer. Envelopes are uploaded with bounded
+	// concurrency and statuses recorded in batches; running it again only retries the failures.
+	PublishPassports(ctx context.Context, manufacturerID string, req domain.BulkPublishRequest) (*domain.PublishReport, error)
+
+	// ListPassports returns one page of the manufacturer's passports; Next continues the list.
+	ListPassports(ctx context.Context, manufacturerID string, query domain.PassportQuery) (*domain.PassportPage, error)
+
+	// SearchText finds passports by words of their schema-selected text fields (prefix matches,
+	// best first), for the viewer in the context. Text the viewer may not read is never matched,
+	// and the passports come filtered as by GetPassport.
+	SearchText(ctx context.Context, text string, limit int) ([]domain.TextSearchResult, error)
 
-	ListPassports(ctx context.Context, manufacturerID string) ([]*domain.Passport, error)
+	// ReindexSearch rebuilds the full-text search documents of every passport of a manufacturer.
+	// progress (optional) is called with the number of passports done.
+	ReindexSearch(ctx context.Context, manufacturerID string, progress func(done int)) (*domain.ReindexReport, error)
 
 	UpdatePassport(ctx context.Context, id uuid.UUID, manufacturerID string, payload []byte) (*domain.Passport, error)
+
+	// RevokePassport withdraws a published passport (e.g. product recall).
+	// The record stays resolvable as a tombstone carrying the reason and timestamp.
+	RevokePassport(ctx context.Context, id uuid.UUID, manufacturerID string, reason string) (*domain.Passport, error)
+
+	// CreateRevision clones a published passport into a new DRAFT linked by previousVersionId.
+	// Publishing the revision supersedes the original.
+	CreateRevision(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.Passport, error)
+
+	// GetRevisionHistory returns the published lineage of a passport, oldest first.
+	// The last entry is the revision that should be displayed for any id in the chain.
+	GetRevisionHistory(ctx context.Context, id uuid.UUID) ([]domain.PassportVersion, error)
+
+	// ResolveIdentifiers returns the first revision of the passport registered under GS1 keys
+	// (GS1 Digital Link). Follow GetRevisionHistory to reach the revision to display.
+	ResolveIdentifiers(ctx context.Context, ids domain.ProductIdentifiers) (uuid.UUID, error)
+}
+
+type SchemaService interface {
+	// UploadSchema stores a new, inactive version of a category schema.
+	// The version must be a semantic version greater than every existing one.
+	UploadSchema(ctx context.Context, category domain.ProductCategory, version string, schema []byte) (*domain.CategorySchema, error)
+
+	// ActivateSchema makes a stored version the one new passports are validated against.
+	ActivateSchema(ctx context.Context, category domain.ProductCategory, version string) (*domain.CategorySchema, error)
+
+	// ListSchemas returns every version of a category, including the embedded bootstrap schema.
+	ListSchemas(ctx context.Context, category domain.ProductCategory) ([]*domain.CategorySchema, error)
+
+	GetSchema(ctx context.Context, category domain.ProductCategory, version string) (*domain.CategorySchema, error)
+}
+
+type GrantService interface {
+	// CreateGrant lets another tenant read the manufacturer's passports up to an access level.
+	CreateGrant(ctx context.Context, manufacturerID string, req domain.GrantRequest) (*domain.AccessGrant, error)
+
+	ListGrants(ctx context.Context, manufacturerID string) ([]*domain.AccessGrant, error)
+
+	// RevokeGrant ends a grant immediately. Only the issuing manufacturer can revoke it.
+	RevokeGrant(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.AccessGrant, error)
+}
+
+type AccessRequestService interface {
+	// RequestAccess lets an authenticated third party ask for restricted data on a published passport.
+	RequestAccess(ctx context.Context, passportID uuid.UUID, requesterTenantID string, input domain.AccessRequestInput) (*domain.AccessRequest, error)
+
+	// ListAccessRequests returns the requests addressed to a manufacturer (all statuses if status is empty).
+	ListAccessRequests(ctx context.Context, manufacturerID string, status domain.AccessRequestStatus) ([]*domain.AccessRequest, error)
+
+	// ApproveAccessRequest grants the requester time-limited access to the passport.
+	ApproveAccessRequest(ctx context.Context, id uuid.UUID, manufacturerID string, decision domain.AccessDecision) (*domain.AccessRequest, error)
+
+	DenyAccessRequest(ctx context.Context, id uuid.UUID, manufacturerID string, decision domain.AccessDecision) (*domain.AccessRequest, error)
+}
+
+type LinkService interface {
+	// CreateLink registers a resolver destination for one passport or, without passportId,
+	// for every passport of the manufacturer.
+	CreateLink(ctx context.Context, manufacturerID string, input domain.LinkInput) (*domain.PassportLink, error)
+
+	ListLinks(ctx context.Context, manufacturerID string) ([]*domain.PassportLink, error)
+
+	// DeleteLink removes a link. Only the manufacturer that registered it can delete it.
+	DeleteLink(ctx context.Context, id uuid.UUID, manufacturerID string) error
+
+	// ResolveLinks returns the links that apply to a passport: links attached to any revision of
+	// its chain replace the manufacturer-wide links of the same type.
+	ResolveLinks(ctx context.Context, passportID uuid.UUID) ([]*domain.PassportLink, error)
+}
+
+type JobService interface {
+	// SubmitJob queues a long-running operation for cmd/api-worker.
+	SubmitJob(ctx context.Context, tenantID string, jobType domain.JobType, params interface{}, input []byte) (*domain.Job, error)
+
+	// GetJob returns a job submitted by the tenant (domain.ErrForbidden for other tenants' jobs).
+	GetJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error)
+
+	ListJobs(ctx context.Context, tenantID string) ([]*domain.Job, error)
+
+	// GetJobResult returns the result document of a finished job.
+	GetJobResult(ctx context.Context, id uuid.UUID, tenantID string) ([]byte, error)
+
+	// CancelJob cancels a queued job at once; a running job stops at its next checkpoint.
+	CancelJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error)
+
+	// RetryJob queues a failed or cancelled job again, with a fresh attempt budget.
+	RetryJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error)
+}
+
+type WebhookService interface {
+	// CreateSubscription registers an endpoint for passport lifecycle events. The returned
+	// subscription carries the signing secret, which is not shown again.
+	CreateSubscription(ctx context.Context, tenantID string, input domain.WebhookInput) (*domain.WebhookSubscription, error)
+
+	ListSubscriptions(ctx context.Context, tenantID string) ([]*domain.WebhookSubscription, error)
+
+	// DeleteSubscription stops the deliveries of a subscription. Only its tenant can delete it.
+	DeleteSubscription(ctx context.Context, id uuid.UUID, tenantID string) error
+
+	// ListDeliveries returns recent deliveries; DEAD lists the dead letters.
+	ListDeliveries(ctx context.Context, tenantID string, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error)
+
+	// Redeliver sends a delivered or dead delivery again, with the same delivery ID and payload.
+	Redeliver(ctx context.Context, id uuid.UUID, tenantID string) (*domain.WebhookDelivery, error)
+}
+
+type EventStreamService interface {
+	// Open starts a stream of the tenant's lifecycle events. It resumes after lastEventID, a
+	// cursor of a previously streamed event, or starts with the events to come when empty.
+	Open(ctx context.Context, tenantID, lastEventID string) (EventStream, error)
+}
+
+// EventStream yields the lifecycle events of one tenant, in publication order per channel.
+type EventStream interface {
+	// Next returns the next events, waiting up to wait for some. None means none arrived.
+	Next(ctx context.Context, wait time.Duration) ([]domain.StreamEvent, error)
+}
+
+// ScanRecorder takes the resolver's scan events off the request path. Record never blocks: when
+// the pipeline falls behind, scans are dropped rather than slowing down resolution.
+type ScanRecorder interface {
+	Record(scan domain.ScanEvent)
+}
+
+type AnalyticsService interface {
+	// GetScanReport returns the tenant's scan counts per time bucket. Zero From/To and an empty
+	// bucket default to the last 30 days, per day.
+	GetScanReport(ctx context.Context, manufacturerID string, query domain.ScanQuery) (*domain.ScanReport, error)
+}
+
+// WebhookSender performs one HTTP delivery and returns the response status code.
+type WebhookSender interface {
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (statusCode int, err error)
+}
+
+// JobHandler executes one type of job in cmd/api-worker. It reports progress through the
+// callback and must return promptly once ctx is cancelled.
+type JobHandler interface {
+	Run(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) (result []byte, err error)
 }
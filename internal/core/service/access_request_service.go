@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultAccessRequestTTL is how long an approval lasts when the owner gives no expiry.
+	defaultAccessRequestTTL = 30 * 24 * time.Hour
+
+	maxPurposeLength = 2000
+)
+
+type accessRequestService struct {
+	requests  ports.AccessRequestRepository
+	passports ports.PassportRepository
+	grants    ports.GrantService
+	eventBus  ports.EventBus
+	levels    *domain.AccessLevels
+	log       *slog.Logger
+}
+
+// Ensure interface implementation
+var _ ports.AccessRequestService = (*accessRequestService)(nil)
+
+// NewAccessRequestService wires the access request workflow. Approvals are turned into
+// PASSPORT-scoped grants through the grant service. A nil access vocabulary means
+// domain.DefaultAccessLevels.
+func NewAccessRequestService(requests ports.AccessRequestRepository, passports ports.PassportRepository, grants ports.GrantService, eventBus ports.EventBus, accessLevels *domain.AccessLevels, log *slog.Logger) ports.AccessRequestService {
+	if accessLevels == nil {
+		accessLevels = domain.MustDefaultAccessLevels()
+	}
+	return &accessRequestService{requests: requests, passports: passports, grants: grants, eventBus: eventBus, levels: accessLevels, log: log}
+}
+
+func (s *accessRequestService) RequestAccess(ctx context.Context, passportID uuid.UUID, requesterTenantID string, input domain.AccessRequestInput) (*domain.AccessRequest, error) {
+	// 1. Validate Purpose
+	purpose := strings.TrimSpace(input.Purpose)
+	if purpose == "" {
+		return nil, fmt.Errorf("%w: purpose is required", domain.ErrInvalidInput)
+	}
+	if len(purpose) > maxPurposeLength {
+		return nil, fmt.Errorf("%w: purpose must be at most %d characters", domain.ErrInvalidInput, maxPurposeLength)
+	}
+
+	// 2. Validate Access Level (defaults to the first tier above public)
+	level := input.AccessLevel
+	if level == "" {
+		level = s.levels.Levels()[1]
+	}
+	rank, known := s.levels.Rank(level)
+	if !known {
+		return nil, fmt.Errorf("%w: unknown access level %s", domain.ErrInvalidInput, level)
+	}
+	if rank == 0 {
+		return nil, fmt.Errorf("%w: public data needs no request", domain.ErrInvalidInput)
+	}
+	level = s.levels.Levels()[rank]
+
+	// 3. Fetch Passport (requests target the live revision, like the resolver)
+	passport, err := s.passports.GetByID(ctx, passportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch passport: %w", err)
+	}
+	for passport.Status == domain.StatusSuperseded && passport.SupersededByID != nil {
+		if passport, err = s.passports.GetByID(ctx, *passport.SupersededByID); err != nil {
+			return nil, fmt.Errorf("failed to fetch passport revision: %w", err)
+		}
+	}
+	switch passport.Status {
+	case domain.StatusPublished:
+	case domain.StatusRevoked:
+		return nil, domain.ErrPassportRevoked
+	default:
+		return nil, fmt.Errorf("%w: only published passports accept access requests", domain.ErrInvalidInput)
+	}
+	if passport.ManufacturerID == requesterTenantID {
+		return nil, fmt.Errorf("%w: a manufacturer already sees its own passports", domain.ErrInvalidInput)
+	}
+
+	// 4. Save (one pending request per requester and passport)
+	req := &domain.AccessRequest{
+		ID:                uuid.New(),
+		PassportID:        passport.ID,
+		ManufacturerID:    passport.ManufacturerID,
+		RequesterTenantID: requesterTenantID,
+		Purpose:           purpose,
+		AccessLevel:       level,
+		Status:            domain.AccessRequestPending,
+		CreatedAt:         time.Now().UTC(),
+	}
+	if err := s.requests.Save(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to save access request: %w", err)
+	}
+
+	// 5. Publish Event
+	s.publish(ctx, domain.EventAccessRequested, req, req.CreatedAt)
+
+	s.log.Info("access requested", "id", req.ID, "passport", req.PassportID, "requester", requesterTenantID, "level", level)
+	return req, nil
+}
+
+func (s *accessRequestService) ListAccessRequests(ctx context.Context, manufacturerID string, status domain.AccessRequestStatus) ([]*domain.AccessRequest, error) {
+	switch status {
+	case "", domain.AccessRequestPending, domain.AccessRequestApproved, domain.AccessRequestDenied:
+	default:
+		return nil, fmt.Errorf("%w: status must be PENDING, APPROVED or DENIED", domain.ErrInvalidInput)
+	}
+	return s.requests.FindByManufacturer(ctx, manufacturerID, status)
+}
+
+func (s *accessRequestService) ApproveAccessRequest(ctx context.Context, id uuid.UUID, manufacturerID string, decision domain.AccessDecision) (*domain.AccessRequest, error) {
+	// 1. Fetch & Check Ownership
+	req, err := s.pendingRequest(ctx, id, manufacturerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(defaultAccessRequestTTL)
+	if decision.ExpiresAt != nil {
+		expiresAt = *decision.ExpiresAt
+	}
+
+	// 2. Create the Entitlement (validates expiry and ownership, emits events:access_granted)
+	grant, err := s.grants.CreateGrant(ctx, manufacturerID, domain.GrantRequest{
+		GranteeTenantID: req.RequesterTenantID,
+		Scope:           domain.GrantScopePassport,
+		PassportID:      &req.PassportID,
+		AccessLevel:     req.AccessLevel,
+		ExpiresAt:       expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Record the Decision
+	req.Status = domain.AccessRequestApproved
+	req.DecidedAt = &now
+	req.DecisionNote = strings.TrimSpace(decision.Note)
+	req.GrantID = &grant.ID
+	if err := s.requests.Decide(ctx, req); err != nil {
+		// Decided concurrently: don't leave a grant behind that no request accounts for
+		if _, revokeErr := s.grants.RevokeGrant(ctx, grant.ID, manufacturerID); revokeErr != nil {
+			s.log.Error("failed to revoke orphaned grant", "grant", grant.ID, "error", revokeErr)
+		}
+		return nil, fmt.Errorf("failed to approve access request: %w", err)
+	}
+
+	// 4. Publish Event
+	s.publish(ctx, domain.EventAccessRequestApproved, req, now)
+
+	return req, nil
+}
+
+func (s *accessRequestService) DenyAccessRequest(ctx context.Context, id uuid.UUID, manufacturerID string, decision domain.AccessDecision) (*domain.AccessRequest, error) {
+	// 1. Fetch & Check Ownership
+	req, err := s.pendingRequest(ctx, id, manufacturerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Record the Decision
+	now := time.Now().UTC()
+	req.Status = domain.AccessRequestDenied
+	req.DecidedAt = &now
+	req.DecisionNote = strings.TrimSpace(decision.Note)
+	if err := s.requests.Decide(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to deny access request: %w", err)
+	}
+
+	// 3. Publish Event
+	s.publish(ctx, domain.EventAccessRequestDenied, req, now)
+
+	return req, nil
+}
+
+// pendingRequest returns a request the manufacturer may still decide.
+func (s *accessRequestService) pendingRequest(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.AccessRequest, error) {
+	req, err := s.requests.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch access request: %w", err)
+	}
+	if req.ManufacturerID != manufacturerID {
+		return nil, domain.ErrForbidden
+	}
+	if req.Status != domain.AccessRequestPending {
+		return nil, fmt.Errorf("%w: access request already %s", domain.ErrConflict, strings.ToLower(string(req.Status)))
+	}
+	return req, nil
+}
+
+func (s *accessRequestService) publish(ctx context.Context, eventType domain.EventType, req *domain.AccessRequest, at time.Time) {
+	event, err := domain.NewAccessRequestEvent(eventType, req, at)
+	if err == nil {
+		err = s.eventBus.Publish(ctx, eventType.Channel(), event)
+	}
+	if err != nil {
+		s.log.Error("failed to publish access request event", "type", eventType, "error", err)
+	}
+}
@@ -0,0 +1,288 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAccessRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccessRequestRepository) Save(ctx context.Context, req *domain.AccessRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockAccessRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessRequest), args.Error(1)
+}
+
+func (m *MockAccessRequestRepository) FindByManufacturer(ctx context.Context, manufacturerID string, status domain.AccessRequestStatus) ([]*domain.AccessRequest, error) {
+	args := m.Called(ctx, manufacturerID, status)
+	return args.Get(0).([]*domain.AccessRequest), args.Error(1)
+}
+
+func (m *MockAccessRequestRepository) Decide(ctx context.Context, req *domain.AccessRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func TestRequestAccess_Validation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	published, draft, revoked := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		passportID uuid.UUID
+		requester  string
+		input      domain.AccessRequestInput
+		wantErr    error
+	}{
+		{"missing purpose", published, "recycler-1", domain.AccessRequestInput{Purpose: "  "}, domain.ErrInvalidInput},
+		{"public level", published, "recycler-1", domain.AccessRequestInput{Purpose: "recycling", AccessLevel: domain.AccessPublic}, domain.ErrInvalidInput},
+		{"unknown level", published, "recycler-1", domain.AccessRequestInput{Purpose: "recycling", AccessLevel: "root"}, domain.ErrInvalidInput},
+		{"own passport", published, "mfg-1", domain.AccessRequestInput{Purpose: "recycling"}, domain.ErrInvalidInput},
+		{"draft", draft, "recycler-1", domain.AccessRequestInput{Purpose: "recycling"}, domain.ErrInvalidInput},
+		{"revoked", revoked, "recycler-1", domain.AccessRequestInput{Purpose: "recycling"}, domain.ErrPassportRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := new(MockAccessRequestRepository)
+			passports := new(MockPassportRepository)
+			passports.On("GetByID", mock.Anything, published).Return(&domain.Passport{ID: published, ManufacturerID: "mfg-1", Status: domain.StatusPublished}, nil)
+			passports.On("GetByID", mock.Anything, draft).Return(&domain.Passport{ID: draft, ManufacturerID: "mfg-1", Status: domain.StatusDraft}, nil)
+			passports.On("GetByID", mock.Anything, revoked).Return(&domain.Passport{ID: revoked, ManufacturerID: "mfg-1", Status: domain.StatusRevoked}, nil)
+			svc := service.NewAccessRequestService(requests, passports, nil, new(MockEventBus), nil, logger)
+
+			_, err := svc.RequestAccess(ctx, tt.passportID, tt.requester, tt.input)
+
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+			requests.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRequestAccess_TargetsLiveRevision(t *testing.T) {
+	requests := new(MockAccessRequestRepository)
+	passports := new(MockPassportRepository)
+	bus := new(MockEventBus)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewAccessRequestService(requests, passports, nil, bus, nil, logger)
+	ctx := context.Background()
+
+	oldID, liveID := uuid.New(), uuid.New()
+	passports.On("GetByID", ctx, oldID).Return(&domain.Passport{ID: oldID, ManufacturerID: "mfg-1", Status: domain.StatusSuperseded, SupersededByID: &liveID}, nil)
+	passports.On("GetByID", ctx, liveID).Return(&domain.Passport{ID: liveID, ManufacturerID: "mfg-1", Status: domain.StatusPublished}, nil)
+	requests.On("Save", ctx, mock.MatchedBy(func(r *domain.AccessRequest) bool {
+		return r.PassportID == liveID && r.ManufacturerID == "mfg-1" && r.AccessLevel == domain.AccessLegitimateInterest && r.Status == domain.AccessRequestPending
+	})).Return(nil)
+	bus.On("Publish", ctx, "events:access_requested", mock.Anything).Return(nil)
+
+	req, err := svc.RequestAccess(ctx, oldID, "recycler-1", domain.AccessRequestInput{Purpose: " battery recycling "})
+
+	require.NoError(t, err)
+	assert.Equal(t, "battery recycling", req.Purpose)
+	requests.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
+
+func TestApproveAccessRequest_CreatesPassportGrant(t *testing.T) {
+	requests := new(MockAccessRequestRepository)
+	grants := new(MockGrantRepository)
+	passports := new(MockPassportRepository)
+	bus := new(MockEventBus)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	grantSvc := service.NewGrantService(grants, passports, bus, nil, logger)
+	svc := service.NewAccessRequestService(requests, passports, grantSvc, bus, nil, logger)
+	ctx := context.Background()
+
+	id, passportID := uuid.New(), uuid.New()
+	requests.On("GetByID", ctx, id).Return(&domain.AccessRequest{
+		ID: id, PassportID: passportID, ManufacturerID: "mfg-1", RequesterTenantID: "recycler-1",
+		AccessLevel: domain.AccessNotifiedBody, Status: domain.AccessRequestPending,
+	}, nil)
+	passports.On("GetByID", ctx, passportID).Return(&domain.Passport{ID: passportID, ManufacturerID: "mfg-1", Status: domain.StatusPublished}, nil)
+
+	var saved *domain.AccessGrant
+	grants.On("Save", ctx, mock.MatchedBy(func(g *domain.AccessGrant) bool {
+		saved = g
+		return g.Scope == domain.GrantScopePassport && *g.PassportID == passportID &&
+			g.GranteeTenantID == "recycler-1" && g.AccessLevel == domain.AccessNotifiedBody
+	})).Return(nil)
+	requests.On("Decide", ctx, mock.MatchedBy(func(r *domain.AccessRequest) bool {
+		return r.Status == domain.AccessRequestApproved && r.GrantID != nil && *r.GrantID == saved.ID
+	})).Return(nil)
+	bus.On("Publish", ctx, "events:access_granted", mock.Anything).Return(nil)
+	bus.On("Publish", ctx, "events:access_request_approved", mock.Anything).Return(nil)
+
+	req, err := svc.ApproveAccessRequest(ctx, id, "mfg-1", domain.AccessDecision{Note: "ok"})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessRequestApproved, req.Status)
+	// Time-limited by default
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), saved.ExpiresAt, time.Minute)
+	requests.AssertExpectations(t)
+	grants.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
+
+func TestApproveAccessRequest_GrantSurvivesRevision(t *testing.T) {
+	requests := new(MockAccessRequestRepository)
+	grants := new(MockGrantRepository)
+	passports := new(MockPassportRepository)
+	cache := new(MockCacheRepository)
+	blob := new(MockBlobStorage)
+	bus := new(MockEventBus)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewAccessRequestService(requests, passports, service.NewGrantService(grants, passports, bus, nil, logger), bus, nil, logger)
+	passportSvc, err := service.NewPassportService(passports, cache, blob, nil, grants, nil, nil, "https://tapi.eu", logger)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The request was approved on the first revision
+	id, firstID, revisionID := uuid.New(), uuid.New(), uuid.New()
+	first := &domain.Passport{ID: firstID, ProductCategory: domain.CategoryBattery, ManufacturerID: "mfg-1", Status: domain.StatusPublished, Version: 1}
+	requests.On("GetByID", ctx, id).Return(&domain.AccessRequest{
+		ID: id, PassportID: firstID, ManufacturerID: "mfg-1", RequesterTenantID: "recycler-1",
+		AccessLevel: domain.AccessLegitimateInterest, Status: domain.AccessRequestPending,
+	}, nil)
+	passports.On("GetByID", mock.Anything, firstID).Return(first, nil)
+	var saved *domain.AccessGrant
+	grants.On("Save", ctx, mock.MatchedBy(func(g *domain.AccessGrant) bool {
+		saved = g
+		return true
+	})).Return(nil)
+	requests.On("Decide", ctx, mock.Anything).Return(nil)
+	bus.On("Publish", ctx, mock.Anything, mock.Anything).Return(nil)
+
+	_, err = svc.ApproveAccessRequest(ctx, id, "mfg-1", domain.AccessDecision{})
+	require.NoError(t, err)
+
+	// The manufacturer then publishes a second revision
+	revision := &domain.Passport{
+		ID: revisionID, ProductCategory: domain.CategoryBattery, ManufacturerID: "mfg-1", ManufacturerName: "Acme Batteries GmbH",
+		ManufacturerDUNS: "123456789", ManufacturerCountry: "DE", Status: domain.StatusDraft, Version: 2, PreviousVersionID: &firstID,
+		Attributes: json.RawMessage(`{"batteryModel": "Test", "disassemblyInstructions": {"documentUrl": "https://example.com/d.pdf"}}`),
+	}
+	passports.On("GetByID", mock.Anything, revisionID).Return(revision, nil)
+	blob.On("UploadJSON", ctx, "passports", mock.Anything, mock.Anything).Return("s3://bucket/v2", nil)
+	passports.On("PublishBatch", ctx, mock.Anything).Return(nil)
+	cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	_, err = passportSvc.PublishPassport(ctx, revisionID, "mfg-1")
+	require.NoError(t, err)
+
+	// Expectations: the grant on the first revision is found through the revision chain
+	passports.On("FindVersionHistory", mock.Anything, revisionID).Return([]domain.PassportVersion{{PassportID: firstID, Version: 1}, {PassportID: revisionID, Version: 2}}, nil)
+	grants.On("FindActiveForPassport", mock.Anything, "recycler-1", revision, []uuid.UUID{revisionID, firstID}, mock.Anything).Return([]*domain.AccessGrant{saved}, nil)
+	cache.On("Get", mock.Anything, mock.Anything).Return("", errors.New("cache miss"))
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	recyclerCtx := context.WithValue(ctx, domain.ViewContextKey, domain.ViewContextRestricted)
+	recyclerCtx = context.WithValue(recyclerCtx, domain.ViewerTenantIDKey, "recycler-1")
+	p, err := passportSvc.GetPassport(recyclerCtx, revisionID)
+
+	require.NoError(t, err)
+	assert.Contains(t, string(p.Attributes), "disassemblyInstructions")
+	grants.AssertExpectations(t)
+}
+
+func TestApproveAccessRequest_ConcurrentDecisionRevokesGrant(t *testing.T) {
+	requests := new(MockAccessRequestRepository)
+	grants := new(MockGrantRepository)
+	passports := new(MockPassportRepository)
+	bus := new(MockEventBus)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewAccessRequestService(requests, passports, service.NewGrantService(grants, passports, bus, nil, logger), bus, nil, logger)
+	ctx := context.Background()
+
+	id, passportID := uuid.New(), uuid.New()
+	requests.On("GetByID", ctx, id).Return(&domain.AccessRequest{
+		ID: id, PassportID: passportID, ManufacturerID: "mfg-1", RequesterTenantID: "recycler-1",
+		AccessLevel: domain.AccessLegitimateInterest, Status: domain.AccessRequestPending,
+	}, nil)
+	passports.On("GetByID", ctx, passportID).Return(&domain.Passport{ID: passportID, ManufacturerID: "mfg-1"}, nil)
+	grants.On("Save", ctx, mock.Anything).Return(nil)
+	grants.On("GetByID", ctx, mock.Anything).Return(&domain.AccessGrant{ManufacturerID: "mfg-1"}, nil)
+	grants.On("Revoke", ctx, mock.Anything, mock.Anything).Return(nil)
+	requests.On("Decide", ctx, mock.Anything).Return(domain.ErrConflict)
+	bus.On("Publish", ctx, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := svc.ApproveAccessRequest(ctx, id, "mfg-1", domain.AccessDecision{})
+
+	assert.True(t, errors.Is(err, domain.ErrConflict))
+	grants.AssertCalled(t, "Revoke", ctx, mock.Anything, mock.Anything)
+	bus.AssertNotCalled(t, "Publish", ctx, "events:access_request_approved", mock.Anything)
+}
+
+func TestDenyAccessRequest_Rules(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	id := uuid.New()
+
+	t.Run("only the owner decides", func(t *testing.T) {
+		requests := new(MockAccessRequestRepository)
+		requests.On("GetByID", ctx, id).Return(&domain.AccessRequest{ID: id, ManufacturerID: "mfg-1", Status: domain.AccessRequestPending}, nil)
+		svc := service.NewAccessRequestService(requests, nil, nil, new(MockEventBus), nil, logger)
+
+		_, err := svc.DenyAccessRequest(ctx, id, "mfg-2", domain.AccessDecision{})
+
+		assert.True(t, errors.Is(err, domain.ErrForbidden))
+		requests.AssertNotCalled(t, "Decide", mock.Anything, mock.Anything)
+	})
+
+	t.Run("decided requests are final", func(t *testing.T) {
+		requests := new(MockAccessRequestRepository)
+		requests.On("GetByID", ctx, id).Return(&domain.AccessRequest{ID: id, ManufacturerID: "mfg-1", Status: domain.AccessRequestApproved}, nil)
+		svc := service.NewAccessRequestService(requests, nil, nil, new(MockEventBus), nil, logger)
+
+		_, err := svc.DenyAccessRequest(ctx, id, "mfg-1", domain.AccessDecision{})
+
+		assert.True(t, errors.Is(err, domain.ErrConflict))
+	})
+
+	t.Run("denial is recorded and published", func(t *testing.T) {
+		requests := new(MockAccessRequestRepository)
+		bus := new(MockEventBus)
+		requests.On("GetByID", ctx, id).Return(&domain.AccessRequest{ID: id, ManufacturerID: "mfg-1", Status: domain.AccessRequestPending}, nil)
+		requests.On("Decide", ctx, mock.MatchedBy(func(r *domain.AccessRequest) bool {
+			return r.Status == domain.AccessRequestDenied && r.DecisionNote == "no contract" && r.GrantID == nil
+		})).Return(nil)
+		bus.On("Publish", ctx, "events:access_request_denied", mock.Anything).Return(nil)
+		svc := service.NewAccessRequestService(requests, nil, nil, bus, nil, logger)
+
+		_, err := svc.DenyAccessRequest(ctx, id, "mfg-1", domain.AccessDecision{Note: "no contract"})
+
+		assert.NoError(t, err)
+		requests.AssertExpectations(t)
+		bus.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+// defaultScanPeriod is the period of a scan report that does not set one.
+const defaultScanPeriod = 30 * 24 * time.Hour
+
+type analyticsService struct {
+	scans ports.ScanRepository
+	log   *slog.Logger
+}
+
+// Ensure interface implementation
+var _ ports.AnalyticsService = (*analyticsService)(nil)
+
+func NewAnalyticsService(scans ports.ScanRepository, log *slog.Logger) ports.AnalyticsService {
+	return &analyticsService{scans: scans, log: log}
+}
+
+func (s *analyticsService) GetScanReport(ctx context.Context, manufacturerID string, query domain.ScanQuery) (*domain.ScanReport, error) {
+	// 1. Defaults: the last 30 days (today included), per day
+	if query.To.IsZero() {
+		query.To = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultScanPeriod)
+	}
+	if query.Bucket == "" {
+		query.Bucket = domain.ScanBucketDay
+	}
+
+	// 2. Validate
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 3. Read the Rollups
+	counts, err := s.scans.FindScanCounts(ctx, manufacturerID, query)
+	if err != nil {
+		s.log.Error("failed to read scan counts", "manufacturer", manufacturerID, "error", err)
+		return nil, fmt.Errorf("%w: could not read scan counts", domain.ErrInternal)
+	}
+	if counts == nil {
+		counts = []domain.ScanCount{}
+	}
+
+	return &domain.ScanReport{
+		From:       query.From,
+		To:         query.To,
+		Bucket:     query.Bucket,
+		GroupBy:    query.GroupBy,
+		PassportID: query.PassportID,
+		Counts:     counts,
+	}, nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "embed"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//go:embed schemas/master-envelope.json
+var masterEnvelopeRaw string
+
+// compileEnvelopeSchema compiles the master envelope. Unlike category payloads, its formats
+// (uuid, uri, date-time) are asserted: the envelope is generated, never user-supplied.
+func compileEnvelopeSchema() (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+
+	const url = "master-envelope.json"
+	if err := compiler.AddResource(url, strings.NewReader(masterEnvelopeRaw)); err != nil {
+		return nil, fmt.Errorf("failed to add master envelope schema: %w", err)
+	}
+	return compiler.Compile(url)
+}
+
+// buildEnvelope wraps a passport's attributes in the master envelope as it will be published.
+func (s *passportService) buildEnvelope(passport *domain.Passport, issuedAt time.Time) ([]byte, error) {
+	envelope := domain.PassportEnvelope{
+		PassportID:      passport.ID,
+		Status:          domain.StatusPublished,
+		ProductCategory: passport.ProductCategory,
+		Manufacturer: domain.EnvelopeManufacturer{
+			Name:       passport.ManufacturerName,
+			DUNSNumber: passport.ManufacturerDUNS,
+			Country:    passport.ManufacturerCountry,
+		},
+		PublicURL:    fmt.Sprintf("%s/r/%s", s.publicBaseURL, passport.ID),
+		IssuanceDate: issuedAt,
+		Payload:      passport.Attributes,
+	}
+
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	if err := s.envelope.Validate(doc); err != nil {
+		return nil, fmt.Errorf("%w: master envelope validation failed: %v", domain.ErrInvalidInput, err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+// streamBatchSize caps the events read from the bus per round.
+const streamBatchSize = 100
+
+// minStreamCursorLength is the size of the catalogue when the stream was introduced: cursors
+// issued since are at least that long.
+const minStreamCursorLength = 6
+
+type eventStreamService struct {
+	reader   ports.EventReader
+	channels []string // The catalogue's channels, in cursor order
+	log      *slog.Logger
+}
+
+// Ensure implementation
+var _ ports.EventStreamService = (*eventStreamService)(nil)
+
+func NewEventStreamService(reader ports.EventReader, log *slog.Logger) ports.EventStreamService {
+	channels := make([]string, 0, len(domain.EventTypes))
+	for _, eventType := range domain.EventTypes {
+		channels = append(channels, eventType.Channel())
+	}
+	return &eventStreamService{reader: reader, channels: channels, log: log}
+}
+
+// Open positions the stream. Entry IDs of different channels are not ordered against each
+// other, so the cursor holds the position reached in every channel: "<id>,<id>,...". A cursor
+// issued before event types were appended to the catalogue lacks their channels, which start
+// at their latest position.
+func (s *eventStreamService) Open(ctx context.Context, tenantID, lastEventID string) (ports.EventStream, error) {
+	if lastEventID == "" {
+		positions, err := s.reader.LatestPositions(ctx, s.channels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event stream positions: %w", err)
+		}
+		return &eventStream{reader: s.reader, channels: s.channels, tenantID: tenantID, positions: positions}, nil
+	}
+
+	ids := strings.Split(lastEventID, ",")
+	if len(ids) < minStreamCursorLength || len(ids) > len(s.channels) {
+		return nil, fmt.Errorf("%w: invalid Last-Event-ID", domain.ErrInvalidInput)
+	}
+	positions := make(map[string]string, len(s.channels))
+	for i, id := range ids {
+		if _, _, ok := parseEntryID(id); !ok {
+			return nil, fmt.Errorf("%w: invalid Last-Event-ID", domain.ErrInvalidInput)
+		}
+		positions[s.channels[i]] = id
+	}
+	if missing := s.channels[len(ids):]; len(missing) > 0 {
+		latest, err := s.reader.LatestPositions(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event stream positions: %w", err)
+		}
+		for _, channel := range missing {
+			positions[channel] = latest[channel]
+		}
+	}
+	return &eventStream{reader: s.reader, channels: s.channels, tenantID: tenantID, positions: positions}, nil
+}
+
+type eventStream struct {
+	reader    ports.EventReader
+	channels  []string
+	tenantID  string
+	positions map[string]string
+}
+
+// Next skips the other tenants' events, so it keeps reading until one of the tenant's arrives
+// or wait is over.
+func (st *eventStream) Next(ctx context.Context, wait time.Duration) ([]domain.StreamEvent, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		remaining := time.Until(deadline)
+		if remaining < time.Millisecond {
+			return nil, nil
+		}
+
+		msgs, err := st.reader.ReadEvents(ctx, st.positions, streamBatchSize, remaining)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read events: %w", err)
+		}
+		// Entries are ordered within a channel; across channels, their timestamps are close enough
+		slices.SortStableFunc(msgs, func(a, b domain.EventMessage) int {
+			return compareEntryIDs(a.ID, b.ID)
+		})
+
+		var events []domain.StreamEvent
+		for _, msg := range msgs {
+			st.positions[msg.Channel] = msg.ID
+
+			var envelope domain.Event
+			if err := json.Unmarshal(msg.Payload, &envelope); err != nil || envelope.Tenant != st.tenantID {
+				continue
+			}
+			events = append(events, domain.StreamEvent{Cursor: st.cursor(), Type: envelope.Type, Payload: msg.Payload})
+		}
+		if len(events) > 0 {
+			return events, nil
+		}
+	}
+}
+
+func (st *eventStream) cursor() string {
+	ids := make([]string, len(st.channels))
+	for i, channel := range st.channels {
+		ids[i] = st.positions[channel]
+	}
+	return strings.Join(ids, ",")
+}
+
+// parseEntryID splits a stream entry ID ("<milliseconds>-<sequence>").
+func parseEntryID(id string) (ms, seq uint64, ok bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
+
+func compareEntryIDs(a, b string) int {
+	aMs, aSeq, _ := parseEntryID(a)
+	bMs, bSeq, _ := parseEntryID(b)
+	if aMs != bMs {
+		return cmp.Compare(aMs, bMs)
+	}
+	return cmp.Compare(aSeq, bSeq)
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEventReader struct {
+	mock.Mock
+}
+
+func (m *MockEventReader) ReadEvents(ctx context.Context, positions map[string]string, count int64, block time.Duration) ([]domain.EventMessage, error) {
+	args := m.Called(ctx, positions, count, block)
+	events, _ := args.Get(0).([]domain.EventMessage)
+	return events, args.Error(1)
+}
+
+func (m *MockEventReader) LatestPositions(ctx context.Context, channels []string) (map[string]string, error) {
+	args := m.Called(ctx, channels)
+	positions, _ := args.Get(0).(map[string]string)
+	return positions, args.Error(1)
+}
+
+func streamMessage(t *testing.T, eventType domain.EventType, id, tenant string) domain.EventMessage {
+	event, err := domain.NewEvent(eventType, tenant, "subject", map[string]string{}, time.Now())
+	require.NoError(t, err)
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	return domain.EventMessage{ID: id, Channel: eventType.Channel(), Payload: payload}
+}
+
+func TestEventStream_FiltersTenantAndResumes(t *testing.T) {
+	reader := new(MockEventReader)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewEventStreamService(reader, logger)
+	ctx := context.Background()
+
+	positions := func() map[string]string {
+		positions := map[string]string{}
+		for _, eventType := range domain.EventTypes {
+			positions[eventType.Channel()] = "0-0"
+		}
+		return positions
+	}
+	published := streamMessage(t, domain.EventPassportPublished, "1700000000002-0", "mfg-1")
+	created := streamMessage(t, domain.EventPassportCreated, "1700000000001-0", "mfg-1")
+	other := streamMessage(t, domain.EventPassportCreated, "1700000000003-0", "mfg-2")
+
+	// Expectations: the stream starts at the latest positions
+	reader.On("LatestPositions", ctx, mock.Anything).Return(positions(), nil).Once()
+	reader.On("ReadEvents", ctx, mock.Anything, int64(100), mock.Anything).Return([]domain.EventMessage{published, created, other}, nil).Once()
+
+	// Execute
+	stream, err := svc.Open(ctx, "mfg-1", "")
+	require.NoError(t, err)
+	events, err := stream.Next(ctx, time.Second)
+
+	// Assertions: in ID order, without the other tenant's event
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, domain.EventPassportCreated, events[0].Type)
+	assert.Equal(t, domain.EventPassportPublished, events[1].Type)
+	assert.Equal(t, "1700000000001-0,0-0,0-0,0-0,0-0,0-0,0-0,0-0,0-0,0-0", events[0].Cursor)
+	assert.Equal(t, "1700000000001-0,0-0,1700000000002-0,0-0,0-0,0-0,0-0,0-0,0-0,0-0", events[1].Cursor)
+	assert.JSONEq(t, string(created.Payload), string(events[0].Payload))
+
+	// Resuming after the first event reads from its positions
+	resumed := positions()
+	resumed[domain.EventPassportCreated.Channel()] = "1700000000001-0"
+	reader.On("ReadEvents", ctx, resumed, int64(100), mock.Anything).Return([]domain.EventMessage{published}, nil).Once()
+
+	stream, err = svc.Open(ctx, "mfg-1", events[0].Cursor)
+	require.NoError(t, err)
+	events, err = stream.Next(ctx, time.Second)
+
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.EventPassportPublished, events[0].Type)
+
+	reader.AssertExpectations(t)
+}
+
+func TestEventStream_ResumesCursorOfOlderCatalogue(t *testing.T) {
+	reader := new(MockEventReader)
+	svc := service.NewEventStreamService(reader, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	// A cursor issued when the catalogue had its first six types: the channels appended since
+	// start at their latest position
+	var appended []string
+	latest := map[string]string{}
+	want := map[string]string{}
+	for i, eventType := range domain.EventTypes {
+		if i < 6 {
+			want[eventType.Channel()] = "1700000000001-0"
+			continue
+		}
+		appended = append(appended, eventType.Channel())
+		latest[eventType.Channel()] = "1700000000009-0"
+		want[eventType.Channel()] = "1700000000009-0"
+	}
+	reader.On("LatestPositions", ctx, appended).Return(latest, nil).Once()
+	reader.On("ReadEvents", ctx, want, int64(100), mock.Anything).Return(nil, nil)
+
+	stream, err := svc.Open(ctx, "mfg-1", strings.TrimSuffix(strings.Repeat("1700000000001-0,", 6), ","))
+	require.NoError(t, err)
+	_, err = stream.Next(ctx, 50*time.Millisecond)
+
+	require.NoError(t, err)
+	reader.AssertExpectations(t)
+}
+
+func TestEventStream_InvalidLastEventID(t *testing.T) {
+	svc := service.NewEventStreamService(new(MockEventReader), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, cursor := range []string{"garbage", "1-0,2-0", "1-0,0-0,x-0,0-0,0-0,0-0"} {
+		_, err := svc.Open(context.Background(), "mfg-1", cursor)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput, cursor)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type grantService struct {
+	grants    ports.GrantRepository
+	passports ports.PassportRepository
+	eventBus  ports.EventBus
+	levels    *domain.AccessLevels
+	log       *slog.Logger
+}
+
+// Ensure interface implementation
+var _ ports.GrantService = (*grantService)(nil)
+
+// NewGrantService wires the access grant use-cases. A nil access vocabulary
+// means domain.DefaultAccessLevels.
+func NewGrantService(grants ports.GrantRepository, passports ports.PassportRepository, eventBus ports.EventBus, accessLevels *domain.AccessLevels, log *slog.Logger) ports.GrantService {
+	if accessLevels == nil {
+		accessLevels = domain.MustDefaultAccessLevels()
+	}
+	return &grantService{grants: grants, passports: passports, eventBus: eventBus, levels: accessLevels, log: log}
+}
+
+func (s *grantService) CreateGrant(ctx context.Context, manufacturerID string, req domain.GrantRequest) (*domain.AccessGrant, error) {
+	now := time.Now().UTC()
+
+	// 1. Validate Grantee & Expiry
+	grantee := strings.TrimSpace(req.GranteeTenantID)
+	if grantee == "" {
+		return nil, fmt.Errorf("%w: granteeTenantId is required", domain.ErrInvalidInput)
+	}
+	if grantee == manufacturerID {
+		return nil, fmt.Errorf("%w: a manufacturer already sees its own passports", domain.ErrInvalidInput)
+	}
+	if !req.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiresAt must be in the future", domain.ErrInvalidInput)
+	}
+
+	// 2. Validate Access Level (defaults to the first tier above public)
+	level := req.AccessLevel
+	if level == "" {
+		level = s.levels.Levels()[1]
+	}
+	rank, known := s.levels.Rank(level)
+	if !known {
+		return nil, fmt.Errorf("%w: unknown access level %s", domain.ErrInvalidInput, level)
+	}
+	if rank == 0 {
+		return nil, fmt.Errorf("%w: public data needs no grant", domain.ErrInvalidInput)
+	}
+	if level == domain.AccessRestricted {
+		// Store the canonical name so later vocabulary changes don't shift the meaning
+		level = s.levels.Levels()[rank]
+	}
+
+	// 3. Validate Scope
+	grant := &domain.AccessGrant{
+		ID:              uuid.New(),
+		ManufacturerID:  manufacturerID,
+		GranteeTenantID: grantee,
+		Scope:           req.Scope,
+		AccessLevel:     level,
+		ExpiresAt:       req.ExpiresAt.UTC(),
+		CreatedAt:       now,
+	}
+
+	switch req.Scope {
+	case domain.GrantScopeManufacturer:
+	case domain.GrantScopeCategory:
+		if _, known := bootstrapSchemas[req.ProductCategory]; !known {
+			return nil, fmt.Errorf("%w: unsupported product category %s", domain.ErrInvalidInput, req.ProductCategory)
+		}
+		grant.ProductCategory = req.ProductCategory
+	case domain.GrantScopePassport:
+		if req.PassportID == nil {
+			return nil, fmt.Errorf("%w: passportId is required for PASSPORT scope", domain.ErrInvalidInput)
+		}
+		passport, err := s.passports.GetByID(ctx, *req.PassportID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch passport: %w", err)
+		}
+		if passport.ManufacturerID != manufacturerID {
+			return nil, domain.ErrForbidden
+		}
+		grant.PassportID = req.PassportID
+	default:
+		return nil, fmt.Errorf("%w: scope must be PASSPORT, CATEGORY or MANUFACTURER", domain.ErrInvalidInput)
+	}
+
+	// 4. Save
+	if err := s.grants.Save(ctx, grant); err != nil {
+		return nil, fmt.Errorf("failed to save grant: %w", err)
+	}
+
+	// 5. Publish Event
+	s.publish(ctx, domain.EventAccessGranted, grant, now)
+
+	s.log.Info("access grant created", "id", grant.ID, "manufacturer", manufacturerID, "grantee", grantee, "scope", grant.Scope, "level", grant.AccessLevel)
+	return grant, nil
+}
+
+func (s *grantService) ListGrants(ctx context.Context, manufacturerID string) ([]*domain.AccessGrant, error) {
+	return s.grants.FindByManufacturer(ctx, manufacturerID)
+}
+
+func (s *grantService) RevokeGrant(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.AccessGrant, error) {
+	// 1. Fetch & Check Ownership
+	grant, err := s.grants.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch grant: %w", err)
+	}
+	if grant.ManufacturerID != manufacturerID {
+		return nil, domain.ErrForbidden
+	}
+	if grant.RevokedAt != nil {
+		return grant, nil
+	}
+
+	// 2. Revoke
+	now := time.Now().UTC()
+	if err := s.grants.Revoke(ctx, id, now); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			// Revoked concurrently: the outcome is the same
+			return s.grants.GetByID(ctx, id)
+		}
+		return nil, fmt.Errorf("failed to revoke grant: %w", err)
+	}
+	grant.RevokedAt = &now
+
+	// 3. Publish Event
+	s.publish(ctx, domain.EventAccessRevoked, grant, now)
+
+	return grant, nil
+}
+
+func (s *grantService) publish(ctx context.Context, eventType domain.EventType, grant *domain.AccessGrant, at time.Time) {
+	event, err := domain.NewAccessGrantEvent(eventType, grant, at)
+	if err == nil {
+		err = s.eventBus.Publish(ctx, eventType.Channel(), event)
+	}
+	if err != nil {
+		s.log.Error("failed to publish grant event", "type", eventType, "error", err)
+	}
+}
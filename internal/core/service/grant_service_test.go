@@ -0,0 +1,244 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGrantRepository struct {
+	mock.Mock
+}
+
+func (m *MockGrantRepository) Save(ctx context.Context, grant *domain.AccessGrant) error {
+	args := m.Called(ctx, grant)
+	return args.Error(0)
+}
+
+func (m *MockGrantRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessGrant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessGrant), args.Error(1)
+}
+
+func (m *MockGrantRepository) FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.AccessGrant, error) {
+	args := m.Called(ctx, manufacturerID)
+	return args.Get(0).([]*domain.AccessGrant), args.Error(1)
+}
+
+func (m *MockGrantRepository) FindActiveForPassport(ctx context.Context, granteeTenantID string, passport *domain.Passport, revisions []uuid.UUID, now time.Time) ([]*domain.AccessGrant, error) {
+	args := m.Called(ctx, granteeTenantID, passport, revisions, now)
+	return args.Get(0).([]*domain.AccessGrant), args.Error(1)
+}
+
+func (m *MockGrantRepository) FindActiveForGrantee(ctx context.Context, granteeTenantID string, now time.Time) ([]*domain.AccessGrant, error) {
+	args := m.Called(ctx, granteeTenantID, now)
+	return args.Get(0).([]*domain.AccessGrant), args.Error(1)
+}
+
+func (m *MockGrantRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+func TestCreateGrant_Validation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	future := time.Now().Add(24 * time.Hour)
+	foreignID, ownID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		req     domain.GrantRequest
+		wantErr error
+	}{
+		{"missing grantee", domain.GrantRequest{Scope: domain.GrantScopeManufacturer, ExpiresAt: future}, domain.ErrInvalidInput},
+		{"self grant", domain.GrantRequest{GranteeTenantID: "mfg-1", Scope: domain.GrantScopeManufacturer, ExpiresAt: future}, domain.ErrInvalidInput},
+		{"expired", domain.GrantRequest{GranteeTenantID: "recycler-1", Scope: domain.GrantScopeManufacturer, ExpiresAt: time.Now().Add(-time.Minute)}, domain.ErrInvalidInput},
+		{"public level", domain.GrantRequest{GranteeTenantID: "recycler-1", Scope: domain.GrantScopeManufacturer, AccessLevel: domain.AccessPublic, ExpiresAt: future}, domain.ErrInvalidInput},
+		{"unknown level", domain.GrantRequest{GranteeTenantID: "recycler-1", Scope: domain.GrantScopeManufacturer, AccessLevel: "root", ExpiresAt: future}, domain.ErrInvalidInput},
+		{"unknown scope", domain.GrantRequest{GranteeTenantID: "recycler-1", Scope: "WORLD", ExpiresAt: future}, domain.ErrInvalidInput},
+		{"unknown category", domain.GrantRequest{GranteeTenantID: "recycler-1", Scope: domain.GrantScopeCategory, ProductCategory: "FURNITURE", ExpiresAt: future}, domain.ErrInvalidInput},
+		{"passport scope without id", domain.GrantRequest{GranteeTenantID: "recycler-1", Scope: domain.GrantScopePassport, ExpiresAt: future}, domain.ErrInvalidInput},
+		{"foreign passport", domain.GrantRequest{GranteeTenantID: "recycler-1", Scope: domain.GrantScopePassport, PassportID: &foreignID, ExpiresAt: future}, domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants := new(MockGrantRepository)
+			passports := new(MockPassportRepository)
+			passports.On("GetByID", mock.Anything, foreignID).Return(&domain.Passport{ID: foreignID, ManufacturerID: "mfg-2"}, nil)
+			passports.On("GetByID", mock.Anything, ownID).Return(&domain.Passport{ID: ownID, ManufacturerID: "mfg-1"}, nil)
+			svc := service.NewGrantService(grants, passports, new(MockEventBus), nil, logger)
+
+			_, err := svc.CreateGrant(ctx, "mfg-1", tt.req)
+
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+			grants.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateGrant_Success(t *testing.T) {
+	grants := new(MockGrantRepository)
+	bus := new(MockEventBus)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewGrantService(grants, new(MockPassportRepository), bus, nil, logger)
+	ctx := context.Background()
+
+	grants.On("Save", ctx, mock.MatchedBy(func(g *domain.AccessGrant) bool {
+		// The legacy alias is stored under its canonical name
+		return g.AccessLevel == domain.AccessLegitimateInterest && g.ProductCategory == domain.CategoryBattery
+	})).Return(nil)
+	bus.On("Publish", ctx, "events:access_granted", mock.Anything).Return(nil)
+
+	grant, err := svc.CreateGrant(ctx, "mfg-1", domain.GrantRequest{
+		GranteeTenantID: "recycler-1",
+		Scope:           domain.GrantScopeCategory,
+		ProductCategory: domain.CategoryBattery,
+		AccessLevel:     domain.AccessRestricted,
+		ExpiresAt:       time.Now().Add(24 * time.Hour),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mfg-1", grant.ManufacturerID)
+	grants.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
+
+func TestRevokeGrant_OnlyIssuer(t *testing.T) {
+	grants := new(MockGrantRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewGrantService(grants, new(MockPassportRepository), new(MockEventBus), nil, logger)
+	ctx := context.Background()
+
+	id := uuid.New()
+	grants.On("GetByID", ctx, id).Return(&domain.AccessGrant{ID: id, ManufacturerID: "mfg-1"}, nil)
+
+	_, err := svc.RevokeGrant(ctx, id, "mfg-2")
+
+	assert.True(t, errors.Is(err, domain.ErrForbidden))
+	grants.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetPassport_GrantRevealsRestrictedFields(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	id := uuid.New()
+	fullAttributes := `{"batteryModel": "Test", "disassemblyInstructions": {"documentUrl": "https://example.com/d.pdf"}}`
+
+	recyclerCtx := context.WithValue(context.Background(), domain.ViewContextKey, domain.ViewContextRestricted)
+	recyclerCtx = context.WithValue(recyclerCtx, domain.ViewerTenantIDKey, "recycler-1")
+
+	tests := []struct {
+		name        string
+		grants      []*domain.AccessGrant
+		wantVisible bool
+	}{
+		{"no grant", nil, false},
+		{"active manufacturer grant", []*domain.AccessGrant{
+			{ManufacturerID: "mfg-1", Scope: domain.GrantScopeManufacturer, AccessLevel: domain.AccessLegitimateInterest, ExpiresAt: time.Now().Add(time.Hour)},
+		}, true},
+		{"grant for another category", []*domain.AccessGrant{
+			{ManufacturerID: "mfg-1", Scope: domain.GrantScopeCategory, ProductCategory: domain.CategoryTextile, AccessLevel: domain.AccessLegitimateInterest, ExpiresAt: time.Now().Add(time.Hour)},
+		}, false},
+		{"expired grant", []*domain.AccessGrant{
+			{ManufacturerID: "mfg-1", Scope: domain.GrantScopeManufacturer, AccessLevel: domain.AccessLegitimateInterest, ExpiresAt: time.Now().Add(-time.Hour)},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPassportRepository)
+			mockCache := new(MockCacheRepository)
+			grants := new(MockGrantRepository)
+			svc, err := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, grants, nil, nil, "https://tapi.eu", logger)
+			assert.NoError(t, err)
+
+			mockCache.On("Get", mock.Anything, mock.Anything).Return("", errors.New("cache miss"))
+			mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Passport{
+				ID:              id,
+				ProductCategory: domain.CategoryBattery,
+				ManufacturerID:  "mfg-1",
+				Attributes:      json.RawMessage(fullAttributes),
+			}, nil)
+			grants.On("FindActiveForPassport", mock.Anything, "recycler-1", mock.Anything, []uuid.UUID{id}, mock.Anything).Return(tt.grants, nil)
+
+			p, err := svc.GetPassport(recyclerCtx, id)
+
+			assert.NoError(t, err)
+			var attrs map[string]interface{}
+			json.Unmarshal(p.Attributes, &attrs)
+			assert.Equal(t, "Test", attrs["batteryModel"])
+			if tt.wantVisible {
+				assert.NotNil(t, attrs["disassemblyInstructions"])
+			} else {
+				assert.Nil(t, attrs["disassemblyInstructions"])
+			}
+		})
+	}
+}
+
+func TestSearchText_PassportGrantCoversRevisions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	grants := new(MockGrantRepository)
+	svc, err := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, grants, nil, nil, "https://tapi.eu", logger)
+	assert.NoError(t, err)
+
+	recyclerCtx := context.WithValue(context.Background(), domain.ViewContextKey, domain.ViewContextRestricted)
+	recyclerCtx = context.WithValue(recyclerCtx, domain.ViewerTenantIDKey, "recycler-1")
+
+	// The grant names the first revision; search only finds the current one
+	firstID, currentID := uuid.New(), uuid.New()
+	grant := &domain.AccessGrant{ManufacturerID: "mfg-1", Scope: domain.GrantScopePassport, PassportID: &firstID,
+		AccessLevel: domain.AccessLegitimateInterest, ExpiresAt: time.Now().Add(time.Hour)}
+	grants.On("FindActiveForGrantee", mock.Anything, "recycler-1", mock.Anything).Return([]*domain.AccessGrant{grant}, nil)
+	grants.On("FindActiveForPassport", mock.Anything, "recycler-1", mock.Anything, []uuid.UUID{currentID, firstID}, mock.Anything).Return([]*domain.AccessGrant{grant}, nil)
+	mockRepo.On("FindVersionHistory", mock.Anything, firstID).Return([]domain.PassportVersion{{PassportID: firstID}, {PassportID: currentID}}, nil)
+	mockRepo.On("FindVersionHistory", mock.Anything, currentID).Return([]domain.PassportVersion{{PassportID: firstID}, {PassportID: currentID}}, nil)
+	mockRepo.On("SearchText", mock.Anything, mock.MatchedBy(func(q domain.TextSearchQuery) bool {
+		return len(q.Grants) == 1 && assert.ObjectsAreEqual([]uuid.UUID{firstID, currentID}, q.Grants[0].Revisions)
+	})).Return([]domain.TextSearchHit{{PassportID: currentID, Score: 0.2}}, nil)
+	mockCache.On("Get", mock.Anything, mock.Anything).Return("", errors.New("cache miss"))
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, currentID).Return(&domain.Passport{
+		ID:                currentID,
+		ProductCategory:   domain.CategoryBattery,
+		ManufacturerID:    "mfg-1",
+		Status:            domain.StatusPublished,
+		Version:           2,
+		PreviousVersionID: &firstID,
+		Attributes:        json.RawMessage(`{"batteryModel": "Test", "disassemblyInstructions": {"documentUrl": "https://example.com/d.pdf"}}`),
+	}, nil)
+
+	results, err := svc.SearchText(recyclerCtx, "disassembly", 0)
+
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Contains(t, string(results[0].Passport.Attributes), "disassemblyInstructions")
+	}
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,523 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// importBatchSize is the number of passports sent per COPY.
+	importBatchSize = 500
+
+	// maxImportLineBytes bounds a single NDJSON line.
+	maxImportLineBytes = 1 << 20
+)
+
+// importRecord is a parsed line, before validation.
+type importRecord struct {
+	line    int
+	ids     domain.ProductIdentifiers
+	payload []byte
+	err     error // Set when the line itself could not be parsed
+}
+
+// recordReader yields the records of an import body; io.EOF ends the stream.
+type recordReader interface {
+	next() (importRecord, error)
+}
+
+// pendingPassport is a validated record waiting for its batch to be written.
+type pendingPassport struct {
+	line     int
+	hash     string
+	passport *domain.Passport
+}
+
+func (s *passportService) ImportPassports(ctx context.Context, manufacturerID string, manufacturerName string, req domain.ImportRequest, body io.Reader) (*domain.ImportReport, error) {
+	// 1. Open the Record Stream
+	records, err := newRecordReader(req, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Resolve the Schema & Parent once for the whole import
+	compiled, err := s.schemas.active(ctx, req.Category)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			return nil, err
+		}
+		s.log.Error("failed to resolve schema", "category", req.Category, "error", err)
+		return nil, fmt.Errorf("%w: schema unavailable", domain.ErrInternal)
+	}
+
+	hierarchy, err := req.Hierarchy.Normalize(domain.ProductIdentifiers{})
+	if err != nil {
+		return nil, err
+	}
+	var parent *domain.Passport
+	var inherited interface{} // The parent's merged attributes
+	if hierarchy.ParentID != nil {
+		if parent, err = s.resolveParent(ctx, manufacturerID, req.Category, hierarchy); err != nil {
+			return nil, err
+		}
+		if inherited, err = s.mergedAttributes(ctx, parent); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Validate each Record, writing them in batches
+	report := &domain.ImportReport{}
+	seen := make(map[string]uuid.UUID)     // Idempotency hashes of this import
+	prefixChecks := make(map[string]error) // checkCompanyPrefix outcome per GTIN
+	pending := make([]pendingPassport, 0, importBatchSize)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err // Client gone or job cancelled: the written batches are kept
+		}
+		record, err := records.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if record.err != nil {
+			report.Add(domain.ImportLineResult{Line: record.line, Error: record.err.Error()})
+			continue
+		}
+
+		p, err := s.prepareImport(ctx, manufacturerID, manufacturerName, req.Category, hierarchy, parent, inherited, compiled, record)
+		if err != nil {
+			report.Add(domain.ImportLineResult{Line: record.line, Error: err.Error()})
+			continue
+		}
+		prefixErr, checked := prefixChecks[p.passport.GTIN]
+		if !checked {
+			prefixErr = s.checkCompanyPrefix(ctx, manufacturerID, p.passport.GTIN)
+			if prefixErr != nil && !errors.Is(prefixErr, domain.ErrForbidden) {
+				return nil, prefixErr
+			}
+			prefixChecks[p.passport.GTIN] = prefixErr
+		}
+		if prefixErr != nil {
+			report.Add(domain.ImportLineResult{Line: record.line, Error: prefixErr.Error()})
+			continue
+		}
+
+		// Already imported (in this body or before): report the existing passport
+		if id, ok := seen[p.hash]; ok {
+			report.Add(domain.ImportLineResult{Line: record.line, PassportID: &id, Duplicate: true})
+			continue
+		}
+		if existing, err := s.cache.GetIdempotency(ctx, p.hash); err == nil {
+			if id, parseErr := uuid.Parse(existing); parseErr == nil {
+				seen[p.hash] = id
+				report.Add(domain.ImportLineResult{Line: record.line, PassportID: &id, Duplicate: true})
+				continue
+			}
+		}
+
+		seen[p.hash] = p.passport.ID
+		pending = append(pending, p)
+		if len(pending) == importBatchSize {
+			if err := s.flushImport(ctx, pending, report); err != nil {
+				return nil, err
+			}
+			pending = pending[:0]
+			if req.Progress != nil {
+				req.Progress(record.line)
+			}
+		}
+	}
+	if err := s.flushImport(ctx, pending, report); err != nil {
+		return nil, err
+	}
+
+	// 4. Report in input order
+	sort.Slice(report.Lines, func(i, j int) bool { return report.Lines[i].Line < report.Lines[j].Line })
+
+	s.log.Info("passports imported", "manufacturer", manufacturerID, "category", req.Category,
+		"accepted", report.Accepted, "duplicates", report.Duplicates, "rejected", report.Rejected)
+	return report, nil
+}
+
+// prepareImport runs the CreatePassport checks on one record.
+func (s *passportService) prepareImport(ctx context.Context, manufacturerID, manufacturerName string, category domain.ProductCategory, hierarchy domain.PassportHierarchy, parent *domain.Passport, inherited interface{}, compiled *compiledSchema, record importRecord) (pendingPassport, error) {
+	// 1. GS1 Identifiers & Hierarchy Level
+	hierarchy, err := hierarchy.Normalize(record.ids)
+	if err != nil {
+		return pendingPassport{}, err
+	}
+	ids := record.ids
+	if parent != nil {
+		if ids, err = inheritIdentifiers(parent, ids); err != nil {
+			return pendingPassport{}, err
+		}
+	}
+	if ids, err = ids.Normalize(); err != nil {
+		return pendingPassport{}, err
+	}
+
+	// 2. Schema Validation (merged document)
+	doc, err := decodeAttributes(record.payload)
+	if err != nil {
+		return pendingPassport{}, err
+	}
+	if parent != nil {
+		doc = mergePatch(inherited, doc)
+	}
+	if err := validateAttributes(compiled.schema, doc, hierarchy.Level); err != nil {
+		return pendingPassport{}, fmt.Errorf("%w: schema validation failed: %v", domain.ErrInvalidInput, err)
+	}
+
+	// 3. Construct Domain Entity (its event is written with it)
+	now := time.Now().UTC()
+	passport := &domain.Passport{
+		ID:               uuid.New(),
+		ProductCategory:  category,
+		Status:           domain.StatusDraft,
+		ManufacturerID:   manufacturerID,
+		ManufacturerName: manufacturerName,
+		GTIN:             ids.GTIN,
+		BatchNumber:      ids.BatchNumber,
+		SerialNumber:     ids.SerialNumber,
+		Level:            hierarchy.Level,
+		ParentID:         hierarchy.ParentID,
+		Attributes:       json.RawMessage(record.payload),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Version:          1,
+		SchemaVersion:    compiled.version,
+	}
+	s.indexText(passport, compiled, doc)
+	if err := passport.RecordEvent(domain.EventPassportCreated); err != nil {
+		return pendingPassport{}, err
+	}
+
+	return pendingPassport{
+		line:     record.line,
+		hash:     idempotencyHash(manufacturerID, category, ids, hierarchy, record.payload),
+		passport: passport,
+	}, nil
+}
+
+// flushImport writes a batch with a single COPY. When some GS1 keys are already taken the
+// whole COPY is rejected, so the batch is replayed row by row to find the offending lines.
+func (s *passportService) flushImport(ctx context.Context, pending []pendingPassport, report *domain.ImportReport) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	batch := make([]*domain.Passport, len(pending))
+	for i, p := range pending {
+		batch[i] = p.passport
+	}
+
+	saved := make([]bool, len(pending))
+	err := s.repo.SaveBatch(ctx, batch)
+	switch {
+	case err == nil:
+		for i := range saved {
+			saved[i] = true
+		}
+	case errors.Is(err, domain.ErrConflict):
+		for i, p := range pending {
+			if err := s.repo.Save(ctx, p.passport); err != nil {
+				if !errors.Is(err, domain.ErrConflict) {
+					s.log.Error("failed to persist imported passport", "line", p.line, "error", err)
+					return fmt.Errorf("%w: failed to save", domain.ErrInternal)
+				}
+				report.Add(domain.ImportLineResult{Line: p.line, Error: "GS1 identifiers already registered"})
+				continue
+			}
+			saved[i] = true
+		}
+	default:
+		s.log.Error("failed to persist imported passports", "count", len(batch), "error", err)
+		return fmt.Errorf("%w: failed to save", domain.ErrInternal)
+	}
+
+	for i, p := range pending {
+		if !saved[i] {
+			continue
+		}
+		if err := s.cache.SetIdempotency(ctx, p.hash, p.passport.ID.String()); err != nil {
+			s.log.Warn("failed to set idempotency key", "error", err)
+		}
+
+		id := p.passport.ID
+		report.Add(domain.ImportLineResult{Line: p.line, PassportID: &id})
+	}
+	return nil
+}
+
+func newRecordReader(req domain.ImportRequest, body io.Reader) (recordReader, error) {
+	switch req.Format {
+	case domain.ImportFormatNDJSON:
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 64*1024), maxImportLineBytes)
+		return &ndjsonReader{scanner: scanner}, nil
+	case domain.ImportFormatCSV:
+		return newCSVReader(req.Mapping, body)
+	default:
+		return nil, fmt.Errorf("%w: format must be ndjson or csv", domain.ErrInvalidInput)
+	}
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) next() (importRecord, error) {
+	for r.scanner.Scan() {
+		r.line++
+		raw := bytes.TrimSpace(r.scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		record := importRecord{line: r.line}
+		var line domain.ImportRecord
+		if err := json.Unmarshal(raw, &line); err != nil {
+			record.err = fmt.Errorf("%w: invalid JSON", domain.ErrInvalidInput)
+			return record, nil
+		}
+		if len(line.Attributes) == 0 || string(line.Attributes) == "null" {
+			record.err = fmt.Errorf("%w: attributes is required", domain.ErrInvalidInput)
+			return record, nil
+		}
+		record.ids = domain.ProductIdentifiers{GTIN: line.GTIN, BatchNumber: line.BatchNumber, SerialNumber: line.SerialNumber}
+		record.payload = line.Attributes
+		return record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return importRecord{}, fmt.Errorf("%w: line %d exceeds %d bytes", domain.ErrInvalidInput, r.line+1, maxImportLineBytes)
+		}
+		return importRecord{}, fmt.Errorf("failed to read import body: %w", err)
+	}
+	return importRecord{}, io.EOF
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns []domain.ColumnTarget // By column index; the zero value marks unmapped columns
+}
+
+func newCSVReader(mapping domain.ColumnMapping, body io.Reader) (*csvReader, error) {
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("%w: a column mapping is required for CSV imports", domain.ErrInvalidInput)
+	}
+
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read CSV header: %v", domain.ErrInvalidInput, err)
+	}
+
+	columns := make([]domain.ColumnTarget, len(header))
+	found := make(map[string]bool, len(mapping))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if target, ok := mapping[name]; ok {
+			columns[i] = target
+			found[name] = true
+		}
+	}
+	for name := range mapping {
+		if !found[name] {
+			return nil, fmt.Errorf("%w: mapped column %q is not in the CSV header", domain.ErrInvalidInput, name)
+		}
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) next() (importRecord, error) {
+	row, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return importRecord{}, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return importRecord{line: parseErr.StartLine, err: fmt.Errorf("%w: %v", domain.ErrInvalidInput, parseErr.Err)}, nil
+		}
+		return importRecord{}, fmt.Errorf("failed to read import body: %w", err)
+	}
+	line, _ := r.reader.FieldPos(0)
+	record := importRecord{line: line}
+
+	var doc interface{} = map[string]interface{}{}
+	for i, cell := range row {
+		target := r.columns[i]
+		if target.Pointer == "" || strings.TrimSpace(cell) == "" {
+			continue // Unmapped column, or field left out
+		}
+		switch target.Pointer {
+		case domain.ImportTargetGTIN:
+			record.ids.GTIN = strings.TrimSpace(cell)
+			continue
+		case domain.ImportTargetLot:
+			record.ids.BatchNumber = strings.TrimSpace(cell)
+			continue
+		case domain.ImportTargetSerial:
+			record.ids.SerialNumber = strings.TrimSpace(cell)
+			continue
+		}
+
+		value, err := convertCell(cell, target.Type)
+		if err != nil {
+			record.err = fmt.Errorf("%w: column %d: %v", domain.ErrInvalidInput, i+1, err)
+			return record, nil
+		}
+		if doc, err = setPointer(doc, splitPointer(target.Pointer), value); err != nil {
+			record.err = fmt.Errorf("%w: %s: %v", domain.ErrInvalidInput, target.Pointer, err)
+			return record, nil
+		}
+	}
+
+	if record.payload, err = json.Marshal(doc); err != nil {
+		record.err = fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	return record, nil
+}
+
+// convertCell turns a CSV cell into the JSON value of the column type.
+func convertCell(cell string, typ domain.ColumnType) (interface{}, error) {
+	trimmed := strings.TrimSpace(cell)
+	switch typ {
+	case domain.ColumnNumber:
+		if _, err := strconv.ParseFloat(trimmed, 64); err != nil {
+			return nil, fmt.Errorf("%q is not a number", cell)
+		}
+		return json.Number(trimmed), nil
+	case domain.ColumnInteger:
+		if _, err := strconv.ParseInt(trimmed, 10, 64); err != nil {
+			return nil, fmt.Errorf("%q is not an integer", cell)
+		}
+		return json.Number(trimmed), nil
+	case domain.ColumnBoolean:
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", cell)
+		}
+		return b, nil
+	case domain.ColumnJSON:
+		value, err := decodeAttributes([]byte(cell))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON value")
+		}
+		return value, nil
+	default:
+		return cell, nil
+	}
+}
+
+// splitPointer splits a JSON pointer (RFC 6901) into unescaped reference tokens.
+func splitPointer(pointer string) []string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// setPointer stores value at the path, creating objects (and arrays, for numeric tokens) on the way.
+func setPointer(container interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token := tokens[0]
+
+	if index, err := strconv.Atoi(token); err == nil && index >= 0 {
+		if arr, ok := container.([]interface{}); ok || container == nil {
+			if index > domain.MaxImportArrayIndex {
+				return nil, fmt.Errorf("array index %d is above %d", index, domain.MaxImportArrayIndex)
+			}
+			for len(arr) <= index {
+				arr = append(arr, nil)
+			}
+			child, err := setPointer(arr[index], tokens[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			arr[index] = child
+			return arr, nil
+		}
+	}
+
+	obj, ok := container.(map[string]interface{})
+	if !ok {
+		if container != nil {
+			return nil, fmt.Errorf("conflicts with another column")
+		}
+		obj = map[string]interface{}{}
+	}
+	child, err := setPointer(obj[token], tokens[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	obj[token] = child
+	return obj, nil
+}
+
+type importJob struct {
+	passports ports.PassportService
+}
+
+// NewImportJob runs JobTypeImport jobs: the stored body is imported with ImportPassports
+// and the report becomes the job result. Retrying is safe thanks to idempotency hashing.
+func NewImportJob(passports ports.PassportService) ports.JobHandler {
+	return &importJob{passports: passports}
+}
+
+func (j *importJob) Run(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error) {
+	var params domain.ImportJobParams
+	if err := json.Unmarshal(job.Params, &params); err != nil {
+		return nil, fmt.Errorf("%w: invalid job parameters", domain.ErrInvalidInput)
+	}
+
+	// Progress counts input lines, as the report does
+	total := bytes.Count(input, []byte("\n"))
+	if len(input) > 0 && input[len(input)-1] != '\n' {
+		total++
+	}
+	progress(domain.JobProgress{Total: total})
+
+	req := params.Request
+	req.Progress = func(lines int) { progress(domain.JobProgress{Done: lines, Total: total}) }
+	report, err := j.passports.ImportPassports(ctx, job.TenantID, params.ManufacturerName, req, bytes.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	progress(domain.JobProgress{Done: total, Total: total})
+
+	return json.Marshal(report)
+}
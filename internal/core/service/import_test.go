@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const textileAttributes = `{"garmentType": "T-Shirt", "fiberComposition": [{"fiberName": "COTTON", "percentage": 100}], "origin": {}, "recyclability": {}}`
+
+func TestImportPassports_NDJSON(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	body := strings.Join([]string{
+		`{"gtin": "9506000134352", "serial": "S1", "attributes": ` + textileAttributes + `}`,
+		`{"gtin": "9506000134352", "serial": "S2", "attributes": {"garmentType": "T-Shirt"}}`,
+		`not json`,
+		``,
+		`{"gtin": "9506000134352", "serial": "S1", "attributes": ` + textileAttributes + `}`,
+		`{"gtin": "9506000134352", "serial": "S3", "attributes": ` + textileAttributes + `}`,
+	}, "\n")
+
+	// Expectations: S3 was imported before, S1 goes through a single COPY
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("miss")).Once()
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("3f6c1c7e-5d43-4a5b-9d1b-2c7f8a4c2e10", nil).Once()
+	mockRepo.On("SaveBatch", ctx, mock.MatchedBy(func(batch []*domain.Passport) bool {
+		return len(batch) == 1 && batch[0].GTIN == "09506000134352" && batch[0].SerialNumber == "S1" &&
+			batch[0].Status == domain.StatusDraft && batch[0].Level == domain.LevelItem &&
+			len(batch[0].Events) == 1 && batch[0].Events[0].Channel == "events:passport_created"
+	})).Return(nil).Once()
+	mockCache.On("SetIdempotency", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Execute
+	report, err := svc.ImportPassports(ctx, "mfg-1", "Manufacturer 1", domain.ImportRequest{Category: domain.CategoryTextile, Format: domain.ImportFormatNDJSON}, strings.NewReader(body))
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Accepted)
+	assert.Equal(t, 2, report.Duplicates)
+	assert.Equal(t, 2, report.Rejected)
+	require.Len(t, report.Lines, 5)
+
+	assert.Equal(t, 1, report.Lines[0].Line)
+	require.NotNil(t, report.Lines[0].PassportID)
+	assert.Contains(t, report.Lines[1].Error, "schema validation failed")
+	assert.Equal(t, 3, report.Lines[2].Line)
+	assert.Contains(t, report.Lines[2].Error, "invalid JSON")
+	assert.Equal(t, 5, report.Lines[3].Line)
+	assert.True(t, report.Lines[3].Duplicate)
+	assert.Equal(t, *report.Lines[0].PassportID, *report.Lines[3].PassportID, "repeated line reports the passport of its first occurrence")
+	assert.True(t, report.Lines[4].Duplicate)
+	assert.Equal(t, "3f6c1c7e-5d43-4a5b-9d1b-2c7f8a4c2e10", report.Lines[4].PassportID.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportPassports_CSVMapping(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	req := domain.ImportRequest{
+		Category: domain.CategoryTextile,
+		Format:   domain.ImportFormatCSV,
+		Mapping: domain.ColumnMapping{
+			"EAN":      {Pointer: domain.ImportTargetGTIN, Type: domain.ColumnString},
+			"Serial":   {Pointer: domain.ImportTargetSerial, Type: domain.ColumnString},
+			"Garment":  {Pointer: "/garmentType", Type: domain.ColumnString},
+			"Fiber":    {Pointer: "/fiberComposition/0/fiberName", Type: domain.ColumnString},
+			"Percent":  {Pointer: "/fiberComposition/0/percentage", Type: domain.ColumnNumber},
+			"Recycled": {Pointer: "/fiberComposition/0/isRecycled", Type: domain.ColumnBoolean},
+			"Origin":   {Pointer: "/origin", Type: domain.ColumnJSON},
+			"Recycle":  {Pointer: "/recyclability", Type: domain.ColumnJSON},
+		},
+	}
+	body := "EAN,Serial,Garment,Fiber,Percent,Recycled,Origin,Recycle,Notes\n" +
+		"9506000134352,S1,T-Shirt,COTTON,100,true,{},{},ignored\n" +
+		"9506000134352,S2,T-Shirt,COTTON,lots,,{},{},\n"
+
+	var saved []*domain.Passport
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("miss"))
+	mockRepo.On("SaveBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).([]*domain.Passport)
+	}).Return(nil).Once()
+	mockCache.On("SetIdempotency", ctx, mock.Anything, mock.Anything).Return(nil)
+
+	report, err := svc.ImportPassports(ctx, "mfg-1", "Manufacturer 1", req, strings.NewReader(body))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Accepted)
+	assert.Equal(t, 1, report.Rejected)
+	assert.Equal(t, 3, report.Lines[1].Line)
+	assert.Contains(t, report.Lines[1].Error, "not a number")
+
+	require.Len(t, saved, 1)
+	assert.Equal(t, "S1", saved[0].SerialNumber)
+	var attrs map[string]interface{}
+	require.NoError(t, json.Unmarshal(saved[0].Attributes, &attrs))
+	assert.Equal(t, []interface{}{map[string]interface{}{"fiberName": "COTTON", "percentage": float64(100), "isRecycled": true}}, attrs["fiberComposition"])
+	assert.NotContains(t, attrs, "Notes")
+}
+
+func TestImportPassports_ConflictingBatchFallsBackToRows(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	body := `{"gtin": "9506000134352", "serial": "S1", "attributes": ` + textileAttributes + `}` + "\n" +
+		`{"gtin": "9506000134352", "serial": "S2", "attributes": ` + textileAttributes + `}` + "\n"
+
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("miss"))
+	mockRepo.On("SaveBatch", ctx, mock.Anything).Return(domain.ErrConflict).Once()
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(p *domain.Passport) bool { return p.SerialNumber == "S1" })).Return(domain.ErrConflict).Once()
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(p *domain.Passport) bool { return p.SerialNumber == "S2" })).Return(nil).Once()
+	mockCache.On("SetIdempotency", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+	report, err := svc.ImportPassports(ctx, "mfg-1", "Manufacturer 1", domain.ImportRequest{Category: domain.CategoryTextile, Format: domain.ImportFormatNDJSON}, strings.NewReader(body))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Accepted)
+	assert.Equal(t, 1, report.Rejected)
+	assert.Equal(t, "GS1 identifiers already registered", report.Lines[0].Error)
+	assert.NotNil(t, report.Lines[1].PassportID)
+	mockRepo.AssertExpectations(t)
+}
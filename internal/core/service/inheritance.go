@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// maxHierarchyDepth bounds the parent walk (model <- batch <- item).
+const maxHierarchyDepth = 2
+
+// resolveParent checks that a new passport may inherit from its parent: same manufacturer and
+// category, a coarser level, and a PUBLISHED (hence immutable) parent.
+func (s *passportService) resolveParent(ctx context.Context, manufacturerID string, category domain.ProductCategory, hierarchy domain.PassportHierarchy) (*domain.Passport, error) {
+	parent, err := s.repo.GetByID(ctx, *hierarchy.ParentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: parent passport %s not found", domain.ErrInvalidInput, hierarchy.ParentID)
+		}
+		return nil, fmt.Errorf("failed to fetch parent passport: %w", err)
+	}
+
+	if parent.ManufacturerID != manufacturerID {
+		return nil, domain.ErrForbidden
+	}
+	if parent.ProductCategory != category {
+		return nil, fmt.Errorf("%w: parent passport is a %s", domain.ErrInvalidInput, parent.ProductCategory)
+	}
+	if !hierarchy.Level.CanInheritFrom(levelOf(parent)) {
+		return nil, fmt.Errorf("%w: a %s passport cannot inherit from a %s passport", domain.ErrInvalidInput, hierarchy.Level, levelOf(parent))
+	}
+	if parent.Status != domain.StatusPublished {
+		return nil, fmt.Errorf("%w: parent passport must be published (is %s)", domain.ErrInvalidInput, parent.Status)
+	}
+	return parent, nil
+}
+
+// inheritIdentifiers fills the GS1 keys a child leaves out from its parent. A child cannot
+// claim another GTIN or batch than its parent.
+func inheritIdentifiers(parent *domain.Passport, ids domain.ProductIdentifiers) (domain.ProductIdentifiers, error) {
+	if parent.GTIN != "" {
+		if ids.GTIN != "" {
+			gtin, err := domain.NormalizeGTIN(ids.GTIN)
+			if err != nil {
+				return ids, err
+			}
+			ids.GTIN = gtin
+		}
+		if ids.GTIN != "" && ids.GTIN != parent.GTIN {
+			return ids, fmt.Errorf("%w: gtin differs from the parent passport's", domain.ErrInvalidInput)
+		}
+		ids.GTIN = parent.GTIN
+	}
+	if parent.BatchNumber != "" {
+		if ids.BatchNumber != "" && ids.BatchNumber != parent.BatchNumber {
+			return ids, fmt.Errorf("%w: batch number differs from the parent passport's", domain.ErrInvalidInput)
+		}
+		ids.BatchNumber = parent.BatchNumber
+	}
+	return ids, nil
+}
+
+// mergedAttributes returns the passport's own attributes laid over those of its ancestors.
+func (s *passportService) mergedAttributes(ctx context.Context, passport *domain.Passport) (interface{}, error) {
+	// 1. Collect the documents up to the root (own first)
+	own, err := decodeAttributes(passport.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	docs := []interface{}{own}
+
+	for parentID := passport.ParentID; parentID != nil; {
+		if len(docs) > maxHierarchyDepth {
+			return nil, fmt.Errorf("%w: passport hierarchy of %s is too deep", domain.ErrInternal, passport.ID)
+		}
+		parent, err := s.repo.GetByID(ctx, *parentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch parent passport: %w", err)
+		}
+		doc, err := decodeAttributes(parent.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+		parentID = parent.ParentID
+	}
+
+	// 2. Apply each level as a patch, from the root down
+	merged := docs[len(docs)-1]
+	for i := len(docs) - 2; i >= 0; i-- {
+		merged = mergePatch(merged, docs[i])
+	}
+	return merged, nil
+}
+
+// inherit replaces the attributes of a batch or item with the merged view.
+func (s *passportService) inherit(ctx context.Context, passport *domain.Passport) error {
+	if passport.ParentID == nil {
+		return nil
+	}
+	doc, err := s.mergedAttributes(ctx, passport)
+	if err != nil {
+		return err
+	}
+	merged, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal merged attributes: %w", err)
+	}
+	passport.Attributes = json.RawMessage(merged)
+	return nil
+}
+
+// validateAttributes runs the category schema on a (merged) document. Models and batches are
+// templates completed by their children, so missing required fields are tolerated there.
+func validateAttributes(schema *jsonschema.Schema, doc interface{}, level domain.PassportLevel) error {
+	err := schema.Validate(doc)
+	if err == nil || level == domain.LevelItem || level == "" {
+		return err
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) && onlyMissingFields(validationErr) {
+		return nil
+	}
+	return err
+}
+
+func onlyMissingFields(err *jsonschema.ValidationError) bool {
+	if len(err.Causes) == 0 {
+		return strings.HasSuffix(err.KeywordLocation, "/required")
+	}
+	for _, cause := range err.Causes {
+		if !onlyMissingFields(cause) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergePatch applies a JSON Merge Patch (RFC 7396): objects are merged key by key, a null
+// removes the inherited key, and any other value (arrays included) replaces it.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	merged := make(map[string]interface{}, len(targetObj)+len(patchObj))
+	for k, v := range targetObj {
+		merged[k] = v
+	}
+	for k, v := range patchObj {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = mergePatch(merged[k], v)
+	}
+	return merged
+}
+
+// decodeAttributes parses a JSON document, keeping numbers exactly as written.
+func decodeAttributes(raw []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON", domain.ErrInvalidInput)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: invalid JSON", domain.ErrInvalidInput)
+	}
+	return doc, nil
+}
+
+// levelOf treats passports created before the hierarchy as items.
+func levelOf(p *domain.Passport) domain.PassportLevel {
+	if p.Level == "" {
+		return domain.LevelItem
+	}
+	return p.Level
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultJobAttempts is how often a job runs before it is marked FAILED.
+	defaultJobAttempts = 3
+
+	// jobListLimit caps GET /jobs.
+	jobListLimit = 100
+)
+
+type jobService struct {
+	jobs ports.JobRepository
+	log  *slog.Logger
+}
+
+// Ensure interface implementation
+var _ ports.JobService = (*jobService)(nil)
+
+// NewJobService wires the API side of the job queue (cmd/api-worker runs the jobs).
+func NewJobService(jobs ports.JobRepository, log *slog.Logger) ports.JobService {
+	return &jobService{jobs: jobs, log: log}
+}
+
+func (s *jobService) SubmitJob(ctx context.Context, tenantID string, jobType domain.JobType, params interface{}, input []byte) (*domain.Job, error) {
+	// 1. Encode Parameters
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job parameters: %w", err)
+	}
+	if string(rawParams) == "null" {
+		rawParams = []byte(`{}`)
+	}
+
+	// 2. Queue
+	now := time.Now().UTC()
+	job := &domain.Job{
+		ID:          uuid.New(),
+		Type:        jobType,
+		TenantID:    tenantID,
+		Status:      domain.JobQueued,
+		Params:      rawParams,
+		MaxAttempts: defaultJobAttempts,
+		RunAfter:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.jobs.Save(ctx, job, input); err != nil {
+		return nil, fmt.Errorf("failed to save job: %w", err)
+	}
+
+	s.log.Info("job queued", "id", job.ID, "type", jobType, "tenant", tenantID, "inputBytes", len(input))
+	return job, nil
+}
+
+func (s *jobService) GetJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error) {
+	job, err := s.jobs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.TenantID != tenantID {
+		return nil, domain.ErrForbidden
+	}
+	return job, nil
+}
+
+func (s *jobService) ListJobs(ctx context.Context, tenantID string) ([]*domain.Job, error) {
+	return s.jobs.FindByTenant(ctx, tenantID, jobListLimit)
+}
+
+func (s *jobService) GetJobResult(ctx context.Context, id uuid.UUID, tenantID string) ([]byte, error) {
+	job, err := s.GetJob(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Status.IsFinal() {
+		return nil, fmt.Errorf("%w: job is %s", domain.ErrConflict, job.Status)
+	}
+
+	result, err := s.jobs.GetResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("job has no result: %w", domain.ErrNotFound)
+	}
+	return result, nil
+}
+
+func (s *jobService) CancelJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error) {
+	if _, err := s.GetJob(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+	job, err := s.jobs.RequestCancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("job cancellation requested", "id", id, "status", job.Status)
+	return job, nil
+}
+
+func (s *jobService) RetryJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error) {
+	if _, err := s.GetJob(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+	job, err := s.jobs.Requeue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("job queued again", "id", id)
+	return job, nil
+}
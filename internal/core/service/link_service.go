@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// languageTag is the subset of BCP 47 used for hreflang: a primary language and optional subtags.
+var languageTag = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+type linkService struct {
+	links     ports.LinkRepository
+	passports ports.PassportRepository
+	log       *slog.Logger
+}
+
+// Ensure interface implementation
+var _ ports.LinkService = (*linkService)(nil)
+
+// NewLinkService wires the GS1 resolver link records.
+func NewLinkService(links ports.LinkRepository, passports ports.PassportRepository, log *slog.Logger) ports.LinkService {
+	return &linkService{links: links, passports: passports, log: log}
+}
+
+func (s *linkService) CreateLink(ctx context.Context, manufacturerID string, input domain.LinkInput) (*domain.PassportLink, error) {
+	// 1. Validate Link Type
+	linkType, err := domain.ParseLinkType(input.LinkType)
+	if err != nil {
+		return nil, err
+	}
+	if linkType == domain.LinkTypeDefault {
+		return nil, fmt.Errorf("%w: %s is always the passport page", domain.ErrInvalidInput, domain.LinkTypeDefault)
+	}
+
+	// 2. Validate Target & Description
+	href, err := url.Parse(strings.TrimSpace(input.Href))
+	if err != nil || (href.Scheme != "https" && href.Scheme != "http") || href.Host == "" {
+		return nil, fmt.Errorf("%w: href must be an absolute http(s) URL", domain.ErrInvalidInput)
+	}
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", domain.ErrInvalidInput)
+	}
+	language, err := canonicalLanguage(input.Language)
+	if err != nil {
+		return nil, err
+	}
+	mediaType := ""
+	if strings.TrimSpace(input.MediaType) != "" {
+		mediaType, _, err = mime.ParseMediaType(input.MediaType)
+		if err != nil || strings.Count(mediaType, "/") != 1 {
+			return nil, fmt.Errorf("%w: invalid mediaType %q", domain.ErrInvalidInput, input.MediaType)
+		}
+	}
+
+	// 3. Check Ownership of the passport, if any
+	if input.PassportID != nil {
+		passport, err := s.passports.GetByID(ctx, *input.PassportID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch passport: %w", err)
+		}
+		if passport.ManufacturerID != manufacturerID {
+			return nil, domain.ErrForbidden
+		}
+	}
+
+	// 4. Save
+	link := &domain.PassportLink{
+		ID:             uuid.New(),
+		ManufacturerID: manufacturerID,
+		PassportID:     input.PassportID,
+		LinkType:       linkType,
+		Href:           href.String(),
+		Title:          title,
+		Language:       language,
+		MediaType:      mediaType,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.links.Save(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save link: %w", err)
+	}
+
+	s.log.Info("resolver link created", "id", link.ID, "manufacturer", manufacturerID, "type", link.LinkType, "passport", link.PassportID)
+	return link, nil
+}
+
+func (s *linkService) ListLinks(ctx context.Context, manufacturerID string) ([]*domain.PassportLink, error) {
+	return s.links.FindByManufacturer(ctx, manufacturerID)
+}
+
+func (s *linkService) DeleteLink(ctx context.Context, id uuid.UUID, manufacturerID string) error {
+	link, err := s.links.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch link: %w", err)
+	}
+	if link.ManufacturerID != manufacturerID {
+		return domain.ErrForbidden
+	}
+	return s.links.Delete(ctx, id)
+}
+
+func (s *linkService) ResolveLinks(ctx context.Context, passportID uuid.UUID) ([]*domain.PassportLink, error) {
+	// 1. Fetch Owner & Revision Chain (links follow the passport through its revisions)
+	passport, err := s.passports.GetByID(ctx, passportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch passport: %w", err)
+	}
+	versions, err := s.passports.FindVersionHistory(ctx, passportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch revision history: %w", err)
+	}
+	chain := []uuid.UUID{passportID}
+	for _, v := range versions {
+		chain = append(chain, v.PassportID)
+	}
+
+	// 2. Fetch Links
+	links, err := s.links.FindForPassports(ctx, passport.ManufacturerID, chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch links: %w", err)
+	}
+
+	// 3. Passport links override the manufacturer defaults of the same type
+	specific := make(map[domain.LinkType]bool)
+	for _, l := range links {
+		if l.PassportID != nil {
+			specific[l.LinkType] = true
+		}
+	}
+	effective := make([]*domain.PassportLink, 0, len(links))
+	for _, l := range links {
+		if l.PassportID == nil && specific[l.LinkType] {
+			continue
+		}
+		effective = append(effective, l)
+	}
+	return effective, nil
+}
+
+// canonicalLanguage normalizes a BCP 47 tag to its conventional casing (e.g. "en-GB").
+func canonicalLanguage(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", nil
+	}
+	if !languageTag.MatchString(tag) {
+		return "", fmt.Errorf("%w: invalid language tag %q", domain.ErrInvalidInput, tag)
+	}
+
+	parts := strings.Split(tag, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i]) // Region
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:]) // Script
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return strings.Join(parts, "-"), nil
+}
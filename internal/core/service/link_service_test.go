@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockLinkRepository) Save(ctx context.Context, link *domain.PassportLink) error {
+	args := m.Called(ctx, link)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PassportLink, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PassportLink), args.Error(1)
+}
+
+func (m *MockLinkRepository) FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.PassportLink, error) {
+	args := m.Called(ctx, manufacturerID)
+	return args.Get(0).([]*domain.PassportLink), args.Error(1)
+}
+
+func (m *MockLinkRepository) FindForPassports(ctx context.Context, manufacturerID string, passportIDs []uuid.UUID) ([]*domain.PassportLink, error) {
+	args := m.Called(ctx, manufacturerID, passportIDs)
+	return args.Get(0).([]*domain.PassportLink), args.Error(1)
+}
+
+func (m *MockLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateLink_Validation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	foreignID := uuid.New()
+
+	tests := []struct {
+		name    string
+		input   domain.LinkInput
+		wantErr error
+	}{
+		{"unknown vocabulary", domain.LinkInput{LinkType: "schema:manual", Href: "https://example.com", Title: "Manual"}, domain.ErrInvalidInput},
+		{"default link", domain.LinkInput{LinkType: "gs1:defaultLink", Href: "https://example.com", Title: "Home"}, domain.ErrInvalidInput},
+		{"relative href", domain.LinkInput{LinkType: "gs1:pip", Href: "/product", Title: "Product"}, domain.ErrInvalidInput},
+		{"script href", domain.LinkInput{LinkType: "gs1:pip", Href: "javascript:alert(1)", Title: "Product"}, domain.ErrInvalidInput},
+		{"missing title", domain.LinkInput{LinkType: "gs1:pip", Href: "https://example.com"}, domain.ErrInvalidInput},
+		{"invalid language", domain.LinkInput{LinkType: "gs1:pip", Href: "https://example.com", Title: "Product", Language: "english!"}, domain.ErrInvalidInput},
+		{"invalid media type", domain.LinkInput{LinkType: "gs1:pip", Href: "https://example.com", Title: "Product", MediaType: "pdf"}, domain.ErrInvalidInput},
+		{"foreign passport", domain.LinkInput{PassportID: &foreignID, LinkType: "gs1:pip", Href: "https://example.com", Title: "Product"}, domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := new(MockLinkRepository)
+			passports := new(MockPassportRepository)
+			passports.On("GetByID", mock.Anything, foreignID).Return(&domain.Passport{ID: foreignID, ManufacturerID: "mfg-2"}, nil)
+			svc := service.NewLinkService(links, passports, logger)
+
+			_, err := svc.CreateLink(ctx, "mfg-1", tt.input)
+
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+			links.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateLink_Normalizes(t *testing.T) {
+	links := new(MockLinkRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewLinkService(links, new(MockPassportRepository), logger)
+	ctx := context.Background()
+
+	links.On("Save", ctx, mock.Anything).Return(nil)
+
+	link, err := svc.CreateLink(ctx, "mfg-1", domain.LinkInput{
+		LinkType:  "https://gs1.org/voc/instructions",
+		Href:      "https://example.com/manual.pdf",
+		Title:     " Manual ",
+		Language:  "EN-gb",
+		MediaType: "Application/PDF; charset=binary",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LinkTypeInstructions, link.LinkType)
+	assert.Equal(t, "Manual", link.Title)
+	assert.Equal(t, "en-GB", link.Language)
+	assert.Equal(t, "application/pdf", link.MediaType)
+	assert.Nil(t, link.PassportID, "no passport means every passport of the manufacturer")
+}
+
+func TestResolveLinks_PassportOverridesManufacturer(t *testing.T) {
+	links := new(MockLinkRepository)
+	passports := new(MockPassportRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewLinkService(links, passports, logger)
+	ctx := context.Background()
+
+	// The link was attached to v1; the resolver displays v2
+	v1, v2 := uuid.New(), uuid.New()
+	passports.On("GetByID", ctx, v2).Return(&domain.Passport{ID: v2, ManufacturerID: "mfg-1"}, nil)
+	passports.On("FindVersionHistory", ctx, v2).Return([]domain.PassportVersion{{PassportID: v1}, {PassportID: v2}}, nil)
+
+	wideManual := &domain.PassportLink{LinkType: domain.LinkTypeInstructions, Href: "https://example.com/manuals"}
+	wideRecycling := &domain.PassportLink{LinkType: domain.LinkTypeSustainabilityInfo, Href: "https://example.com/recycling"}
+	ownManual := &domain.PassportLink{PassportID: &v1, LinkType: domain.LinkTypeInstructions, Href: "https://example.com/manuals/x1.pdf"}
+	links.On("FindForPassports", ctx, "mfg-1", mock.MatchedBy(func(ids []uuid.UUID) bool {
+		return assert.ObjectsAreEqual([]uuid.UUID{v2, v1, v2}, ids)
+	})).Return([]*domain.PassportLink{wideManual, wideRecycling, ownManual}, nil)
+
+	got, err := svc.ResolveLinks(ctx, v2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.PassportLink{wideRecycling, ownManual}, got)
+}
+
+func TestDeleteLink_OnlyOwner(t *testing.T) {
+	links := new(MockLinkRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewLinkService(links, new(MockPassportRepository), logger)
+	ctx := context.Background()
+
+	id := uuid.New()
+	links.On("GetByID", ctx, id).Return(&domain.PassportLink{ID: id, ManufacturerID: "mfg-1"}, nil)
+
+	err := svc.DeleteLink(ctx, id, "mfg-2")
+
+	assert.True(t, errors.Is(err, domain.ErrForbidden))
+	links.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
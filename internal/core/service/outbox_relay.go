@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// maxRelayBackoff caps the wait before an event that failed to publish is retried.
+const maxRelayBackoff = 5 * time.Minute
+
+// RelayConfig tunes an OutboxRelay. Zero values take the defaults.
+type RelayConfig struct {
+	BatchSize    int           // Events claimed per round (default 100)
+	PollInterval time.Duration // Wait when the outbox is empty (default 1s)
+	Lease        time.Duration // Claimed events stay hidden from other relays (default 30s)
+}
+
+// OutboxRelay publishes the events of the transactional outbox to the event bus. An event is
+// removed once published and retried with backoff otherwise; it may reach the bus twice if the
+// relay stops in between, never zero times.
+type OutboxRelay struct {
+	outbox ports.OutboxRepository
+	bus    ports.EventBus
+	cfg    RelayConfig
+	log    *slog.Logger
+}
+
+func NewOutboxRelay(outbox ports.OutboxRepository, bus ports.EventBus, cfg RelayConfig, log *slog.Logger) *OutboxRelay {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 30 * time.Second
+	}
+	return &OutboxRelay{outbox: outbox, bus: bus, cfg: cfg, log: log}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		relayed, err := r.Relay(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.log.Error("failed to relay outbox events", "error", err)
+		}
+		if relayed > 0 {
+			continue // A passport's next event becomes claimable once the previous one is gone
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+// Relay makes one round: it claims due events (at most one per passport) and publishes them.
+// It returns how many were published.
+func (r *OutboxRelay) Relay(ctx context.Context) (int, error) {
+	events, err := r.outbox.ClaimEvents(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	published := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		if err := r.bus.Publish(ctx, event.Channel, event.Payload); err != nil {
+			r.recordFailure(ctx, event, err)
+			continue
+		}
+		published = append(published, event.ID)
+	}
+
+	// Not removed, the events are published again once the lease expires
+	if err := r.outbox.MarkRelayed(context.WithoutCancel(ctx), published); err != nil {
+		return 0, err
+	}
+	return len(published), nil
+}
+
+func (r *OutboxRelay) recordFailure(ctx context.Context, event *domain.OutboxEvent, err error) {
+	event.Attempts++
+	event.LastError = err.Error()
+	event.NextAttemptAt = time.Now().UTC().Add(relayBackoff(event.Attempts))
+
+	r.log.Warn("failed to publish outbox event", "id", event.ID, "channel", event.Channel, "attempt", event.Attempts, "error", err)
+	if err := r.outbox.RecordFailure(context.WithoutCancel(ctx), event); err != nil {
+		r.log.Error("failed to record outbox failure", "id", event.ID, "error", err)
+	}
+}
+
+// relayBackoff doubles with every failed attempt: 1s, 2s, 4s... up to maxRelayBackoff.
+func relayBackoff(attempts int) time.Duration {
+	backoff := time.Second
+	for i := 1; i < attempts && backoff < maxRelayBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRelayBackoff)
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkRelayed(ctx context.Context, ids []uuid.UUID) error {
+	return m.Called(ctx, ids).Error(0)
+}
+
+func (m *MockOutboxRepository) RecordFailure(ctx context.Context, event *domain.OutboxEvent) error {
+	return m.Called(ctx, event).Error(0)
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	outbox := new(MockOutboxRepository)
+	bus := new(MockEventBus)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	relay := service.NewOutboxRelay(outbox, bus, service.RelayConfig{BatchSize: 10}, logger)
+	ctx := context.Background()
+
+	created := &domain.OutboxEvent{ID: uuid.New(), PassportID: uuid.New(), Channel: "events:passport_created", Payload: json.RawMessage(`{"tenant_id":"mfg-1"}`)}
+	revoked := &domain.OutboxEvent{ID: uuid.New(), PassportID: uuid.New(), Channel: "events:passport_revoked", Payload: json.RawMessage(`{"tenant_id":"mfg-1"}`), Attempts: 2}
+
+	// Expectations: the first event goes out, the second is retried later
+	outbox.On("ClaimEvents", ctx, 10, 30*time.Second).Return([]*domain.OutboxEvent{created, revoked}, nil).Once()
+	bus.On("Publish", ctx, "events:passport_created", created.Payload).Return(nil).Once()
+	bus.On("Publish", ctx, "events:passport_revoked", revoked.Payload).Return(errors.New("connection refused")).Once()
+	outbox.On("RecordFailure", mock.Anything, revoked).Return(nil).Once()
+	outbox.On("MarkRelayed", mock.Anything, []uuid.UUID{created.ID}).Return(nil).Once()
+
+	// Execute
+	before := time.Now()
+	relayed, err := relay.Relay(ctx)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, 1, relayed)
+	assert.Equal(t, 3, revoked.Attempts)
+	assert.Equal(t, "connection refused", revoked.LastError)
+	assert.WithinDuration(t, before.Add(4*time.Second), revoked.NextAttemptAt, time.Second) // 1s, 2s, 4s
+
+	outbox.AssertExpectations(t)
+	bus.AssertExpectations(t)
+}
@@ -20,6 +20,16 @@ func (m *MockRepo) Save(ctx context.Context, p *domain.Passport) error {
 	args := m.Called(ctx, p)
 	return args.Error(0)
 }
+func (m *MockRepo) SaveBatch(ctx context.Context, passports []*domain.Passport) error {
+	args := m.Called(ctx, passports)
+	return args.Error(0)
+}
+func (m *MockRepo) FindDrafts(ctx context.Context, manufacturerID string, category domain.ProductCategory, createdBefore *time.Time) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (m *MockRepo) PublishBatch(ctx context.Context, passports []*domain.Passport) error {
+	return nil
+}
 func (m *MockRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Passport, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -27,8 +37,8 @@ func (m *MockRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Passport,
 	}
 	return args.Get(0).(*domain.Passport), args.Error(1)
 }
-func (m *MockRepo) Update(ctx context.Context, p *domain.Passport) error {
-	args := m.Called(ctx, p)
+func (m *MockRepo) Update(ctx context.Context, p *domain.Passport, expected domain.PassportStatus) error {
+	args := m.Called(ctx, p, expected)
 	return args.Error(0)
 }
 
@@ -37,11 +47,37 @@ func (m *MockRepo) FindByCategory(ctx context.Context, category domain.ProductCa
 	return args.Get(0).([]*domain.Passport), args.Error(1)
 }
 
-func (m *MockRepo) FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.Passport, error) {
-	args := m.Called(ctx, manufacturerID)
+func (m *MockRepo) FindByManufacturer(ctx context.Context, manufacturerID string, query domain.PassportQuery) ([]*domain.Passport, error) {
+	args := m.Called(ctx, manufacturerID, query)
 	return args.Get(0).([]*domain.Passport), args.Error(1)
 }
 
+func (m *MockRepo) FindVersionHistory(ctx context.Context, id uuid.UUID) ([]domain.PassportVersion, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.PassportVersion), args.Error(1)
+}
+
+func (m *MockRepo) FindByIdentifiers(ctx context.Context, ids domain.ProductIdentifiers) ([]uuid.UUID, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepo) CountPublishedChildren(ctx context.Context, parentIDs []uuid.UUID) (int, error) {
+	args := m.Called(ctx, parentIDs)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepo) SaveSearchDocuments(ctx context.Context, passports []*domain.Passport) error {
+	args := m.Called(ctx, passports)
+	return args.Error(0)
+}
+
+func (m *MockRepo) SearchText(ctx context.Context, query domain.TextSearchQuery) ([]domain.TextSearchHit, error) {
+	args := m.Called(ctx, query)
+	hits, _ := args.Get(0).([]domain.TextSearchHit)
+	return hits, args.Error(1)
+}
+
 type MockCache struct{ mock.Mock }
 
 func (m *MockCache) Get(ctx context.Context, key string) (string, error) {
@@ -74,7 +110,7 @@ func TestGetPassport_Filtering(t *testing.T) {
 	repo := new(MockRepo)
 	cache := new(MockCache)
 	// We don't need real BlobStore or EventBus for this test
-	svc, err := NewPassportService(repo, cache, nil, nil, nil)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", nil)
 	assert.NoError(t, err)
 
 	// Create a passport with restricted data
@@ -131,7 +167,7 @@ func TestGetPassport_Filtering_Textile(t *testing.T) {
 	cache := new(MockCache)
 	// We don't need real BlobStore or EventBus for this test
 	// NewPassportService will load the embedded textile.json which SHOULD have supplyChainDetails restricted
-	svc, err := NewPassportService(repo, cache, nil, nil, nil)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", nil)
 	assert.NoError(t, err)
 
 	// Create a passport with restricted data
@@ -160,3 +196,37 @@ func TestGetPassport_Filtering_Textile(t *testing.T) {
 	assert.Equal(t, "T-Shirt", attrsPublic["garmentType"])
 	assert.Nil(t, attrsPublic["supplyChainDetails"], "Restricted field should be removed")
 }
+
+func TestGetPassport_Filtering_Electronics(t *testing.T) {
+	// Setup Service
+	repo := new(MockRepo)
+	cache := new(MockCache)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", nil)
+	assert.NoError(t, err)
+
+	// Create a passport with restricted repair data
+	fullAttributes := `{"productModel": "Phone 5", "energyClass": "B", "repairInformation": {"repairManualUrl": "https://example.com/manual.pdf"}}`
+	id := uuid.New()
+	passport := &domain.Passport{
+		ID:              id,
+		ProductCategory: domain.CategoryElectronic,
+		Attributes:      json.RawMessage(fullAttributes),
+	}
+
+	repo.On("GetByID", mock.Anything, id).Return(passport, nil).Once()
+	cache.On("Get", mock.Anything, mock.Anything).Return("", assert.AnError)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Public Context (Should Filter)
+	pPublic, err := svc.GetPassport(context.Background(), id)
+	assert.NoError(t, err)
+
+	attrs, err := pPublic.GetElectronicAttributes()
+	assert.NoError(t, err)
+	assert.Equal(t, "Phone 5", attrs.ProductModel)
+	assert.Equal(t, "B", attrs.EnergyClass)
+
+	var raw map[string]interface{}
+	json.Unmarshal(pPublic.Attributes, &raw)
+	assert.Nil(t, raw["repairInformation"], "Restricted field should be removed")
+}
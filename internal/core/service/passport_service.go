@@ -10,123 +10,102 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
-	_ "embed"
-
 	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/core/ports"
 	"github.com/google/uuid"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
-// Embed the schemas directly into the Go binary
-//
-//go:embed schemas/payloads/battery.json
-var batterySchemaRaw string
-
-//go:embed schemas/payloads/textile.json
-var textileSchemaRaw string
-
 type passportService struct {
-	repo             ports.PassportRepository
-	cache            ports.CacheRepository
-	blobStore        ports.BlobStorage
-	eventBus         ports.EventBus
-	compiler         *jsonschema.Compiler
-	schemas          map[domain.ProductCategory]*jsonschema.Schema
-	restrictedFields map[domain.ProductCategory][]string
-	log              *slog.Logger
+	repo      ports.PassportRepository
+	cache     ports.CacheRepository
+	blobStore ports.BlobStorage
+	schemas   *schemaCatalog
+	envelope  *jsonschema.Schema
+	grants    ports.GrantRepository
+	tenants   ports.TenantRepository
+	levels    *domain.AccessLevels
+	log       *slog.Logger
+
+	// publicBaseURL prefixes the resolver link recorded in published envelopes
+	publicBaseURL string
 }
 
 // Ensure interface implementation
 var _ ports.PassportService = (*passportService)(nil)
 
-func NewPassportService(repo ports.PassportRepository, cache ports.CacheRepository, blobStore ports.BlobStorage, eventBus ports.EventBus, log *slog.Logger) (ports.PassportService, error) {
-	compiler := jsonschema.NewCompiler()
-	compiler.Draft = jsonschema.Draft2020
-
-	// Register and Compile Schemas
-	if err := compiler.AddResource("battery.json", strings.NewReader(batterySchemaRaw)); err != nil {
-		return nil, fmt.Errorf("failed to add battery schema: %w", err)
-	}
-	if err := compiler.AddResource("textile.json", strings.NewReader(textileSchemaRaw)); err != nil {
-		return nil, fmt.Errorf("failed to add textile schema: %w", err)
+// NewPassportService wires the passport use-cases. The schema registry is optional:
+// without it, only the schemas embedded in the binary are used. Without a grant
+// repository, foreign viewers only get the level of their credential. Without a tenant
+// repository, publishing uses the manufacturer identifiers already on the passport.
+// A nil access vocabulary means domain.DefaultAccessLevels. Lifecycle events go through
+// the repository's outbox (see OutboxRelay).
+func NewPassportService(repo ports.PassportRepository, cache ports.CacheRepository, blobStore ports.BlobStorage, schemaRegistry ports.SchemaRegistry, grants ports.GrantRepository, tenants ports.TenantRepository, accessLevels *domain.AccessLevels, publicBaseURL string, log *slog.Logger) (ports.PassportService, error) {
+	if accessLevels == nil {
+		accessLevels = domain.MustDefaultAccessLevels()
 	}
 
-	batterySchema, err := compiler.Compile("battery.json")
+	schemas, err := newSchemaCatalog(schemaRegistry, accessLevels)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compile battery schema: %w", err)
-	}
-
-	textileSchema, err := compiler.Compile("textile.json")
-	if err != nil {
-		return nil, fmt.Errorf("failed to compile textile schema: %w", err)
-	}
-
-	// Parse Restricted Fields
-	restrictedFields := make(map[domain.ProductCategory][]string)
-
-	batRestricted, err := parseRestrictedFields(batterySchemaRaw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse restricted fields for battery: %w", err)
+		return nil, err
 	}
-	restrictedFields[domain.CategoryBattery] = batRestricted
 
-	texRestricted, err := parseRestrictedFields(textileSchemaRaw)
+	envelope, err := compileEnvelopeSchema()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse restricted fields for textile: %w", err)
+		return nil, fmt.Errorf("failed to load master envelope schema: %w", err)
 	}
-	restrictedFields[domain.CategoryTextile] = texRestricted
 
 	return &passportService{
 		repo:      repo,
 		cache:     cache,
 		blobStore: blobStore,
-		eventBus:  eventBus,
-		compiler:  compiler,
-		schemas: map[domain.ProductCategory]*jsonschema.Schema{
-			domain.CategoryBattery: batterySchema,
-			domain.CategoryTextile: textileSchema,
-		},
-		restrictedFields: restrictedFields,
-		log:              log,
+		schemas:   schemas,
+		envelope:  envelope,
+		grants:    grants,
+		tenants:   tenants,
+		levels:    accessLevels,
+		log:       log,
+
+		publicBaseURL: strings.TrimRight(publicBaseURL, "/"),
 	}, nil
 }
 
-func parseRestrictedFields(rawSchema string) ([]string, error) {
-	var schema struct {
-		Properties map[string]struct {
-			Access string `json:"access"`
-		} `json:"properties"`
-	}
-	if err := json.Unmarshal([]byte(rawSchema), &schema); err != nil {
+func (s *passportService) CreatePassport(ctx context.Context, manufacturerID string, manufacturerName string, category domain.ProductCategory, ids domain.ProductIdentifiers, hierarchy domain.PassportHierarchy, payload []byte) (*domain.Passport, error) {
+	// 0. Validate Hierarchy Level & GS1 Identifiers (GTIN check digit, character set).
+	// Batches and items take the GS1 keys they leave out from their (published) parent.
+	hierarchy, err := hierarchy.Normalize(ids)
+	if err != nil {
 		return nil, err
 	}
-	var restricted []string
-	for key, prop := range schema.Properties {
-		if prop.Access == "restricted" {
-			restricted = append(restricted, key)
+	if hierarchy.ParentID != nil {
+		parent, err := s.resolveParent(ctx, manufacturerID, category, hierarchy)
+		if err != nil {
+			return nil, err
+		}
+		if ids, err = inheritIdentifiers(parent, ids); err != nil {
+			return nil, err
 		}
 	}
-	return restricted, nil
-}
+	if ids, err = ids.Normalize(); err != nil {
+		return nil, err
+	}
+	if err := s.checkCompanyPrefix(ctx, manufacturerID, ids.GTIN); err != nil {
+		return nil, err
+	}
 
-func (s *passportService) CreatePassport(ctx context.Context, manufacturerID string, manufacturerName string, category domain.ProductCategory, payload []byte) (*domain.Passport, error) {
 	// 1. Idempotency Check
-	// Generate a hash of the raw payload + category + manufacturer
-	hasher := sha256.New()
-	hasher.Write([]byte(manufacturerID))
-	hasher.Write([]byte(category))
-	hasher.Write(payload)
-	payloadHash := hex.EncodeToString(hasher.Sum(nil))
+	payloadHash := idempotencyHash(manufacturerID, category, ids, hierarchy, payload)
 
 	// Check Redis for existing hash
 	if existingIDStr, err := s.cache.GetIdempotency(ctx, payloadHash); err == nil {
@@ -143,22 +122,15 @@ func (s *passportService) CreatePassport(ctx context.Context, manufacturerID str
 		// If parsing failed or DB lookup failed, we fall through and recreate (safe fallback)
 	}
 
-	// 2. Schema Validation
-	schema, exists := s.schemas[category]
-	if !exists {
-		s.log.Warn("unsupported product category", "category", category)
-		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidInput, category)
-	}
-
-	var jsonInterface interface{}
-	if err := json.Unmarshal(payload, &jsonInterface); err != nil {
-		s.log.Warn("invalid json format", "error", err)
-		return nil, fmt.Errorf("%w: invalid JSON", domain.ErrInvalidInput)
-	}
-
-	if err := schema.Validate(jsonInterface); err != nil {
-		s.log.Warn("schema validation failed", "error", err)
-		return nil, fmt.Errorf("%w: schema validation failed", domain.ErrInvalidInput)
+	// 2. Schema Validation (merged document, against the category's active schema version)
+	compiled, err := s.schemas.active(ctx, category)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			s.log.Warn("unsupported product category", "category", category)
+			return nil, err
+		}
+		s.log.Error("failed to resolve schema", "category", category, "error", err)
+		return nil, fmt.Errorf("%w: schema unavailable", domain.ErrInternal)
 	}
 
 	// 3. Construct Domain Entity
@@ -169,13 +141,38 @@ func (s *passportService) CreatePassport(ctx context.Context, manufacturerID str
 		Status:           domain.StatusDraft,
 		ManufacturerID:   manufacturerID,
 		ManufacturerName: manufacturerName,
+		GTIN:             ids.GTIN,
+		BatchNumber:      ids.BatchNumber,
+		SerialNumber:     ids.SerialNumber,
+		Level:            hierarchy.Level,
+		ParentID:         hierarchy.ParentID,
 		Attributes:       json.RawMessage(payload),
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		Version:          1,
+		SchemaVersion:    compiled.version,
+	}
+
+	doc, err := s.mergedAttributes(ctx, passport)
+	if err != nil {
+		s.log.Warn("failed to merge attributes", "error", err)
+		return nil, err
 	}
 
-	// 4. Save to Repository
+	if err := validateAttributes(compiled.schema, doc, hierarchy.Level); err != nil {
+		s.log.Warn("schema validation failed", "error", err)
+		return nil, fmt.Errorf("%w: schema validation failed", domain.ErrInvalidInput)
+	}
+	s.indexText(passport, compiled, doc)
+
+	// 4. Save to Repository with its Event (unique indexes reject GS1 keys already registered)
+	if err := passport.RecordEvent(domain.EventPassportCreated); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInternal, err)
+	}
 	if err := s.repo.Save(ctx, passport); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, fmt.Errorf("%w: GS1 identifiers already registered", domain.ErrConflict)
+		}
 		s.log.Error("failed to persist passport", "error", err)
 		return nil, fmt.Errorf("%w: failed to save", domain.ErrInternal)
 	}
@@ -186,22 +183,26 @@ func (s *passportService) CreatePassport(ctx context.Context, manufacturerID str
 		s.log.Warn("failed to set idempotency key", "error", err)
 	}
 
-	// 6. Publish Event
-	event := struct {
-		TenantID   string    `json:"tenant_id"`
-		PassportID string    `json:"passport_id"`
-		Timestamp  time.Time `json:"timestamp"`
-	}{
-		TenantID:   manufacturerID,
-		PassportID: passport.ID.String(),
-		Timestamp:  time.Now().UTC(),
-	}
+	return passport, nil
+}
 
-	if err := s.eventBus.Publish(ctx, "events:passport_created", event); err != nil {
-		s.log.Error("failed to publish passport_created event", "error", err)
+// idempotencyHash identifies a creation request: a hash of the raw payload + category +
+// manufacturer (+ GS1 keys, hierarchy). Single and bulk creation share it.
+func idempotencyHash(manufacturerID string, category domain.ProductCategory, ids domain.ProductIdentifiers, hierarchy domain.PassportHierarchy, payload []byte) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(manufacturerID))
+	hasher.Write([]byte(category))
+	if !ids.IsZero() {
+		hasher.Write([]byte(ids.GTIN + "/" + ids.BatchNumber + "/" + ids.SerialNumber))
 	}
-
-	return passport, nil
+	if hierarchy.Level != domain.LevelItem || hierarchy.ParentID != nil {
+		hasher.Write([]byte(hierarchy.Level))
+		if hierarchy.ParentID != nil {
+			hasher.Write(hierarchy.ParentID[:])
+		}
+	}
+	hasher.Write(payload)
+	return hex.EncodeToString(hasher.Sum(nil))
 }
 
 func (s *passportService) GetPassport(ctx context.Context, id uuid.UUID) (*domain.Passport, error) {
@@ -226,6 +227,12 @@ func (s *passportService) GetPassport(ctx context.Context, id uuid.UUID) (*domai
 		}
 		passport = p
 
+		// Batches and items are served with the attributes they inherit (parents are immutable)
+		if err := s.inherit(ctx, passport); err != nil {
+			s.log.Error("failed to merge inherited attributes", "id", id, "error", err)
+			return nil, fmt.Errorf("%w: failed to merge inherited attributes", domain.ErrInternal)
+		}
+
 		// 3. FILL CACHE: Save for next time (Full Data)
 		// We cache for 1 hour (or longer, since passports are immutable-ish)
 		if jsonBytes, jsonErr := json.Marshal(passport); jsonErr == nil {
@@ -237,96 +244,335 @@ func (s *passportService) GetPassport(ctx context.Context, id uuid.UUID) (*domai
 		}
 	}
 
-	// 4. FILTERING (Public vs Restricted)
+	// 4. FILTERING (by Access Level)
 	// This MUST run after retrieval (Cache OR DB) to ensure we don't leak secrets
 	viewContext, _ := ctx.Value(domain.ViewContextKey).(string)
 	viewerTenantID, _ := ctx.Value(domain.ViewerTenantIDKey).(string)
 
 	// Strict Ownership Check:
-	// Even if authenticated (Restricted Context), you can only see restricted data
-	// if you are the Manufacturer of this passport.
+	// The Manufacturer of this passport always sees everything.
 	isOwner := (viewerTenantID == passport.ManufacturerID)
+	if viewContext == domain.ViewContextRestricted && isOwner {
+		return passport, nil
+	}
 
-	if viewContext != domain.ViewContextRestricted || !isOwner {
-		s.filterAttributes(passport)
+	// Everyone else sees the tiers up to the level carried by their credential,
+	// or granted to them by the manufacturer, whichever is higher.
+	viewerRank := 0
+	if viewContext == domain.ViewContextRestricted {
+		level, _ := ctx.Value(domain.ViewerAccessLevelKey).(string)
+		viewerRank = max(s.levels.ViewerRank(domain.AccessLevel(level)), s.grantedRank(ctx, viewerTenantID, passport))
 	}
+	s.filterAttributes(ctx, passport, viewerRank)
 
 	return passport, nil
 }
 
-func (s *passportService) filterAttributes(passport *domain.Passport) {
-	restricted, ok := s.restrictedFields[passport.ProductCategory]
-	if !ok || len(restricted) == 0 {
-		return
+// grantedRank returns the highest access rank the manufacturer granted the viewer on this passport.
+// Lookup failures fail closed (no grant).
+func (s *passportService) grantedRank(ctx context.Context, viewerTenantID string, passport *domain.Passport) int {
+	if s.grants == nil || viewerTenantID == "" {
+		return 0
 	}
 
-	var attrs map[string]interface{}
-	if err := json.Unmarshal(passport.Attributes, &attrs); err != nil {
-		s.log.Warn("failed to unmarshal attributes for filtering", "error", err)
+	// A grant on any revision of the product covers this one
+	revisions := []uuid.UUID{passport.ID}
+	if passport.PreviousVersionID != nil || passport.SupersededByID != nil {
+		versions, err := s.repo.FindVersionHistory(ctx, passport.ID)
+		if err != nil {
+			s.log.Error("failed to look up revision history", "id", passport.ID, "error", err)
+			return 0
+		}
+		for _, v := range versions {
+			if v.PassportID != passport.ID {
+				revisions = append(revisions, v.PassportID)
+			}
+		}
+	}
+
+	now := time.Now()
+	grants, err := s.grants.FindActiveForPassport(ctx, viewerTenantID, passport, revisions, now)
+	if err != nil {
+		s.log.Error("failed to look up access grants", "id", passport.ID, "viewer", viewerTenantID, "error", err)
+		return 0
+	}
+
+	rank := 0
+	for _, g := range grants {
+		if g.IsActive(now) && g.Covers(passport, revisions) {
+			rank = max(rank, s.levels.ViewerRank(g.AccessLevel))
+		}
+	}
+	return rank
+}
+
+// filterAttributes removes every field above the viewer's access rank.
+func (s *passportService) filterAttributes(ctx context.Context, passport *domain.Passport, viewerRank int) {
+	// Filter with the schema version the passport was validated against
+	compiled, err := s.schemas.version(ctx, passport.ProductCategory, passport.SchemaVersion)
+	if err != nil {
+		// Fail closed: without its access rules we cannot tell what is safe to show
+		s.log.Error("failed to load schema for filtering", "id", passport.ID, "schemaVersion", passport.SchemaVersion, "error", err)
+		passport.Attributes = json.RawMessage(`{}`)
+		return
+	}
+	if len(compiled.redactions) == 0 {
 		return
 	}
 
-	for _, field := range restricted {
-		delete(attrs, field)
+	decoder := json.NewDecoder(bytes.NewReader(passport.Attributes))
+	decoder.UseNumber() // keep numbers exactly as stored
+	var attrs interface{}
+	if err := decoder.Decode(&attrs); err != nil {
+		s.log.Warn("failed to unmarshal attributes for filtering", "id", passport.ID, "error", err)
+		passport.Attributes = json.RawMessage(`{}`)
+		return
 	}
 
-	if filtered, err := json.Marshal(attrs); err == nil {
-		passport.Attributes = json.RawMessage(filtered)
+	filtered, err := json.Marshal(redact(attrs, compiled.redactions, viewerRank))
+	if err != nil {
+		s.log.Error("failed to marshal filtered attributes", "id", passport.ID, "error", err)
+		passport.Attributes = json.RawMessage(`{}`)
+		return
 	}
+	passport.Attributes = json.RawMessage(filtered)
 }
 
-func (s *passportService) PublishPassport(ctx context.Context, id uuid.UUID) (*domain.Passport, error) {
-	// 1. Fetch Passport
+func (s *passportService) PublishPassport(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.Passport, error) {
+	// 1. Fetch Passport & Check Ownership
 	passport, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch passport: %w", err)
 	}
+	if passport.ManufacturerID != manufacturerID {
+		return nil, domain.ErrForbidden
+	}
 
-	// 2. Check if already published
-	if passport.Status == domain.StatusPublished {
+	// 2. Check if already published (or since replaced, or withdrawn)
+	if passport.Status == domain.StatusPublished || passport.Status == domain.StatusSuperseded {
 		return nil, domain.ErrPassportAlreadyPublished
 	}
+	if passport.Status == domain.StatusRevoked {
+		return nil, domain.ErrPassportRevoked
+	}
+
+	// 3. A revision can only replace a live predecessor, and a batch or item needs a live parent
+	previous, err := s.previousRevision(ctx, passport)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkParentLive(ctx, passport); err != nil {
+		return nil, err
+	}
+
+	// 4. Build, Hash & Upload the Master Envelope
+	if err := s.uploadEnvelope(ctx, passport, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	// 5. Update Passport Struct (and its predecessor's)
+	passport.Status = domain.StatusPublished
+	if err := passport.RecordEvent(domain.EventPassportPublished); err != nil {
+		return nil, err
+	}
+	if err := recordSupersession(passport, previous); err != nil {
+		return nil, err
+	}
+
+	// 6. Save in one Transaction (with the events); the draft and predecessor statuses are guarded
+	if err := s.repo.PublishBatch(ctx, []*domain.Passport{passport}); err != nil {
+		return nil, fmt.Errorf("failed to save published passport: %w", err)
+	}
+
+	// 7. Invalidate Cache (Force next read to hit DB)
+	if previous != nil {
+		s.invalidate(previous.ID)
+	}
+	s.invalidate(id)
+
+	return passport, nil
+}
+
+// previousRevision returns the published revision a draft replaces (nil for a first revision).
+// A predecessor revoked or replaced since the draft was created cannot be superseded: a recalled
+// product must never resolve to a valid revision.
+func (s *passportService) previousRevision(ctx context.Context, passport *domain.Passport) (*domain.Passport, error) {
+	if passport.PreviousVersionID == nil {
+		return nil, nil
+	}
+
+	previous, err := s.repo.GetByID(ctx, *passport.PreviousVersionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch previous revision: %w", err)
+	}
+	switch previous.Status {
+	case domain.StatusPublished:
+		return previous, nil
+	case domain.StatusRevoked:
+		return nil, fmt.Errorf("%w: the previous revision was revoked", domain.ErrPassportRevoked)
+	default:
+		return nil, fmt.Errorf("%w: the previous revision is %s", domain.ErrConflict, previous.Status)
+	}
+}
+
+// checkParentLive rejects publishing a batch or item whose parent product was revoked since the
+// draft was created: it would inherit recalled data while looking valid.
+func (s *passportService) checkParentLive(ctx context.Context, passport *domain.Passport) error {
+	if passport.ParentID == nil {
+		return nil
+	}
 
-	// 3. Marshal Attributes
-	payloadBytes, err := json.Marshal(passport.Attributes)
+	parent, err := s.repo.GetByID(ctx, *passport.ParentID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal attributes: %w", err)
+		return fmt.Errorf("failed to fetch parent passport: %w", err)
+	}
+	// The parent may have been revised: its live revision tells whether the product was recalled
+	for parent.Status == domain.StatusSuperseded && parent.SupersededByID != nil {
+		if parent, err = s.repo.GetByID(ctx, *parent.SupersededByID); err != nil {
+			return fmt.Errorf("failed to fetch parent passport revision: %w", err)
+		}
+	}
+	if parent.Status == domain.StatusRevoked {
+		return fmt.Errorf("%w: the parent passport was revoked", domain.ErrPassportRevoked)
+	}
+	return nil
+}
+
+// checkNoPublishedChildren rejects revoking a model or batch while published passports still
+// inherit from one of its revisions: those must be revoked first, so that no item keeps
+// looking valid under a recalled parent.
+func (s *passportService) checkNoPublishedChildren(ctx context.Context, passport *domain.Passport) error {
+	if levelOf(passport) == domain.LevelItem {
+		return nil
 	}
 
-	// 4. Calculate SHA-256 Hash
-	hash := sha256.Sum256(payloadBytes)
+	revisions := []uuid.UUID{passport.ID}
+	if passport.PreviousVersionID != nil {
+		versions, err := s.repo.FindVersionHistory(ctx, passport.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch revision history: %w", err)
+		}
+		for _, v := range versions {
+			if v.PassportID != passport.ID {
+				revisions = append(revisions, v.PassportID)
+			}
+		}
+	}
+
+	children, err := s.repo.CountPublishedChildren(ctx, revisions)
+	if err != nil {
+		return fmt.Errorf("failed to count child passports: %w", err)
+	}
+	if children > 0 {
+		return fmt.Errorf("%w: %d published passports inherit from this %s; revoke them first", domain.ErrConflict, children, levelOf(passport))
+	}
+	return nil
+}
+
+// recordSupersession marks the predecessor as replaced by the passport. Its event is recorded
+// with the passport's, so that both are written by the publishing transaction.
+func recordSupersession(passport, previous *domain.Passport) error {
+	if previous == nil {
+		return nil
+	}
+
+	previous.Status = domain.StatusSuperseded
+	previous.SupersededByID = &passport.ID
+	if err := previous.RecordEvent(domain.EventPassportSuperseded); err != nil {
+		return err
+	}
+	passport.Events = append(passport.Events, previous.Events...)
+	previous.Events = nil
+	return nil
+}
+
+// uploadEnvelope stores the passport's master envelope in blob storage and sets its manufacturer
+// details, hash, storage location and publication time. The status is left to the caller.
+func (s *passportService) uploadEnvelope(ctx context.Context, passport *domain.Passport, now time.Time) error {
+	// 1. Record the Economic Operator (as registered at issuance)
+	if err := s.recordManufacturer(ctx, passport); err != nil {
+		return err
+	}
+
+	// 2. Build & Validate the Master Envelope (self-contained: items carry what they inherit)
+	view := *passport
+	if err := s.inherit(ctx, &view); err != nil {
+		return err
+	}
+	envelopeBytes, err := s.buildEnvelope(&view, now)
+	if err != nil {
+		return err
+	}
+
+	// 3. Calculate SHA-256 Hash
+	hash := sha256.Sum256(envelopeBytes)
 	hashString := hex.EncodeToString(hash[:])
 
-	// 5. Upload to BlobStorage
+	// 4. Upload to BlobStorage
 	key := fmt.Sprintf("passports/%s.json", passport.ID.String())
-	s3URL, err := s.blobStore.UploadJSON(ctx, "passports", key, payloadBytes)
+	s3URL, err := s.blobStore.UploadJSON(ctx, "passports", key, envelopeBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload to blob storage: %w", err)
+		return fmt.Errorf("failed to upload to blob storage: %w", err)
 	}
 
-	// 6. Update Passport Struct
-	passport.Status = domain.StatusPublished
 	passport.ImmutabilityHash = hashString
 	passport.StorageLocation = s3URL
-	now := time.Now()
 	passport.PublishedAt = &now
+	return nil
+}
 
-	// 7. Save to Repo
-	if err := s.repo.Update(ctx, passport); err != nil {
-		return nil, fmt.Errorf("failed to save published passport: %w", err)
+// recordManufacturer copies the tenant's registered DUNS number and country onto the passport.
+func (s *passportService) recordManufacturer(ctx context.Context, passport *domain.Passport) error {
+	if s.tenants == nil {
+		return nil
 	}
 
-	// 8. Invalidate Cache (Force next read to hit DB)
-	cacheKey := fmt.Sprintf("passport:%s", id.String())
-	go func() {
-		_ = s.cache.Delete(context.Background(), cacheKey)
-	}()
+	profile, err := s.tenants.GetTenantProfile(ctx, passport.ManufacturerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: manufacturer %s is not registered", domain.ErrInvalidInput, passport.ManufacturerID)
+		}
+		return fmt.Errorf("failed to fetch manufacturer profile: %w", err)
+	}
 
-	return passport, nil
+	passport.ManufacturerDUNS = strings.TrimSpace(profile.DUNSNumber)
+	passport.ManufacturerCountry = strings.ToUpper(strings.TrimSpace(profile.Country))
+	return nil
 }
 
-func (s *passportService) ListPassports(ctx context.Context, manufacturerID string) ([]*domain.Passport, error) {
-	return s.repo.FindByManufacturer(ctx, manufacturerID)
+func (s *passportService) ListPassports(ctx context.Context, manufacturerID string, query domain.PassportQuery) (*domain.PassportPage, error) {
+	// 1. Validate the Query
+	if query.Sort == "" {
+		query.Sort, query.Descending = domain.SortCreatedAt, true
+	}
+	if !query.Sort.IsValid() {
+		return nil, fmt.Errorf("%w: unknown sort %q", domain.ErrInvalidInput, query.Sort)
+	}
+	switch {
+	case query.Limit == 0:
+		query.Limit = domain.DefaultPassportPageSize
+	case query.Limit < 0 || query.Limit > domain.MaxPassportPageSize:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, domain.MaxPassportPageSize)
+	}
+	if query.After != nil && query.After.Order != query.Order() {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q", domain.ErrInvalidInput, query.After.Order)
+	}
+
+	// 2. Fetch one more than the page, to know whether another follows
+	limit := query.Limit
+	query.Limit++
+	passports, err := s.repo.FindByManufacturer(ctx, manufacturerID, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passports: %w", err)
+	}
+
+	page := &domain.PassportPage{Passports: passports}
+	if len(passports) > limit {
+		page.Passports = passports[:limit]
+		last := page.Passports[limit-1]
+		page.Next = &domain.PassportCursor{Order: query.Order(), Key: query.Sort.Key(last), ID: last.ID}
+	}
+	return page, nil
 }
 
 func (s *passportService) UpdatePassport(ctx context.Context, id uuid.UUID, manufacturerID string, payload []byte) (*domain.Passport, error) {
@@ -346,37 +592,251 @@ func (s *passportService) UpdatePassport(ctx context.Context, id uuid.UUID, manu
 		return nil, fmt.Errorf("cannot update published passport")
 	}
 
-	// 4. Schema Validation
-	schema, exists := s.schemas[passport.ProductCategory]
-	if !exists {
-		return nil, fmt.Errorf("unsupported category")
+	// 4. Schema Validation (drafts move to the currently active schema version)
+	compiled, err := s.schemas.active(ctx, passport.ProductCategory)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported category: %w", err)
 	}
 
-	var jsonInterface interface{}
-	if err := json.Unmarshal(payload, &jsonInterface); err != nil {
-		return nil, fmt.Errorf("%w: invalid JSON", domain.ErrInvalidInput)
+	doc, err := s.mergedAttributes(ctx, &domain.Passport{ID: passport.ID, ParentID: passport.ParentID, Attributes: payload})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := schema.Validate(jsonInterface); err != nil {
+	if err := validateAttributes(compiled.schema, doc, passport.Level); err != nil {
 		s.log.Warn("schema validation failed", "error", err)
 		return nil, fmt.Errorf("%w: schema validation failed: %v", domain.ErrInvalidInput, err)
 	}
 
 	// 5. Update Fields
 	passport.Attributes = json.RawMessage(payload)
+	passport.SchemaVersion = compiled.version
 	now := time.Now().UTC()
 	passport.UpdatedAt = now
+	s.indexText(passport, compiled, doc)
+	if err := passport.RecordEvent(domain.EventPassportUpdated); err != nil {
+		return nil, err
+	}
 
-	// 6. Save to Repo (Update)
-	if err := s.repo.Update(ctx, passport); err != nil {
+	// 6. Save to Repo (Update, with the event), unless it was published meanwhile
+	if err := s.repo.Update(ctx, passport, domain.StatusDraft); err != nil {
 		return nil, fmt.Errorf("failed to update passport: %w", err)
 	}
 
 	// 7. Invalidate Cache
+	s.invalidate(id)
+
+	return passport, nil
+}
+
+func (s *passportService) RevokePassport(ctx context.Context, id uuid.UUID, manufacturerID string, reason string) (*domain.Passport, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: revocation reason is required", domain.ErrInvalidInput)
+	}
+
+	// 1. Fetch Passport
+	passport, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch passport: %w", err)
+	}
+
+	// 2. Check Ownership
+	if passport.ManufacturerID != manufacturerID {
+		return nil, domain.ErrForbidden
+	}
+
+	// 3. Check Status (Only the live revision can be recalled)
+	switch passport.Status {
+	case domain.StatusRevoked:
+		return nil, domain.ErrPassportRevoked
+	case domain.StatusDraft, domain.StatusSuperseded:
+		return nil, fmt.Errorf("%w: only the current published revision can be revoked", domain.ErrInvalidInput)
+	}
+	if err := s.checkNoPublishedChildren(ctx, passport); err != nil {
+		return nil, err
+	}
+
+	// 4. Mark as Revoked
+	// The S3 object and ImmutabilityHash are left untouched: the original record stays auditable.
+	now := time.Now().UTC()
+	passport.Status = domain.StatusRevoked
+	passport.Revocation = &domain.Revocation{
+		Reason:    reason,
+		RevokedAt: now,
+	}
+	passport.UpdatedAt = now
+
+	if err := passport.RecordEvent(domain.EventPassportRevoked); err != nil {
+		return nil, err
+	}
+
+	// 5. Save to Repo (with the event), unless it was superseded or revoked meanwhile
+	if err := s.repo.Update(ctx, passport, domain.StatusPublished); err != nil {
+		return nil, fmt.Errorf("failed to save revoked passport: %w", err)
+	}
+
+	// 6. Invalidate Cache (the resolver must stop serving the product as valid)
+	s.invalidate(id)
+
+	return passport, nil
+}
+
+func (s *passportService) CreateRevision(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.Passport, error) {
+	// 1. Fetch the revision being replaced
+	source, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch passport: %w", err)
+	}
+
+	// 2. Check Ownership
+	if source.ManufacturerID != manufacturerID {
+		return nil, domain.ErrForbidden
+	}
+
+	// 3. Only the live revision can be revised (keeps the chain linear)
+	switch source.Status {
+	case domain.StatusRevoked:
+		return nil, domain.ErrPassportRevoked
+	case domain.StatusDraft:
+		return nil, fmt.Errorf("%w: drafts can be edited directly", domain.ErrInvalidInput)
+	case domain.StatusSuperseded:
+		return nil, fmt.Errorf("%w: passport already superseded by %s", domain.ErrConflict, source.SupersededByID)
+	}
+
+	// 4. Clone into a new Draft
+	now := time.Now().UTC()
+	previousID := source.ID
+	revision := &domain.Passport{
+		ID:                  uuid.New(),
+		ProductCategory:     source.ProductCategory,
+		Status:              domain.StatusDraft,
+		ManufacturerID:      source.ManufacturerID,
+		ManufacturerName:    source.ManufacturerName,
+		ManufacturerDUNS:    source.ManufacturerDUNS,
+		ManufacturerCountry: source.ManufacturerCountry,
+		GTIN:                source.GTIN,
+		BatchNumber:         source.BatchNumber,
+		SerialNumber:        source.SerialNumber,
+		Level:               source.Level,
+		ParentID:            source.ParentID,
+		Attributes:          append(json.RawMessage(nil), source.Attributes...),
+		CreatedAt:           now,
+		UpdatedAt:           now,
+		Version:             max(source.Version, 1) + 1,
+		PreviousVersionID:   &previousID,
+		SchemaVersion:       source.SchemaVersion,
+	}
+
+	// The revision is searchable like its source (same document, same schema version)
+	if compiled, err := s.schemas.version(ctx, revision.ProductCategory, revision.SchemaVersion); err != nil {
+		s.log.Warn("failed to load schema for indexing", "id", revision.ID, "error", err)
+	} else if doc, err := s.mergedAttributes(ctx, revision); err != nil {
+		s.log.Warn("failed to merge attributes for indexing", "id", revision.ID, "error", err)
+	} else {
+		s.indexText(revision, compiled, doc)
+	}
+
+	// 5. Save with its Event (the unique index on previous_version_id rejects a second pending revision)
+	if err := revision.RecordEvent(domain.EventPassportCreated); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInternal, err)
+	}
+	if err := s.repo.Save(ctx, revision); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, fmt.Errorf("%w: a revision of this passport already exists", domain.ErrConflict)
+		}
+		s.log.Error("failed to persist revision", "error", err)
+		return nil, fmt.Errorf("%w: failed to save", domain.ErrInternal)
+	}
+
+	return revision, nil
+}
+
+func (s *passportService) GetRevisionHistory(ctx context.Context, id uuid.UUID) ([]domain.PassportVersion, error) {
+	versions, err := s.repo.FindVersionHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pending drafts are not part of the public lineage
+	history := make([]domain.PassportVersion, 0, len(versions))
+	for _, v := range versions {
+		if v.Status != domain.StatusDraft {
+			history = append(history, v)
+		}
+	}
+	return history, nil
+}
+
+func (s *passportService) ResolveIdentifiers(ctx context.Context, ids domain.ProductIdentifiers) (uuid.UUID, error) {
+	ids, err := ids.Normalize()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if ids.GTIN == "" {
+		return uuid.Nil, fmt.Errorf("%w: a GTIN is required", domain.ErrInvalidInput)
+	}
+
+	matches, err := s.repo.FindByIdentifiers(ctx, ids)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to look up identifiers: %w", err)
+	}
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("gtin %s: %w", ids.GTIN, domain.ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+
+	// Uniqueness is per manufacturer: the passport of the tenant owning the GTIN's GS1 company
+	// prefix wins, and without a registered owner, never guess whose passport a scan refers to
+	owner, err := s.companyPrefixOwner(ctx, ids.GTIN)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if owner != "" {
+		for _, id := range matches {
+			passport, err := s.repo.GetByID(ctx, id)
+			if err != nil {
+				return uuid.Nil, fmt.Errorf("failed to fetch passport: %w", err)
+			}
+			if passport.ManufacturerID == owner {
+				return id, nil
+			}
+		}
+	}
+	return uuid.Nil, fmt.Errorf("%w: gtin %s is registered by several manufacturers", domain.ErrConflict, ids.GTIN)
+}
+
+// companyPrefixOwner returns the tenant the GS1 company prefix of the GTIN is registered to
+// ("" if none, or without a tenant repository).
+func (s *passportService) companyPrefixOwner(ctx context.Context, gtin string) (string, error) {
+	if s.tenants == nil || gtin == "" {
+		return "", nil
+	}
+	owner, err := s.tenants.FindCompanyPrefixOwner(ctx, gtin)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up GS1 company prefix: %w", err)
+	}
+	return owner, nil
+}
+
+// checkCompanyPrefix refuses a GTIN whose GS1 company prefix is registered to another tenant.
+func (s *passportService) checkCompanyPrefix(ctx context.Context, manufacturerID, gtin string) error {
+	owner, err := s.companyPrefixOwner(ctx, gtin)
+	if err != nil {
+		return err
+	}
+	if owner != "" && owner != manufacturerID {
+		return fmt.Errorf("%w: gtin %s belongs to a GS1 company prefix registered to another manufacturer", domain.ErrForbidden, gtin)
+	}
+	return nil
+}
+
+// invalidate drops the cached copy of a passport without blocking the caller.
+func (s *passportService) invalidate(id uuid.UUID) {
 	cacheKey := fmt.Sprintf("passport:%s", id.String())
 	go func() {
 		_ = s.cache.Delete(context.Background(), cacheKey)
 	}()
-
-	return passport, nil
 }
@@ -11,8 +11,12 @@ package service_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"testing"
@@ -23,6 +27,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // --- Mocks ---
@@ -36,6 +41,24 @@ func (m *MockPassportRepository) Save(ctx context.Context, passport *domain.Pass
 	return args.Error(0)
 }
 
+func (m *MockPassportRepository) SaveBatch(ctx context.Context, passports []*domain.Passport) error {
+	args := m.Called(ctx, passports)
+	return args.Error(0)
+}
+
+func (m *MockPassportRepository) FindDrafts(ctx context.Context, manufacturerID string, category domain.ProductCategory, createdBefore *time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, manufacturerID, category, createdBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockPassportRepository) PublishBatch(ctx context.Context, passports []*domain.Passport) error {
+	args := m.Called(ctx, passports)
+	return args.Error(0)
+}
+
 func (m *MockPassportRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Passport, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -49,16 +72,45 @@ func (m *MockPassportRepository) FindByCategory(ctx context.Context, category do
 	return args.Get(0).([]*domain.Passport), args.Error(1)
 }
 
-func (m *MockPassportRepository) FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.Passport, error) {
-	args := m.Called(ctx, manufacturerID)
+func (m *MockPassportRepository) FindByManufacturer(ctx context.Context, manufacturerID string, query domain.PassportQuery) ([]*domain.Passport, error) {
+	args := m.Called(ctx, manufacturerID, query)
 	return args.Get(0).([]*domain.Passport), args.Error(1)
 }
 
-func (m *MockPassportRepository) Update(ctx context.Context, passport *domain.Passport) error {
-	args := m.Called(ctx, passport)
+func (m *MockPassportRepository) Update(ctx context.Context, passport *domain.Passport, expected domain.PassportStatus) error {
+	args := m.Called(ctx, passport, expected)
+	return args.Error(0)
+}
+
+func (m *MockPassportRepository) FindVersionHistory(ctx context.Context, id uuid.UUID) ([]domain.PassportVersion, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PassportVersion), args.Error(1)
+}
+
+func (m *MockPassportRepository) FindByIdentifiers(ctx context.Context, ids domain.ProductIdentifiers) ([]uuid.UUID, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockPassportRepository) CountPublishedChildren(ctx context.Context, parentIDs []uuid.UUID) (int, error) {
+	args := m.Called(ctx, parentIDs)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPassportRepository) SaveSearchDocuments(ctx context.Context, passports []*domain.Passport) error {
+	args := m.Called(ctx, passports)
 	return args.Error(0)
 }
 
+func (m *MockPassportRepository) SearchText(ctx context.Context, query domain.TextSearchQuery) ([]domain.TextSearchHit, error) {
+	args := m.Called(ctx, query)
+	hits, _ := args.Get(0).([]domain.TextSearchHit)
+	return hits, args.Error(1)
+}
+
 type MockBlobStorage struct {
 	mock.Mock
 }
@@ -113,10 +165,9 @@ func TestCreatePassport_Success(t *testing.T) {
 	mockRepo := new(MockPassportRepository)
 	mockCache := new(MockCacheRepository)
 	mockBlob := new(MockBlobStorage)
-	mockBus := new(MockEventBus)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	svc, err := service.NewPassportService(mockRepo, mockCache, mockBlob, mockBus, logger)
+	svc, err := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -140,10 +191,9 @@ func TestCreatePassport_Success(t *testing.T) {
 	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("cache miss"))
 	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Passport")).Return(nil)
 	mockCache.On("SetIdempotency", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	mockBus.On("Publish", mock.Anything, "events:passport_created", mock.Anything).Return(nil)
 
 	// Execute
-	passport, err := svc.CreatePassport(ctx, manufacturerID, manufacturerID, category, payloadBytes)
+	passport, err := svc.CreatePassport(ctx, manufacturerID, manufacturerID, category, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, payloadBytes)
 
 	// Assert
 	assert.NoError(t, err)
@@ -151,10 +201,14 @@ func TestCreatePassport_Success(t *testing.T) {
 	assert.Equal(t, manufacturerID, passport.ManufacturerID)
 	assert.Equal(t, domain.StatusDraft, passport.Status)
 
+	// The event is handed to the repository with the passport (outbox)
+	require.Len(t, passport.Events, 1)
+	assert.Equal(t, "events:passport_created", passport.Events[0].Channel)
+	assert.Equal(t, passport.ID, passport.Events[0].PassportID)
+
 	mockRepo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
 	mockBlob.AssertExpectations(t)
-	mockBus.AssertExpectations(t)
 }
 
 func TestCreatePassport_InvalidSchema(t *testing.T) {
@@ -162,10 +216,9 @@ func TestCreatePassport_InvalidSchema(t *testing.T) {
 	mockRepo := new(MockPassportRepository)
 	mockCache := new(MockCacheRepository)
 	mockBlob := new(MockBlobStorage)
-	mockBus := new(MockEventBus)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, mockBus, logger)
+	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
 	ctx := context.Background()
 
 	// Invalid Payload (Missing required fields)
@@ -178,7 +231,7 @@ func TestCreatePassport_InvalidSchema(t *testing.T) {
 	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("cache miss"))
 
 	// Execute
-	passport, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery, payloadBytes)
+	passport, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, payloadBytes)
 
 	// Assertions
 	assert.Error(t, err)
@@ -191,10 +244,9 @@ func TestCreatePassport_IdempotencyHit(t *testing.T) {
 	mockRepo := new(MockPassportRepository)
 	mockCache := new(MockCacheRepository)
 	mockBlob := new(MockBlobStorage)
-	mockBus := new(MockEventBus)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, mockBus, logger)
+	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
 	ctx := context.Background()
 
 	existingID := uuid.New()
@@ -207,7 +259,7 @@ func TestCreatePassport_IdempotencyHit(t *testing.T) {
 	mockRepo.On("GetByID", ctx, existingID).Return(existingPassport, nil)
 
 	// Execute
-	passport, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery, []byte("{}"))
+	passport, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, []byte("{}"))
 
 	// Assertions
 	assert.NoError(t, err)
@@ -220,29 +272,35 @@ func TestPublishPassport_Success(t *testing.T) {
 	mockRepo := new(MockPassportRepository)
 	mockCache := new(MockCacheRepository)
 	mockBlob := new(MockBlobStorage)
-	mockBus := new(MockEventBus)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, mockBus, logger)
+	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
 	ctx := context.Background()
 
 	id := uuid.New()
 	passport := &domain.Passport{
-		ID:         id,
-		Status:     domain.StatusDraft,
-		Attributes: json.RawMessage(`{"foo":"bar"}`),
+		ID:                  id,
+		ProductCategory:     domain.CategoryBattery,
+		Status:              domain.StatusDraft,
+		ManufacturerID:      "mfg-1",
+		ManufacturerName:    "Acme Batteries GmbH",
+		ManufacturerDUNS:    "123456789",
+		ManufacturerCountry: "DE",
+		Attributes:          json.RawMessage(`{"foo":"bar"}`),
 	}
 
 	// Expectations
 	mockRepo.On("GetByID", ctx, id).Return(passport, nil)
 	mockBlob.On("UploadJSON", ctx, "passports", mock.Anything, mock.Anything).Return("s3://bucket/key", nil)
-	mockRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Passport) bool {
-		return p.Status == domain.StatusPublished && p.StorageLocation == "s3://bucket/key" && p.ImmutabilityHash != ""
+	mockRepo.On("PublishBatch", ctx, mock.MatchedBy(func(ps []*domain.Passport) bool {
+		p := ps[0]
+		return len(ps) == 1 && p.Status == domain.StatusPublished && p.StorageLocation == "s3://bucket/key" && p.ImmutabilityHash != "" &&
+			len(p.Events) == 1 && p.Events[0].Channel == "events:passport_published"
 	})).Return(nil)
 	mockCache.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
 	// Execute
-	published, err := svc.PublishPassport(ctx, id)
+	published, err := svc.PublishPassport(ctx, id, "mfg-1")
 
 	// Assertions
 	assert.NoError(t, err)
@@ -254,3 +312,711 @@ func TestPublishPassport_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockBlob.AssertExpectations(t)
 }
+
+func TestUpdatePassport_RecordsEvent(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	draft := &domain.Passport{ID: uuid.New(), ProductCategory: domain.CategoryTextile, Status: domain.StatusDraft, ManufacturerID: "mfg-1", Version: 1}
+
+	// Expectations: the updated event is saved with the new attributes
+	mockRepo.On("GetByID", ctx, draft.ID).Return(draft, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Passport) bool {
+		return string(p.Attributes) == textileAttributes && len(p.Events) == 1 && p.Events[0].Channel == "events:passport_updated"
+	}), domain.StatusDraft).Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	_, err := svc.UpdatePassport(ctx, draft.ID, "mfg-1", []byte(textileAttributes))
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListPassports_Pagination(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	created := time.Date(2025, 11, 28, 9, 30, 0, 0, time.UTC)
+	passports := []*domain.Passport{
+		{ID: uuid.New(), ManufacturerID: "mfg-1", CreatedAt: created},
+		{ID: uuid.New(), ManufacturerID: "mfg-1", CreatedAt: created.Add(-time.Minute)},
+		{ID: uuid.New(), ManufacturerID: "mfg-1", CreatedAt: created.Add(-2 * time.Minute)},
+	}
+
+	// Expectations: one more passport than the page is fetched, newest first by default
+	mockRepo.On("FindByManufacturer", ctx, "mfg-1", mock.MatchedBy(func(q domain.PassportQuery) bool {
+		return q.Limit == 3 && q.Sort == domain.SortCreatedAt && q.Descending && q.Filter.Status == domain.StatusDraft
+	})).Return(passports, nil).Once()
+
+	page, err := svc.ListPassports(ctx, "mfg-1", domain.PassportQuery{Limit: 2, Filter: domain.PassportFilter{Status: domain.StatusDraft}})
+
+	require.NoError(t, err)
+	assert.Len(t, page.Passports, 2)
+	require.NotNil(t, page.Next)
+	assert.Equal(t, domain.PassportCursor{Order: "-createdAt", Key: "2025-11-28T09:29:00Z", ID: passports[1].ID}, *page.Next)
+
+	// The last page has no cursor
+	mockRepo.On("FindByManufacturer", ctx, "mfg-1", mock.MatchedBy(func(q domain.PassportQuery) bool {
+		return q.After != nil && q.After.ID == passports[1].ID
+	})).Return(passports[2:], nil).Once()
+
+	page, err = svc.ListPassports(ctx, "mfg-1", domain.PassportQuery{Limit: 2, Sort: domain.SortCreatedAt, Descending: true, After: page.Next})
+
+	require.NoError(t, err)
+	assert.Len(t, page.Passports, 1)
+	assert.Nil(t, page.Next)
+
+	// A cursor only continues the order it was issued for
+	_, err = svc.ListPassports(ctx, "mfg-1", domain.PassportQuery{Sort: domain.SortSerialNumber, After: &domain.PassportCursor{Order: "-createdAt", ID: uuid.New()}})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	_, err = svc.ListPassports(ctx, "mfg-1", domain.PassportQuery{Limit: domain.MaxPassportPageSize + 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRevokePassport_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	mockBlob := new(MockBlobStorage)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	id := uuid.New()
+	publishedAt := time.Now().Add(-24 * time.Hour)
+	passport := &domain.Passport{
+		ID:               id,
+		ManufacturerID:   "mfg-1",
+		Status:           domain.StatusPublished,
+		PublishedAt:      &publishedAt,
+		ImmutabilityHash: "abc123",
+		StorageLocation:  "s3://passports/key",
+		Attributes:       json.RawMessage(`{"foo":"bar"}`),
+	}
+
+	// Expectations
+	mockRepo.On("GetByID", ctx, id).Return(passport, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Passport) bool {
+		return p.Status == domain.StatusRevoked && p.Revocation != nil && p.Revocation.Reason == "Thermal runaway recall" &&
+			len(p.Events) == 1 && p.Events[0].Channel == "events:passport_revoked"
+	}), domain.StatusPublished).Return(nil)
+	mockCache.On("Delete", mock.Anything, "passport:"+id.String()).Return(nil).Maybe()
+
+	// Execute
+	revoked, err := svc.RevokePassport(ctx, id, "mfg-1", "  Thermal runaway recall ")
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusRevoked, revoked.Status)
+	assert.False(t, revoked.Revocation.RevokedAt.IsZero())
+	// The immutable record is kept for audit
+	assert.Equal(t, "abc123", revoked.ImmutabilityHash)
+	assert.Equal(t, "s3://passports/key", revoked.StorageLocation)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRevokePassport_Rejections(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		status   domain.PassportStatus
+		caller   string
+		reason   string
+		expected error
+	}{
+		{"Not Owner", domain.StatusPublished, "mfg-other", "recall", domain.ErrForbidden},
+		{"Already Revoked", domain.StatusRevoked, "mfg-1", "recall", domain.ErrPassportRevoked},
+		{"Draft", domain.StatusDraft, "mfg-1", "recall", domain.ErrInvalidInput},
+		{"Missing Reason", domain.StatusPublished, "mfg-1", "   ", domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPassportRepository)
+			svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+
+			id := uuid.New()
+			mockRepo.On("GetByID", ctx, id).Return(&domain.Passport{ID: id, ManufacturerID: "mfg-1", Status: tt.status}, nil).Maybe()
+
+			_, err := svc.RevokePassport(ctx, id, tt.caller, tt.reason)
+
+			assert.ErrorIs(t, err, tt.expected)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRevokePassport_SupersededMeanwhile(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	// A revision is published between the read and the write: the update only applies to a
+	// passport still PUBLISHED, so the superseded row is left alone
+	id := uuid.New()
+	mockRepo.On("GetByID", ctx, id).Return(&domain.Passport{ID: id, ManufacturerID: "mfg-1", Status: domain.StatusPublished}, nil)
+	mockRepo.On("Update", ctx, mock.Anything, domain.StatusPublished).Return(fmt.Errorf("%w: passport %s is no longer published", domain.ErrConflict, id))
+
+	_, err := svc.RevokePassport(ctx, id, "mfg-1", "recall")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockCache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestCreateRevision_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(MockPassportRepository)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	id := uuid.New()
+	source := &domain.Passport{
+		ID:               id,
+		ProductCategory:  domain.CategoryBattery,
+		ManufacturerID:   "mfg-1",
+		Status:           domain.StatusPublished,
+		Version:          2,
+		ImmutabilityHash: "hash-v2",
+		Attributes:       json.RawMessage(`{"batteryModel":"X1"}`),
+	}
+
+	// Expectations
+	mockRepo.On("GetByID", ctx, id).Return(source, nil)
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(p *domain.Passport) bool {
+		return p.ID != id && p.Status == domain.StatusDraft && p.Version == 3 &&
+			p.PreviousVersionID != nil && *p.PreviousVersionID == id && p.ImmutabilityHash == ""
+	})).Return(nil)
+
+	// Execute
+	revision, err := svc.CreateRevision(ctx, id, "mfg-1")
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"batteryModel":"X1"}`, string(revision.Attributes))
+	assert.Equal(t, domain.StatusPublished, source.Status, "source must be left untouched until the revision is published")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateRevision_PendingRevisionConflict(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	id := uuid.New()
+	mockRepo.On("GetByID", ctx, id).Return(&domain.Passport{ID: id, ManufacturerID: "mfg-1", Status: domain.StatusPublished}, nil)
+	mockRepo.On("Save", ctx, mock.Anything).Return(domain.ErrConflict)
+
+	_, err := svc.CreateRevision(ctx, id, "mfg-1")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestPublishPassport_SupersedesPreviousRevision(t *testing.T) {
+	// Setup
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	mockBlob := new(MockBlobStorage)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	previousID := uuid.New()
+	id := uuid.New()
+	revision := &domain.Passport{
+		ID:                  id,
+		ProductCategory:     domain.CategoryBattery,
+		Status:              domain.StatusDraft,
+		ManufacturerID:      "mfg-1",
+		ManufacturerName:    "Acme Batteries GmbH",
+		ManufacturerDUNS:    "123456789",
+		ManufacturerCountry: "DE",
+		Version:             2,
+		PreviousVersionID:   &previousID,
+		Attributes:          json.RawMessage(`{"foo":"baz"}`),
+	}
+	previous := &domain.Passport{ID: previousID, Status: domain.StatusPublished, Version: 1}
+
+	// Expectations
+	mockRepo.On("GetByID", ctx, id).Return(revision, nil)
+	mockRepo.On("GetByID", ctx, previousID).Return(previous, nil)
+	mockBlob.On("UploadJSON", ctx, "passports", "passports/"+id.String()+".json", mock.Anything).Return("s3://bucket/v2", nil)
+	// Both revisions are written in one transaction, with an event each
+	mockRepo.On("PublishBatch", ctx, mock.MatchedBy(func(ps []*domain.Passport) bool {
+		p := ps[0]
+		return len(ps) == 1 && p.Status == domain.StatusPublished && len(p.Events) == 2 &&
+			p.Events[0].Channel == "events:passport_published" && p.Events[0].PassportID == id &&
+			p.Events[1].Channel == "events:passport_superseded" && p.Events[1].PassportID == previousID
+	})).Return(nil)
+	mockCache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	// Execute
+	published, err := svc.PublishPassport(ctx, id, "mfg-1")
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, published.Status)
+	assert.Equal(t, domain.StatusSuperseded, previous.Status)
+	assert.Equal(t, &id, previous.SupersededByID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPublishPassport_RejectsStaleRevisions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	previousID := uuid.New()
+
+	tests := []struct {
+		name     string
+		status   domain.PassportStatus
+		previous domain.PassportStatus
+		want     error
+	}{
+		{"superseded passport", domain.StatusSuperseded, "", domain.ErrPassportAlreadyPublished},
+		{"revoked predecessor", domain.StatusDraft, domain.StatusRevoked, domain.ErrPassportRevoked},
+		{"superseded predecessor", domain.StatusDraft, domain.StatusSuperseded, domain.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPassportRepository)
+			mockBlob := new(MockBlobStorage)
+			svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
+
+			id := uuid.New()
+			mockRepo.On("GetByID", ctx, id).Return(&domain.Passport{ID: id, ManufacturerID: "mfg-1", Status: tt.status, Version: 2, PreviousVersionID: &previousID}, nil)
+			mockRepo.On("GetByID", ctx, previousID).Return(&domain.Passport{ID: previousID, Status: tt.previous}, nil).Maybe()
+
+			_, err := svc.PublishPassport(ctx, id, "mfg-1")
+
+			assert.ErrorIs(t, err, tt.want)
+			mockBlob.AssertNotCalled(t, "UploadJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "PublishBatch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPublishPassport_Forbidden(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockBlob := new(MockBlobStorage)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	// Another tenant's draft revision must not replace its live passport
+	id, previousID := uuid.New(), uuid.New()
+	mockRepo.On("GetByID", ctx, id).Return(&domain.Passport{ID: id, ManufacturerID: "mfg-1", Status: domain.StatusDraft, Version: 2, PreviousVersionID: &previousID}, nil)
+
+	_, err := svc.PublishPassport(ctx, id, "mfg-2")
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockBlob.AssertNotCalled(t, "UploadJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "PublishBatch", mock.Anything, mock.Anything)
+}
+
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) GetTenantProfile(ctx context.Context, tenantID string) (*domain.TenantProfile, error) {
+	args := m.Called(ctx, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TenantProfile), args.Error(1)
+}
+
+func (m *MockTenantRepository) FindCompanyPrefixOwner(ctx context.Context, gtin string) (string, error) {
+	args := m.Called(ctx, gtin)
+	return args.String(0), args.Error(1)
+}
+
+func TestPublishPassport_UploadsValidatedEnvelope(t *testing.T) {
+	// Setup
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	mockBlob := new(MockBlobStorage)
+	tenants := new(MockTenantRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, err := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, tenants, nil, "https://tapi.eu/", logger)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	id := uuid.New()
+	mockRepo.On("GetByID", ctx, id).Return(&domain.Passport{
+		ID:               id,
+		ProductCategory:  domain.CategoryTextile,
+		Status:           domain.StatusDraft,
+		ManufacturerID:   "mfg-1",
+		ManufacturerName: "Acme Textiles",
+		Attributes:       json.RawMessage(`{"fiberComposition":[{"material":"cotton","percentage":100}]}`),
+	}, nil)
+	tenants.On("GetTenantProfile", ctx, "mfg-1").Return(&domain.TenantProfile{ID: "mfg-1", DUNSNumber: "987654321", Country: "it"}, nil)
+
+	var uploaded []byte
+	mockBlob.On("UploadJSON", ctx, "passports", "passports/"+id.String()+".json", mock.MatchedBy(func(data []byte) bool {
+		uploaded = data
+		return true
+	})).Return("s3://bucket/key", nil)
+	mockRepo.On("PublishBatch", ctx, mock.MatchedBy(func(ps []*domain.Passport) bool {
+		return ps[0].ManufacturerDUNS == "987654321" && ps[0].ManufacturerCountry == "IT"
+	})).Return(nil)
+	mockCache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	// Execute
+	published, err := svc.PublishPassport(ctx, id, "mfg-1")
+
+	// Assertions: the stored object is the whole envelope, and the hash covers it
+	require.NoError(t, err)
+	var envelope domain.PassportEnvelope
+	require.NoError(t, json.Unmarshal(uploaded, &envelope))
+	assert.Equal(t, id, envelope.PassportID)
+	assert.Equal(t, "https://tapi.eu/r/"+id.String(), envelope.PublicURL)
+	assert.Equal(t, domain.StatusPublished, envelope.Status)
+	assert.Equal(t, domain.EnvelopeManufacturer{Name: "Acme Textiles", DUNSNumber: "987654321", Country: "IT"}, envelope.Manufacturer)
+	assert.True(t, envelope.IssuanceDate.Equal(*published.PublishedAt))
+	assert.JSONEq(t, `{"fiberComposition":[{"material":"cotton","percentage":100}]}`, string(envelope.Payload))
+
+	sum := sha256.Sum256(uploaded)
+	assert.Equal(t, hex.EncodeToString(sum[:]), published.ImmutabilityHash)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPublishPassport_RejectsInvalidEnvelope(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		profile *domain.TenantProfile
+		lookup  error
+	}{
+		{"unregistered manufacturer", nil, domain.ErrNotFound},
+		{"missing DUNS number", &domain.TenantProfile{Country: "DE"}, nil},
+		{"malformed DUNS number", &domain.TenantProfile{DUNSNumber: "12-345-6789", Country: "DE"}, nil},
+		{"missing country", &domain.TenantProfile{DUNSNumber: "123456789"}, nil},
+		{"country name instead of code", &domain.TenantProfile{DUNSNumber: "123456789", Country: "Germany"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPassportRepository)
+			mockBlob := new(MockBlobStorage)
+			tenants := new(MockTenantRepository)
+			svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), mockBlob, nil, nil, tenants, nil, "https://tapi.eu", logger)
+
+			id := uuid.New()
+			mockRepo.On("GetByID", ctx, id).Return(&domain.Passport{
+				ID:               id,
+				ProductCategory:  domain.CategoryBattery,
+				Status:           domain.StatusDraft,
+				ManufacturerID:   "mfg-1",
+				ManufacturerName: "Acme Batteries GmbH",
+				Attributes:       json.RawMessage(`{"foo":"bar"}`),
+			}, nil)
+			tenants.On("GetTenantProfile", ctx, "mfg-1").Return(tt.profile, tt.lookup)
+
+			_, err := svc.PublishPassport(ctx, id, "mfg-1")
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput), "got %v", err)
+			mockBlob.AssertNotCalled(t, "UploadJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "PublishBatch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreatePassport_Electronics(t *testing.T) {
+	// Setup
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	payload := []byte(`{
+		"productModel": "Phone 5",
+		"deviceType": "SMARTPHONE",
+		"repairability": {"repairabilityScore": 7.4, "repairabilityClass": "B"},
+		"sparePartsAvailability": {"minimumYears": 7, "parts": [{"partName": "Battery", "userReplaceable": true}]},
+		"softwareSupport": {"minimumYears": 5, "securityUpdatesUntil": "2031-01-01"},
+		"energyClass": "A",
+		"hazardousSubstances": [{"substance": "Lead", "casNumber": "7439-92-1", "concentration": 0.08}]
+	}`)
+
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("cache miss"))
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Passport")).Return(nil)
+	mockCache.On("SetIdempotency", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	passport, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryElectronic, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, payload)
+
+	assert.NoError(t, err)
+	attrs, err := passport.GetElectronicAttributes()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, attrs.SparePartsAvailability.MinimumYears)
+	assert.Equal(t, "7439-92-1", attrs.HazardousSubstances[0].CASNumber)
+
+	// Missing the mandatory ESPR blocks is rejected
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("cache miss"))
+	_, err = svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryElectronic, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, []byte(`{"productModel": "Phone 5"}`))
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestCreatePassport_GS1Identifiers(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+	payload := []byte(`{"garmentType": "T-Shirt", "fiberComposition": [{"fiberName": "COTTON", "percentage": 100}], "origin": {}, "recyclability": {}}`)
+
+	t.Run("Invalid check digit", func(t *testing.T) {
+		_, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryTextile, domain.ProductIdentifiers{GTIN: "9506000134353"}, domain.PassportHierarchy{}, payload)
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	})
+
+	t.Run("Already registered", func(t *testing.T) {
+		mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("miss")).Once()
+		mockRepo.On("Save", ctx, mock.MatchedBy(func(p *domain.Passport) bool {
+			// Stored in GTIN-14 form
+			return p.GTIN == "09506000134352" && p.SerialNumber == "S1"
+		})).Return(domain.ErrConflict).Once()
+
+		_, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryTextile, domain.ProductIdentifiers{GTIN: "9506000134352", SerialNumber: "S1"}, domain.PassportHierarchy{}, payload)
+
+		assert.True(t, errors.Is(err, domain.ErrConflict))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestResolveIdentifiers(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	id := uuid.New()
+	item := domain.ProductIdentifiers{GTIN: "09506000134352", SerialNumber: "S1"}
+	mockRepo.On("FindByIdentifiers", ctx, item).Return([]uuid.UUID{id}, nil)
+	mockRepo.On("FindByIdentifiers", ctx, domain.ProductIdentifiers{GTIN: "09506000134352", BatchNumber: "L1"}).Return([]uuid.UUID{}, nil)
+	mockRepo.On("FindByIdentifiers", ctx, domain.ProductIdentifiers{GTIN: "09506000134352"}).Return([]uuid.UUID{uuid.New(), uuid.New()}, nil)
+
+	// GTIN-13 input is matched in its GTIN-14 form
+	got, err := svc.ResolveIdentifiers(ctx, domain.ProductIdentifiers{GTIN: "9506000134352", SerialNumber: "S1"})
+	assert.NoError(t, err)
+	assert.Equal(t, id, got)
+
+	_, err = svc.ResolveIdentifiers(ctx, domain.ProductIdentifiers{GTIN: "09506000134352", BatchNumber: "L1"})
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+	_, err = svc.ResolveIdentifiers(ctx, domain.ProductIdentifiers{GTIN: "09506000134352"})
+	assert.True(t, errors.Is(err, domain.ErrConflict), "ambiguous keys must not resolve")
+
+	_, err = svc.ResolveIdentifiers(ctx, domain.ProductIdentifiers{SerialNumber: "S1"})
+	assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+}
+
+func TestResolveIdentifiers_PrefersCompanyPrefixOwner(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	tenants := new(MockTenantRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, tenants, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	// Another tenant registered the same GTIN and serial first: the owner's codes still resolve
+	squatterID, ownerID := uuid.New(), uuid.New()
+	ids := domain.ProductIdentifiers{GTIN: "09506000134352", SerialNumber: "S1"}
+	mockRepo.On("FindByIdentifiers", ctx, ids).Return([]uuid.UUID{squatterID, ownerID}, nil)
+	mockRepo.On("GetByID", ctx, squatterID).Return(&domain.Passport{ID: squatterID, ManufacturerID: "mfg-squatter"}, nil)
+	mockRepo.On("GetByID", ctx, ownerID).Return(&domain.Passport{ID: ownerID, ManufacturerID: "mfg-1"}, nil)
+	tenants.On("FindCompanyPrefixOwner", ctx, "09506000134352").Return("mfg-1", nil).Once()
+
+	got, err := svc.ResolveIdentifiers(ctx, ids)
+
+	require.NoError(t, err)
+	assert.Equal(t, ownerID, got)
+
+	// Without a registered owner, the scan stays ambiguous
+	tenants.On("FindCompanyPrefixOwner", ctx, "09506000134352").Return("", nil).Once()
+
+	_, err = svc.ResolveIdentifiers(ctx, ids)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestCreatePassport_RejectsForeignCompanyPrefix(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	tenants := new(MockTenantRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, tenants, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+	payload := []byte(`{"garmentType": "T-Shirt", "fiberComposition": [{"fiberName": "COTTON", "percentage": 100}], "origin": {}, "recyclability": {}}`)
+
+	tenants.On("FindCompanyPrefixOwner", ctx, "09506000134352").Return("mfg-1", nil)
+
+	_, err := svc.CreatePassport(ctx, "mfg-squatter", "Squatter", domain.CategoryTextile, domain.ProductIdentifiers{GTIN: "9506000134352", SerialNumber: "S1"}, domain.PassportHierarchy{}, payload)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCreatePassport_ItemInheritsFromModel(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	// The model carries everything but the unit-specific fields
+	modelID := uuid.New()
+	model := &domain.Passport{
+		ID:              modelID,
+		ProductCategory: domain.CategoryBattery,
+		Status:          domain.StatusPublished,
+		ManufacturerID:  "mfg-1",
+		GTIN:            "09506000134352",
+		Level:           domain.LevelModel,
+		Attributes: json.RawMessage(`{"batteryModel": "X1", "chemistry": "LITHIUM_ION", "ratedCapacity": 100,
+			"carbonFootprint": {"totalCarbonFootprint": 50, "shareOfRenewables": 90}, "materialComposition": []}`),
+	}
+	mockRepo.On("GetByID", ctx, modelID).Return(model, nil)
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("miss"))
+
+	t.Run("Only own fields", func(t *testing.T) {
+		own := `{"serialNumber": "S1", "carbonFootprint": {"shareOfRenewables": 95}}`
+		mockRepo.On("Save", ctx, mock.MatchedBy(func(p *domain.Passport) bool {
+			// Stored without the inherited fields, under the model's GTIN
+			return string(p.Attributes) == own && p.ParentID != nil && *p.ParentID == modelID &&
+				p.Level == domain.LevelItem && p.GTIN == model.GTIN && p.SerialNumber == "S1"
+		})).Return(nil).Once()
+		mockCache.On("SetIdempotency", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery,
+			domain.ProductIdentifiers{SerialNumber: "S1"}, domain.PassportHierarchy{ParentID: &modelID}, []byte(own))
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Merged document is validated", func(t *testing.T) {
+		_, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery,
+			domain.ProductIdentifiers{SerialNumber: "S2"}, domain.PassportHierarchy{ParentID: &modelID}, []byte(`{"chemistry": "PLUTONIUM"}`))
+
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	})
+
+	t.Run("Parent of another manufacturer", func(t *testing.T) {
+		_, err := svc.CreatePassport(ctx, "mfg-2", "Manufacturer 2", domain.CategoryBattery,
+			domain.ProductIdentifiers{}, domain.PassportHierarchy{ParentID: &modelID}, []byte(`{}`))
+
+		assert.True(t, errors.Is(err, domain.ErrForbidden))
+	})
+
+	t.Run("Draft parent", func(t *testing.T) {
+		draftID := uuid.New()
+		mockRepo.On("GetByID", ctx, draftID).Return(&domain.Passport{ID: draftID, ProductCategory: domain.CategoryBattery, Status: domain.StatusDraft,
+			ManufacturerID: "mfg-1", Level: domain.LevelModel, Attributes: json.RawMessage(`{}`)}, nil)
+
+		_, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery,
+			domain.ProductIdentifiers{}, domain.PassportHierarchy{ParentID: &draftID}, []byte(`{}`))
+
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	})
+
+	t.Run("Incomplete model", func(t *testing.T) {
+		// Missing required fields are left to the items
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*domain.Passport")).Return(nil).Once()
+		mockCache.On("SetIdempotency", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery,
+			domain.ProductIdentifiers{}, domain.PassportHierarchy{Level: domain.LevelModel}, []byte(`{"batteryModel": "X2", "carbonFootprint": {"totalCarbonFootprint": 40}}`))
+		assert.NoError(t, err)
+
+		// ...but not wrong ones
+		_, err = svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryBattery,
+			domain.ProductIdentifiers{}, domain.PassportHierarchy{Level: domain.LevelModel}, []byte(`{"ratedCapacity": "high"}`))
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	})
+}
+
+func TestGetPassport_MergesInheritedAttributes(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	modelID, batchID, itemID := uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("GetByID", mock.Anything, modelID).Return(&domain.Passport{ID: modelID, Level: domain.LevelModel,
+		Attributes: json.RawMessage(`{"batteryModel": "X1", "weight": 12.50, "carbonFootprint": {"totalCarbonFootprint": 50, "shareOfRenewables": 90}}`)}, nil)
+	mockRepo.On("GetByID", mock.Anything, batchID).Return(&domain.Passport{ID: batchID, Level: domain.LevelBatch, ParentID: &modelID,
+		Attributes: json.RawMessage(`{"manufacturingPlace": "Plant 2", "carbonFootprint": {"shareOfRenewables": 95}}`)}, nil)
+	mockRepo.On("GetByID", mock.Anything, itemID).Return(&domain.Passport{ID: itemID, Level: domain.LevelItem, ParentID: &batchID, ManufacturerID: "mfg-1",
+		Attributes: json.RawMessage(`{"serialNumber": "S1", "weight": null}`)}, nil)
+	mockCache.On("Get", mock.Anything, mock.Anything).Return("", errors.New("miss"))
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	viewCtx := context.WithValue(ctx, domain.ViewContextKey, domain.ViewContextRestricted)
+	viewCtx = context.WithValue(viewCtx, domain.ViewerTenantIDKey, "mfg-1")
+	passport, err := svc.GetPassport(viewCtx, itemID)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"batteryModel": "X1",
+		"manufacturingPlace": "Plant 2",
+		"serialNumber": "S1",
+		"carbonFootprint": {"totalCarbonFootprint": 50, "shareOfRenewables": 95}
+	}`, string(passport.Attributes), "children override their parents; null removes an inherited field")
+}
+
+func TestRevokePassport_ParentWithPublishedChildren(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	// The batch was revised: items may still inherit from its first revision
+	firstID, batchID := uuid.New(), uuid.New()
+	mockRepo.On("GetByID", ctx, batchID).Return(&domain.Passport{ID: batchID, ManufacturerID: "mfg-1", Level: domain.LevelBatch,
+		Status: domain.StatusPublished, Version: 2, PreviousVersionID: &firstID}, nil)
+	mockRepo.On("FindVersionHistory", ctx, batchID).Return([]domain.PassportVersion{{PassportID: firstID}, {PassportID: batchID}}, nil)
+	mockRepo.On("CountPublishedChildren", ctx, []uuid.UUID{batchID, firstID}).Return(3, nil)
+
+	_, err := svc.RevokePassport(ctx, batchID, "mfg-1", "recall")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPublishPassport_RejectsItemOfRevokedParent(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockBlob := new(MockBlobStorage)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, new(MockCacheRepository), mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	// The draft points at the batch's first revision; its second one was revoked
+	firstID, revokedID, itemID := uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("GetByID", ctx, itemID).Return(&domain.Passport{ID: itemID, ManufacturerID: "mfg-1", Level: domain.LevelItem,
+		Status: domain.StatusDraft, ParentID: &firstID}, nil)
+	mockRepo.On("GetByID", ctx, firstID).Return(&domain.Passport{ID: firstID, Level: domain.LevelBatch, Status: domain.StatusSuperseded, SupersededByID: &revokedID}, nil)
+	mockRepo.On("GetByID", ctx, revokedID).Return(&domain.Passport{ID: revokedID, Level: domain.LevelBatch, Status: domain.StatusRevoked}, nil)
+
+	_, err := svc.PublishPassport(ctx, itemID, "mfg-1")
+
+	assert.ErrorIs(t, err, domain.ErrPassportRevoked)
+	mockBlob.AssertNotCalled(t, "UploadJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,301 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// publishConcurrency bounds the envelope uploads in flight during a bulk publish.
+	publishConcurrency = 8
+
+	// publishBatchSize is how many passports are recorded as published per transaction.
+	publishBatchSize = 100
+
+	// uploadCheckpointTTL is how long an uploaded but unrecorded envelope can be reused by a retry.
+	uploadCheckpointTTL = 7 * 24 * time.Hour
+)
+
+// uploadCheckpoint remembers an envelope upload until its publication is recorded, so a retry
+// does not upload the passport again. It only applies while the draft is unchanged.
+type uploadCheckpoint struct {
+	DraftUpdatedAt      time.Time `json:"draftUpdatedAt"`
+	ImmutabilityHash    string    `json:"immutabilityHash"`
+	StorageLocation     string    `json:"storageLocation"`
+	PublishedAt         time.Time `json:"publishedAt"`
+	ManufacturerDUNS    string    `json:"manufacturerDuns,omitempty"`
+	ManufacturerCountry string    `json:"manufacturerCountry,omitempty"`
+}
+
+func uploadCheckpointKey(id uuid.UUID) string {
+	return fmt.Sprintf("publish:upload:%s", id.String())
+}
+
+func (s *passportService) PublishPassports(ctx context.Context, manufacturerID string, req domain.BulkPublishRequest) (*domain.PublishReport, error) {
+	// 1. Validate the Selection
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. Resolve the Passports (explicit IDs are checked one by one while publishing)
+	ids := dedupeIDs(req.IDs)
+	if len(ids) == 0 {
+		drafts, err := s.repo.FindDrafts(ctx, manufacturerID, req.Category, req.CreatedBefore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list drafts: %w", err)
+		}
+		ids = drafts
+	}
+
+	// 3. Upload & Record in Batches
+	report := &domain.PublishReport{Passports: make([]domain.PublishResult, 0, len(ids))}
+	for start := 0; start < len(ids); start += publishBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err // Client gone or job cancelled: the recorded batches are kept
+		}
+		end := min(start+publishBatchSize, len(ids))
+
+		staged := s.stagePublish(ctx, manufacturerID, ids[start:end])
+		for _, result := range s.commitPublish(ctx, staged) {
+			report.Add(result)
+		}
+
+		if req.Progress != nil {
+			req.Progress(end, len(ids))
+		}
+	}
+
+	s.log.Info("passports published", "manufacturer", manufacturerID,
+		"published", report.Published, "skipped", report.Skipped, "failed", report.Failed)
+	return report, nil
+}
+
+// stagedPublish is a passport of the current batch: uploaded and ready to record (with the
+// revision it replaces, if any), or settled.
+type stagedPublish struct {
+	passport *domain.Passport
+	previous *domain.Passport
+	result   domain.PublishResult
+}
+
+// stagePublish uploads the envelopes of a batch, publishConcurrency at a time.
+func (s *passportService) stagePublish(ctx context.Context, manufacturerID string, ids []uuid.UUID) []stagedPublish {
+	staged := make([]stagedPublish, len(ids))
+	sem := make(chan struct{}, publishConcurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			staged[i].result.PassportID = id
+			passport, previous, outcome, err := s.uploadDraft(ctx, manufacturerID, id)
+			switch {
+			case err != nil:
+				staged[i].result.Outcome = domain.PublishFailed
+				staged[i].result.Error = err.Error()
+			case outcome == domain.PublishSkipped:
+				staged[i].result.Outcome = domain.PublishSkipped
+				staged[i].result.ImmutabilityHash = passport.ImmutabilityHash
+			default:
+				staged[i].passport = passport
+				staged[i].previous = previous
+			}
+		}()
+	}
+	wg.Wait()
+
+	return staged
+}
+
+// uploadDraft uploads the envelope of one draft, reusing the upload of an earlier attempt if any.
+// It also returns the revision the draft replaces.
+func (s *passportService) uploadDraft(ctx context.Context, manufacturerID string, id uuid.UUID) (*domain.Passport, *domain.Passport, domain.PublishOutcome, error) {
+	// 1. Fetch & Check the Passport
+	passport, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, domain.PublishFailed, errors.New("passport not found")
+		}
+		return nil, nil, domain.PublishFailed, fmt.Errorf("failed to fetch passport: %w", err)
+	}
+	if passport.ManufacturerID != manufacturerID {
+		return nil, nil, domain.PublishFailed, errors.New("passport not found") // Don't reveal other tenants' passports
+	}
+	switch passport.Status {
+	case domain.StatusPublished, domain.StatusSuperseded:
+		return passport, nil, domain.PublishSkipped, nil
+	case domain.StatusRevoked:
+		return nil, nil, domain.PublishFailed, domain.ErrPassportRevoked
+	}
+	previous, err := s.previousRevision(ctx, passport)
+	if err != nil {
+		return nil, nil, domain.PublishFailed, err
+	}
+	if err := s.checkParentLive(ctx, passport); err != nil {
+		return nil, nil, domain.PublishFailed, err
+	}
+
+	// 2. Reuse an Earlier Upload of the same Draft
+	if raw, err := s.cache.Get(ctx, uploadCheckpointKey(id)); err == nil {
+		var cp uploadCheckpoint
+		if json.Unmarshal([]byte(raw), &cp) == nil && cp.DraftUpdatedAt.Equal(passport.UpdatedAt) {
+			passport.ImmutabilityHash = cp.ImmutabilityHash
+			passport.StorageLocation = cp.StorageLocation
+			passport.PublishedAt = &cp.PublishedAt
+			passport.ManufacturerDUNS = cp.ManufacturerDUNS
+			passport.ManufacturerCountry = cp.ManufacturerCountry
+			return passport, previous, domain.PublishPublished, nil
+		}
+	}
+
+	// 3. Upload
+	draftUpdatedAt := passport.UpdatedAt
+	if err := s.uploadEnvelope(ctx, passport, time.Now().UTC()); err != nil {
+		return nil, nil, domain.PublishFailed, err
+	}
+
+	// 4. Checkpoint until the publication is recorded
+	cp, err := json.Marshal(uploadCheckpoint{
+		DraftUpdatedAt:      draftUpdatedAt,
+		ImmutabilityHash:    passport.ImmutabilityHash,
+		StorageLocation:     passport.StorageLocation,
+		PublishedAt:         *passport.PublishedAt,
+		ManufacturerDUNS:    passport.ManufacturerDUNS,
+		ManufacturerCountry: passport.ManufacturerCountry,
+	})
+	if err == nil {
+		err = s.cache.Set(ctx, uploadCheckpointKey(id), string(cp), uploadCheckpointTTL)
+	}
+	if err != nil {
+		s.log.Warn("failed to checkpoint envelope upload", "id", id, "error", err)
+	}
+	return passport, previous, domain.PublishPublished, nil
+}
+
+// commitPublish records the uploaded passports of a batch (and their events) in one transaction,
+// falling back to one passport at a time if another publisher got to some of them first.
+func (s *passportService) commitPublish(ctx context.Context, staged []stagedPublish) []domain.PublishResult {
+	var ready []*domain.Passport
+	failed := make(map[uuid.UUID]error)
+	for _, st := range staged {
+		if st.passport == nil {
+			continue
+		}
+		st.passport.Status = domain.StatusPublished
+		if err := st.passport.RecordEvent(domain.EventPassportPublished); err != nil {
+			failed[st.passport.ID] = err
+			continue
+		}
+		if err := recordSupersession(st.passport, st.previous); err != nil {
+			failed[st.passport.ID] = err
+			continue
+		}
+		ready = append(ready, st.passport)
+	}
+
+	if len(ready) > 0 {
+		err := s.repo.PublishBatch(ctx, ready)
+		switch {
+		case errors.Is(err, domain.ErrConflict):
+			for _, p := range ready {
+				if err := s.repo.PublishBatch(ctx, []*domain.Passport{p}); err != nil {
+					failed[p.ID] = err
+				}
+			}
+		case err != nil:
+			for _, p := range ready {
+				failed[p.ID] = err
+			}
+		}
+	}
+
+	results := make([]domain.PublishResult, 0, len(staged))
+	for _, st := range staged {
+		if st.passport == nil {
+			results = append(results, st.result)
+			continue
+		}
+
+		p := st.passport
+		if err, ok := failed[p.ID]; ok {
+			s.log.Error("failed to record publication", "id", p.ID, "error", err)
+			results = append(results, domain.PublishResult{
+				PassportID: p.ID,
+				Outcome:    domain.PublishFailed,
+				Error:      fmt.Sprintf("failed to record publication: %v", err),
+			})
+			continue
+		}
+
+		_ = s.cache.Delete(ctx, uploadCheckpointKey(p.ID))
+		if p.PreviousVersionID != nil {
+			s.invalidate(*p.PreviousVersionID)
+		}
+		s.invalidate(p.ID)
+		results = append(results, domain.PublishResult{
+			PassportID:       p.ID,
+			Outcome:          domain.PublishPublished,
+			ImmutabilityHash: p.ImmutabilityHash,
+		})
+	}
+	return results
+}
+
+// dedupeIDs drops repeated IDs, keeping the first occurrence.
+func dedupeIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// publishJob runs JobTypePublish jobs in cmd/api-worker. A retried job skips what an earlier
+// attempt already published.
+type publishJob struct {
+	passports ports.PassportService
+}
+
+func NewPublishJob(passports ports.PassportService) ports.JobHandler {
+	return &publishJob{passports: passports}
+}
+
+func (j *publishJob) Run(ctx context.Context, job *domain.Job, _ []byte, progress func(domain.JobProgress)) ([]byte, error) {
+	var req domain.BulkPublishRequest
+	if err := json.Unmarshal(job.Params, &req); err != nil {
+		return nil, fmt.Errorf("%w: invalid job parameters", domain.ErrInvalidInput)
+	}
+
+	req.Progress = func(done, total int) { progress(domain.JobProgress{Done: done, Total: total}) }
+	report, err := j.passports.PublishPassports(ctx, job.TenantID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(report)
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func draftPassport(manufacturerID string, status domain.PassportStatus) *domain.Passport {
+	return &domain.Passport{
+		ID:                  uuid.New(),
+		ProductCategory:     domain.CategoryBattery,
+		Status:              status,
+		ManufacturerID:      manufacturerID,
+		ManufacturerName:    "Acme Batteries GmbH",
+		ManufacturerDUNS:    "123456789",
+		ManufacturerCountry: "DE",
+		Attributes:          json.RawMessage(`{"foo":"bar"}`),
+		UpdatedAt:           time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPublishPassports_IDs(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	mockBlob := new(MockBlobStorage)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	draft := draftPassport("mfg-1", domain.StatusDraft)
+	published := draftPassport("mfg-1", domain.StatusPublished)
+	published.ImmutabilityHash = "abc123"
+	foreign := draftPassport("mfg-2", domain.StatusDraft)
+
+	// Expectations: only the own draft is uploaded and recorded
+	mockRepo.On("GetByID", ctx, draft.ID).Return(draft, nil).Once()
+	mockRepo.On("GetByID", ctx, published.ID).Return(published, nil).Once()
+	mockRepo.On("GetByID", ctx, foreign.ID).Return(foreign, nil).Once()
+	mockCache.On("Get", ctx, "publish:upload:"+draft.ID.String()).Return("", errors.New("miss")).Once()
+	mockBlob.On("UploadJSON", ctx, "passports", "passports/"+draft.ID.String()+".json", mock.Anything).Return("s3://passports/key", nil).Once()
+	mockCache.On("Set", ctx, "publish:upload:"+draft.ID.String(), mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.On("PublishBatch", ctx, mock.MatchedBy(func(batch []*domain.Passport) bool {
+		return len(batch) == 1 && batch[0].ID == draft.ID && batch[0].Status == domain.StatusPublished &&
+			batch[0].StorageLocation == "s3://passports/key" && batch[0].ImmutabilityHash != "" &&
+			len(batch[0].Events) == 1 && batch[0].Events[0].Channel == "events:passport_published"
+	})).Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	// Execute (the repeated ID is published once)
+	report, err := svc.PublishPassports(ctx, "mfg-1", domain.BulkPublishRequest{
+		IDs: []uuid.UUID{draft.ID, published.ID, foreign.ID, draft.ID},
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Published)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+	require.Len(t, report.Passports, 3)
+	assert.Equal(t, domain.PublishResult{PassportID: draft.ID, Outcome: domain.PublishPublished, ImmutabilityHash: draft.ImmutabilityHash}, report.Passports[0])
+	assert.Equal(t, domain.PublishResult{PassportID: published.ID, Outcome: domain.PublishSkipped, ImmutabilityHash: "abc123"}, report.Passports[1])
+	assert.Equal(t, domain.PublishResult{PassportID: foreign.ID, Outcome: domain.PublishFailed, Error: "passport not found"}, report.Passports[2])
+
+	mockRepo.AssertExpectations(t)
+	mockBlob.AssertExpectations(t)
+}
+
+func TestPublishPassports_RetryReusesUpload(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	mockBlob := new(MockBlobStorage)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(mockRepo, mockCache, mockBlob, nil, nil, nil, nil, "https://tapi.eu", logger)
+	ctx := context.Background()
+
+	draft := draftPassport("mfg-1", domain.StatusDraft)
+	req := domain.BulkPublishRequest{Category: domain.CategoryBattery}
+	checkpointKey := "publish:upload:" + draft.ID.String()
+
+	// First attempt: the upload succeeds, recording the status fails
+	var checkpoint string
+	mockRepo.On("FindDrafts", ctx, "mfg-1", domain.CategoryBattery, (*time.Time)(nil)).Return([]uuid.UUID{draft.ID}, nil)
+	first, second := *draft, *draft
+	mockRepo.On("GetByID", ctx, draft.ID).Return(&first, nil).Once()
+	mockCache.On("Get", ctx, checkpointKey).Return("", errors.New("miss")).Once()
+	mockBlob.On("UploadJSON", ctx, "passports", mock.Anything, mock.Anything).Return("s3://passports/key", nil).Once()
+	mockCache.On("Set", ctx, checkpointKey, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		checkpoint = args.String(2)
+	}).Return(nil).Once()
+	mockRepo.On("PublishBatch", ctx, mock.Anything).Return(errors.New("connection reset")).Once()
+
+	report, err := svc.PublishPassports(ctx, "mfg-1", req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	assert.Contains(t, report.Passports[0].Error, "failed to record publication")
+	require.NotEmpty(t, checkpoint)
+
+	// Retry: the checkpointed upload is reused
+	var recorded *domain.Passport
+	mockRepo.On("GetByID", ctx, draft.ID).Return(&second, nil).Once()
+	mockCache.On("Get", ctx, checkpointKey).Return(checkpoint, nil).Once()
+	mockRepo.On("PublishBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).([]*domain.Passport)[0]
+	}).Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	report, err = svc.PublishPassports(ctx, "mfg-1", req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Published)
+	require.NotNil(t, recorded)
+	assert.Equal(t, "s3://passports/key", recorded.StorageLocation)
+	assert.Equal(t, report.Passports[0].ImmutabilityHash, recorded.ImmutabilityHash)
+	assert.Len(t, recorded.Events, 1, "the failed attempt's event was rolled back with it")
+
+	mockBlob.AssertNumberOfCalls(t, "UploadJSON", 1)
+	mockCache.AssertCalled(t, "Delete", ctx, checkpointKey)
+}
+
+func TestPublishPassports_InvalidSelection(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc, _ := service.NewPassportService(new(MockPassportRepository), new(MockCacheRepository), new(MockBlobStorage), nil, nil, nil, nil, "https://tapi.eu", logger)
+
+	_, err := svc.PublishPassports(context.Background(), "mfg-1", domain.BulkPublishRequest{})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = svc.PublishPassports(context.Background(), "mfg-1", domain.BulkPublishRequest{
+		IDs:      []uuid.UUID{uuid.New()},
+		Category: domain.CategoryBattery,
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
@@ -0,0 +1,410 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+)
+
+type segmentKind int
+
+const (
+	segmentName       segmentKind = iota // exact object key or array index
+	segmentPattern                       // object keys matching a patternProperties regex
+	segmentAny                           // every array item, or every key not covered by a sibling rule
+	segmentDescendant                    // zero or more levels of anything (recursive schemas)
+)
+
+// pathSegment is one step of a redaction rule.
+type pathSegment struct {
+	kind    segmentKind
+	name    string
+	pattern *regexp.Regexp
+
+	// For additionalProperties: keys handled by properties/patternProperties are not "additional".
+	exceptNames    map[string]struct{}
+	exceptPatterns []*regexp.Regexp
+}
+
+func (seg pathSegment) String() string {
+	switch seg.kind {
+	case segmentPattern:
+		return "{" + seg.pattern.String() + "}"
+	case segmentAny:
+		return "*"
+	case segmentDescendant:
+		return "**"
+	default:
+		// RFC 6901 escaping
+		return strings.NewReplacer("~", "~0", "/", "~1").Replace(seg.name)
+	}
+}
+
+func (seg pathSegment) matchesKey(key string) bool {
+	switch seg.kind {
+	case segmentName:
+		return key == seg.name
+	case segmentPattern:
+		return seg.pattern.MatchString(key)
+	case segmentAny:
+		if _, ok := seg.exceptNames[key]; ok {
+			return false
+		}
+		for _, re := range seg.exceptPatterns {
+			if re.MatchString(key) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (seg pathSegment) matchesIndex(i int) bool {
+	switch seg.kind {
+	case segmentName:
+		return seg.name == strconv.Itoa(i)
+	case segmentAny:
+		return true
+	}
+	return false
+}
+
+// redactionRule removes every payload value located at its path from viewers below its level.
+type redactionRule struct {
+	path  []pathSegment
+	level domain.AccessLevel
+	rank  int
+}
+
+// Pointer renders the rule as a JSON pointer. "*" stands for any array item or additional key,
+// "{re}" for keys matching a pattern and "**" for any depth.
+func (r redactionRule) Pointer() string {
+	var b strings.Builder
+	for _, seg := range r.path {
+		b.WriteString("/")
+		b.WriteString(seg.String())
+	}
+	return b.String()
+}
+
+// parseRedactionRules walks the whole schema (properties, items, prefixItems, local $ref,
+// allOf/anyOf/oneOf, if/then/else, patternProperties, additionalProperties) and returns
+// one rule per location annotated above public. Unknown access levels are rejected.
+func parseRedactionRules(rawSchema []byte, levels *domain.AccessLevels) ([]redactionRule, error) {
+	w, err := walkSchema(rawSchema, levels)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stable order keeps logs and tests deterministic
+	sort.Slice(w.rules, func(i, j int) bool {
+		if pi, pj := w.rules[i].Pointer(), w.rules[j].Pointer(); pi != pj {
+			return pi < pj
+		}
+		return w.rules[i].rank < w.rules[j].rank
+	})
+	return w.rules, nil
+}
+
+// walkSchema collects the annotations of a raw schema.
+func walkSchema(rawSchema []byte, levels *domain.AccessLevels) (*schemaWalker, error) {
+	var root interface{}
+	if err := json.Unmarshal(rawSchema, &root); err != nil {
+		return nil, err
+	}
+
+	w := &schemaWalker{root: root, levels: levels, active: make(map[string]bool), recursive: make(map[string]bool), seen: make(map[string]bool)}
+	if err := w.walk(root, nil); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+type schemaWalker struct {
+	root      interface{}
+	levels    *domain.AccessLevels
+	rules     []redactionRule
+	fields    []searchField
+	active    map[string]bool // $refs on the current walk stack
+	recursive map[string]bool // $refs that were re-entered through themselves
+	seen      map[string]bool // rule pointers (and levels) already emitted, search fields too
+}
+
+func (w *schemaWalker) add(path []pathSegment, level domain.AccessLevel, rank int) {
+	rule := redactionRule{path: append([]pathSegment(nil), path...), level: level, rank: rank}
+	if key := rule.Pointer() + "@" + string(level); !w.seen[key] {
+		w.seen[key] = true
+		w.rules = append(w.rules, rule)
+	}
+}
+
+func (w *schemaWalker) walk(node interface{}, path []pathSegment) error {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		// Boolean schemas carry no annotations
+		return nil
+	}
+
+	// 1. The annotation itself. Descendants are still walked: a stricter ancestor
+	// removes them anyway, but viewers above it still need the descendants' own rules.
+	if access, ok := schema["access"].(string); ok {
+		rank, known := w.levels.Rank(domain.AccessLevel(access))
+		if !known {
+			return fmt.Errorf("unknown access level %q", access)
+		}
+		if rank > 0 {
+			w.add(path, domain.AccessLevel(access), rank)
+		}
+	}
+	if search, ok := schema["search"].(string); ok {
+		weight := domain.SearchWeight(search)
+		if !weight.IsValid() {
+			return fmt.Errorf("unknown search weight %q", search)
+		}
+		w.addField(path, weight)
+	}
+
+	// 2. References (same location in the instance)
+	if ref, ok := schema["$ref"].(string); ok {
+		if err := w.walkRef(ref, path); err != nil {
+			return err
+		}
+	}
+
+	// 3. Applicators at the same location
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		if subs, ok := schema[keyword].([]interface{}); ok {
+			for _, sub := range subs {
+				if err := w.walk(sub, path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, keyword := range []string{"if", "then", "else"} {
+		if sub, ok := schema[keyword]; ok {
+			if err := w.walk(sub, path); err != nil {
+				return err
+			}
+		}
+	}
+	if deps, ok := schema["dependentSchemas"].(map[string]interface{}); ok {
+		for _, sub := range deps {
+			if err := w.walk(sub, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 4. Object members
+	exceptNames := make(map[string]struct{})
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, sub := range props {
+			exceptNames[name] = struct{}{}
+			if err := w.walk(sub, appendSegment(path, pathSegment{kind: segmentName, name: name})); err != nil {
+				return err
+			}
+		}
+	}
+	var exceptPatterns []*regexp.Regexp
+	if patterns, ok := schema["patternProperties"].(map[string]interface{}); ok {
+		for expr, sub := range patterns {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("invalid patternProperties %q: %w", expr, err)
+			}
+			exceptPatterns = append(exceptPatterns, re)
+			if err := w.walk(sub, appendSegment(path, pathSegment{kind: segmentPattern, pattern: re})); err != nil {
+				return err
+			}
+		}
+	}
+	if sub, ok := schema["additionalProperties"]; ok {
+		seg := pathSegment{kind: segmentAny, exceptNames: exceptNames, exceptPatterns: exceptPatterns}
+		if err := w.walk(sub, appendSegment(path, seg)); err != nil {
+			return err
+		}
+	}
+
+	// 5. Array items
+	if prefix, ok := schema["prefixItems"].([]interface{}); ok {
+		for i, sub := range prefix {
+			if err := w.walk(sub, appendSegment(path, pathSegment{kind: segmentName, name: strconv.Itoa(i)})); err != nil {
+				return err
+			}
+		}
+	}
+	if sub, ok := schema["items"]; ok {
+		if err := w.walk(sub, appendSegment(path, pathSegment{kind: segmentAny})); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *schemaWalker) walkRef(ref string, path []pathSegment) error {
+	// Only document-local references can be resolved; anything else fails closed.
+	if !strings.HasPrefix(ref, "#") {
+		return fmt.Errorf("unsupported $ref %q: only local references are allowed", ref)
+	}
+
+	if w.active[ref] {
+		// Recursive schema: the rules found under this ref must also apply at any depth below it.
+		w.recursive[ref] = true
+		return nil
+	}
+
+	target, err := resolvePointer(w.root, strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return fmt.Errorf("unresolvable $ref %q: %w", ref, err)
+	}
+
+	w.active[ref] = true
+	first, firstField := len(w.rules), len(w.fields)
+	err = w.walk(target, path)
+	delete(w.active, ref)
+	if err != nil {
+		return err
+	}
+
+	if w.recursive[ref] {
+		delete(w.recursive, ref)
+		for _, rule := range w.rules[first:] {
+			rel := rule.path[len(path):]
+			recursivePath := appendSegment(path, pathSegment{kind: segmentDescendant})
+			w.add(append(recursivePath, rel...), rule.level, rule.rank)
+		}
+		for _, field := range w.fields[firstField:] {
+			rel := field.path[len(path):]
+			recursivePath := appendSegment(path, pathSegment{kind: segmentDescendant})
+			w.addField(append(recursivePath, rel...), field.weight)
+		}
+	}
+	return nil
+}
+
+// resolvePointer follows an RFC 6901 JSON pointer ("" is the document itself).
+func resolvePointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("anchors are not supported")
+	}
+
+	current := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("no member %q", token)
+			}
+			current = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("no item %q", token)
+			}
+			current = v[i]
+		default:
+			return nil, fmt.Errorf("cannot descend into %q", token)
+		}
+	}
+	return current, nil
+}
+
+func appendSegment(path []pathSegment, seg pathSegment) []pathSegment {
+	out := make([]pathSegment, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, seg)
+}
+
+// redact removes every value the viewer's rank may not see and returns the (possibly replaced)
+// document. A rule matching the document root removes everything.
+func redact(doc interface{}, rules []redactionRule, viewerRank int) interface{} {
+	for _, rule := range rules {
+		if rule.rank <= viewerRank {
+			continue
+		}
+		if len(rule.path) == 0 {
+			return map[string]interface{}{}
+		}
+		doc = redactPath(doc, rule.path)
+	}
+	return doc
+}
+
+func redactPath(value interface{}, path []pathSegment) interface{} {
+	seg, rest := path[0], path[1:]
+
+	if seg.kind == segmentDescendant {
+		// Zero levels: apply the rest here. More levels: recurse into every child with the same path.
+		if len(rest) > 0 {
+			value = redactPath(value, rest)
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				v[key] = redactPath(child, path)
+			}
+		case []interface{}:
+			for i, child := range v {
+				v[i] = redactPath(child, path)
+			}
+		}
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if !seg.matchesKey(key) {
+				continue
+			}
+			if len(rest) == 0 {
+				delete(v, key)
+			} else {
+				v[key] = redactPath(child, rest)
+			}
+		}
+		return v
+
+	case []interface{}:
+		if len(rest) == 0 && seg.kind == segmentAny {
+			// Every item is restricted
+			return []interface{}{}
+		}
+		for i, child := range v {
+			if !seg.matchesIndex(i) {
+				continue
+			}
+			if len(rest) == 0 {
+				// Keep the positions of the other items
+				v[i] = nil
+			} else {
+				v[i] = redactPath(child, rest)
+			}
+		}
+		return v
+	}
+
+	return value
+}
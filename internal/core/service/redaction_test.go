@@ -0,0 +1,352 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// nestedSchema hides data at every kind of location the walker understands.
+const nestedSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"$defs": {
+		"supplier": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"auditReport": {"type": "string", "access": "restricted"}
+			}
+		},
+		"component": {
+			"type": "object",
+			"properties": {
+				"label": {"type": "string"},
+				"costPrice": {"type": "number", "access": "restricted"},
+				"children": {"type": "array", "items": {"$ref": "#/$defs/component"}}
+			}
+		}
+	},
+	"properties": {
+		"model": {"type": "string", "access": "public"},
+		"carbonFootprint": {
+			"type": "object",
+			"access": "public",
+			"properties": {
+				"total": {"type": "number"},
+				"declarationUrl": {"type": "string", "access": "restricted"}
+			}
+		},
+		"materialComposition": {
+			"type": "array",
+			"access": "public",
+			"items": {
+				"type": "object",
+				"properties": {
+					"material": {"type": "string"},
+					"supplier": {"$ref": "#/$defs/supplier"}
+				}
+			}
+		},
+		"certificates": {
+			"type": "array",
+			"prefixItems": [
+				{"type": "string"},
+				{"type": "string", "access": "restricted"}
+			]
+		},
+		"labels": {
+			"type": "object",
+			"patternProperties": {
+				"^internal-": {"type": "string", "access": "restricted"}
+			},
+			"additionalProperties": {"type": "string"}
+		},
+		"extensions": {
+			"type": "object",
+			"properties": {"publicNote": {"type": "string"}},
+			"additionalProperties": {"access": "restricted"}
+		},
+		"recycling": {
+			"allOf": [
+				{"properties": {"instructions": {"type": "string", "access": "restricted"}}},
+				{"properties": {"facility": {"type": "string"}}}
+			],
+			"oneOf": [
+				{"properties": {"hotline": {"type": "string", "access": "restricted"}}}
+			]
+		},
+		"bom": {"$ref": "#/$defs/component"},
+		"secretBlock": {
+			"type": "object",
+			"access": "restricted",
+			"properties": {"anything": {"type": "string"}}
+		}
+	}
+}`
+
+func TestParseRedactionRules_Nested(t *testing.T) {
+	rules, err := parseRedactionRules([]byte(nestedSchema), domain.MustDefaultAccessLevels())
+	require.NoError(t, err)
+
+	var pointers []string
+	for _, r := range rules {
+		pointers = append(pointers, r.Pointer())
+	}
+
+	assert.ElementsMatch(t, []string{
+		"/bom/**/costPrice",
+		"/bom/costPrice",
+		"/carbonFootprint/declarationUrl",
+		"/certificates/1",
+		"/extensions/*",
+		"/labels/{^internal-}",
+		"/materialComposition/*/supplier/auditReport",
+		"/recycling/hotline",
+		"/recycling/instructions",
+		"/secretBlock",
+	}, pointers)
+}
+
+func TestParseRedactionRules_RejectsRemoteRefs(t *testing.T) {
+	_, err := parseRedactionRules([]byte(`{"properties": {"a": {"$ref": "https://example.com/other.json"}}}`), domain.MustDefaultAccessLevels())
+	assert.Error(t, err)
+
+	_, err = parseRedactionRules([]byte(`{"properties": {"a": {"$ref": "#/$defs/missing"}}}`), domain.MustDefaultAccessLevels())
+	assert.Error(t, err)
+}
+
+func TestRedact_NoRestrictedLeafLeaks(t *testing.T) {
+	rules, err := parseRedactionRules([]byte(nestedSchema), domain.MustDefaultAccessLevels())
+	require.NoError(t, err)
+
+	// Every restricted value contains SECRET, every public value contains PUBLIC.
+	payload := `{
+		"model": "PUBLIC-model",
+		"carbonFootprint": {"total": 42, "declarationUrl": "SECRET-url"},
+		"materialComposition": [
+			{"material": "PUBLIC-cobalt", "supplier": {"name": "PUBLIC-supplier", "auditReport": "SECRET-audit-1"}},
+			{"material": "PUBLIC-lithium", "supplier": {"name": "PUBLIC-supplier-2", "auditReport": "SECRET-audit-2"}}
+		],
+		"certificates": ["PUBLIC-cert", "SECRET-cert"],
+		"labels": {"internal-code": "SECRET-label", "color": "PUBLIC-red"},
+		"extensions": {"publicNote": "PUBLIC-note", "vendorX": {"deep": "SECRET-ext"}},
+		"recycling": {"instructions": "SECRET-instructions", "facility": "PUBLIC-facility", "hotline": "SECRET-hotline"},
+		"bom": {
+			"label": "PUBLIC-root",
+			"costPrice": "SECRET-cost-0",
+			"children": [
+				{"label": "PUBLIC-child", "costPrice": "SECRET-cost-1", "children": [
+					{"label": "PUBLIC-grandchild", "costPrice": "SECRET-cost-2"}
+				]}
+			]
+		},
+		"secretBlock": {"anything": "SECRET-block"}
+	}`
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(payload), &doc))
+
+	out, err := json.Marshal(redact(doc, rules, 0))
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(out), "SECRET")
+	for _, public := range []string{"PUBLIC-model", "PUBLIC-cobalt", "PUBLIC-supplier-2", "PUBLIC-cert", "PUBLIC-red", "PUBLIC-note", "PUBLIC-facility", "PUBLIC-grandchild"} {
+		assert.Contains(t, string(out), public)
+	}
+	assert.Equal(t, strings.Count(payload, "PUBLIC"), strings.Count(string(out), "PUBLIC"), "public data must be preserved")
+}
+
+func TestRedact_RestrictedArrayItems(t *testing.T) {
+	rules, err := parseRedactionRules([]byte(`{
+		"properties": {
+			"partNumbers": {"type": "array", "items": {"type": "string", "access": "restricted"}}
+		}
+	}`), domain.MustDefaultAccessLevels())
+	require.NoError(t, err)
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"partNumbers": ["SECRET-1", "SECRET-2"]}`), &doc))
+
+	out, _ := json.Marshal(redact(doc, rules, 0))
+	assert.JSONEq(t, `{"partNumbers": []}`, string(out))
+}
+
+func TestGetPassport_Filtering_NestedFields(t *testing.T) {
+	repo := new(MockRepo)
+	cache := new(MockCache)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", nil)
+	require.NoError(t, err)
+
+	// Replace the bootstrap battery schema with one that restricts nested fields
+	compiled, err := compileSchema(domain.CategoryBattery, BootstrapSchemaVersion, []byte(nestedSchema), domain.MustDefaultAccessLevels())
+	require.NoError(t, err)
+	svc.(*passportService).schemas.bootstrap[domain.CategoryBattery] = compiled
+
+	id := uuid.New()
+	repo.On("GetByID", mock.Anything, id).Return(&domain.Passport{
+		ID:              id,
+		ProductCategory: domain.CategoryBattery,
+		ManufacturerID:  "mfg-1",
+		Attributes:      json.RawMessage(`{"carbonFootprint": {"total": 12.50, "declarationUrl": "SECRET"}, "materialComposition": [{"material": "Cobalt", "supplier": {"auditReport": "SECRET"}}]}`),
+	}, nil)
+	cache.On("Get", mock.Anything, mock.Anything).Return("", assert.AnError)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	p, err := svc.GetPassport(context.Background(), id)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(p.Attributes), "SECRET")
+	assert.JSONEq(t, `{"carbonFootprint": {"total": 12.50}, "materialComposition": [{"material": "Cobalt", "supplier": {}}]}`, string(p.Attributes))
+}
+
+func TestGetPassport_Filtering_FailsClosedOnMalformedAttributes(t *testing.T) {
+	repo := new(MockRepo)
+	cache := new(MockCache)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	id := uuid.New()
+	repo.On("GetByID", mock.Anything, id).Return(&domain.Passport{
+		ID:              id,
+		ProductCategory: domain.CategoryBattery,
+		Attributes:      json.RawMessage(`{"disassemblyInstructions": "SECRET"`),
+	}, nil)
+	cache.On("Get", mock.Anything, mock.Anything).Return("", assert.AnError)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	p, err := svc.GetPassport(context.Background(), id)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(p.Attributes))
+}
+
+// tieredSchema uses the Annex XIII vocabulary, including the legacy "restricted" alias.
+const tieredSchema = `{
+	"type": "object",
+	"properties": {
+		"model": {"type": "string", "access": "public"},
+		"disassembly": {"type": "string", "access": "restricted"},
+		"testReports": {
+			"type": "object",
+			"access": "notified_body",
+			"properties": {
+				"summary": {"type": "string"},
+				"rawData": {"type": "string", "access": "commission"}
+			}
+		},
+		"recycler": {
+			"type": "object",
+			"access": "legitimate_interest",
+			"properties": {
+				"dismantling": {"type": "string"},
+				"supplierContracts": {"type": "string", "access": "commission"}
+			}
+		}
+	}
+}`
+
+func TestRedact_AccessTiers(t *testing.T) {
+	levels := domain.MustDefaultAccessLevels()
+	rules, err := parseRedactionRules([]byte(tieredSchema), levels)
+	require.NoError(t, err)
+
+	payload := `{
+		"model": "M1",
+		"disassembly": "D",
+		"testReports": {"summary": "S", "rawData": "R"},
+		"recycler": {"dismantling": "X", "supplierContracts": "C"}
+	}`
+
+	tests := []struct {
+		level domain.AccessLevel
+		want  string
+	}{
+		{domain.AccessPublic, `{"model": "M1"}`},
+		{domain.AccessLegitimateInterest, `{"model": "M1", "disassembly": "D", "recycler": {"dismantling": "X"}}`},
+		{domain.AccessRestricted, `{"model": "M1", "disassembly": "D", "recycler": {"dismantling": "X"}}`},
+		{domain.AccessNotifiedBody, `{"model": "M1", "disassembly": "D", "testReports": {"summary": "S"}, "recycler": {"dismantling": "X"}}`},
+		{domain.AccessCommission, payload},
+		{"unknown-level", `{"model": "M1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			var doc interface{}
+			require.NoError(t, json.Unmarshal([]byte(payload), &doc))
+
+			out, err := json.Marshal(redact(doc, rules, levels.ViewerRank(tt.level)))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(out))
+		})
+	}
+}
+
+func TestParseRedactionRules_RejectsUnknownLevel(t *testing.T) {
+	_, err := parseRedactionRules([]byte(`{"properties": {"a": {"access": "top_secret"}}}`), domain.MustDefaultAccessLevels())
+	assert.Error(t, err)
+}
+
+func TestGetPassport_FiltersByViewerAccessLevel(t *testing.T) {
+	repo := new(MockRepo)
+	cache := new(MockCache)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", nil)
+	require.NoError(t, err)
+
+	compiled, err := compileSchema(domain.CategoryBattery, BootstrapSchemaVersion, []byte(tieredSchema), domain.MustDefaultAccessLevels())
+	require.NoError(t, err)
+	svc.(*passportService).schemas.bootstrap[domain.CategoryBattery] = compiled
+
+	attributes := `{"model": "M1", "disassembly": "D", "testReports": {"summary": "S", "rawData": "R"}}`
+	cache.On("Get", mock.Anything, mock.Anything).Return("", assert.AnError)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	view := func(tenant string, level domain.AccessLevel) map[string]interface{} {
+		id := uuid.New()
+		repo.On("GetByID", mock.Anything, id).Return(&domain.Passport{
+			ID:              id,
+			ProductCategory: domain.CategoryBattery,
+			ManufacturerID:  "mfg-1",
+			Attributes:      json.RawMessage(attributes),
+		}, nil)
+
+		ctx := context.WithValue(context.Background(), domain.ViewContextKey, domain.ViewContextRestricted)
+		ctx = context.WithValue(ctx, domain.ViewerTenantIDKey, tenant)
+		ctx = context.WithValue(ctx, domain.ViewerAccessLevelKey, string(level))
+
+		p, err := svc.GetPassport(ctx, id)
+		require.NoError(t, err)
+		var attrs map[string]interface{}
+		require.NoError(t, json.Unmarshal(p.Attributes, &attrs))
+		return attrs
+	}
+
+	// A foreign tenant without a level only gets the public tier
+	assert.Equal(t, map[string]interface{}{"model": "M1"}, view("other", ""))
+
+	// A notified body sees its tier but not the Commission's
+	nb := view("auditor", domain.AccessNotifiedBody)
+	assert.Equal(t, "D", nb["disassembly"])
+	assert.Equal(t, map[string]interface{}{"summary": "S"}, nb["testReports"])
+
+	// The owner sees everything regardless of the level claimed
+	owner := view("mfg-1", domain.AccessPublic)
+	assert.Equal(t, "R", owner["testReports"].(map[string]interface{})["rawData"])
+}
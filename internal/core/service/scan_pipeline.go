@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+// ScanRecorderConfig tunes a ScanRecorder. Zero values take the defaults.
+type ScanRecorderConfig struct {
+	Buffer        int           // Scans waiting to be written; more are dropped (default 10000)
+	BatchSize     int           // Scans written per round trip (default 500)
+	FlushInterval time.Duration // Longest wait before a partial batch is written (default 1s)
+}
+
+// ScanRecorder buffers the resolver's scans in memory and appends them to the scan log in
+// batches. Analytics are best effort: scans are dropped when the buffer is full, the log is
+// unavailable, or the process dies before a flush.
+type ScanRecorder struct {
+	scans   ports.ScanRepository
+	cfg     ScanRecorderConfig
+	log     *slog.Logger
+	queue   chan domain.ScanEvent
+	dropped atomic.Int64
+}
+
+// Ensure we implement the interface
+var _ ports.ScanRecorder = (*ScanRecorder)(nil)
+
+func NewScanRecorder(scans ports.ScanRepository, cfg ScanRecorderConfig, log *slog.Logger) *ScanRecorder {
+	if cfg.Buffer < 1 {
+		cfg.Buffer = 10000
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	return &ScanRecorder{scans: scans, cfg: cfg, log: log, queue: make(chan domain.ScanEvent, cfg.Buffer)}
+}
+
+func (r *ScanRecorder) Record(scan domain.ScanEvent) {
+	select {
+	case r.queue <- scan:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Run writes the recorded scans until ctx is cancelled, then writes what is left.
+func (r *ScanRecorder) Run(ctx context.Context) {
+	batch := make([]domain.ScanEvent, 0, r.cfg.BatchSize)
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case scan := <-r.queue:
+			if batch = append(batch, scan); len(batch) >= r.cfg.BatchSize {
+				batch = r.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(ctx, batch)
+		case <-ctx.Done():
+			// Drain without waiting for more
+			for drained := false; !drained; {
+				select {
+				case scan := <-r.queue:
+					batch = append(batch, scan)
+				default:
+					drained = true
+				}
+			}
+			r.flush(context.WithoutCancel(ctx), batch)
+			return
+		}
+	}
+}
+
+// flush writes a batch and returns it emptied. A failed batch is dropped rather than retried:
+// the buffer keeps filling meanwhile.
+func (r *ScanRecorder) flush(ctx context.Context, batch []domain.ScanEvent) []domain.ScanEvent {
+	if dropped := r.dropped.Swap(0); dropped > 0 {
+		r.log.Warn("scan buffer full, scans dropped", "dropped", dropped)
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := r.scans.SaveScans(ctx, batch); err != nil {
+		r.log.Error("failed to save scans", "scans", len(batch), "error", err)
+	}
+	return batch[:0]
+}
+
+// RollupConfig tunes a ScanRollup. Zero values take the defaults.
+type RollupConfig struct {
+	BatchSize    int           // Scans rolled up per round (default 5000)
+	PollInterval time.Duration // Wait when the scan log is empty (default 10s)
+}
+
+// ScanRollup moves the scan log into the daily counts read by the analytics API. Rollups running
+// in parallel take disjoint batches.
+type ScanRollup struct {
+	scans ports.ScanRepository
+	cfg   RollupConfig
+	log   *slog.Logger
+}
+
+func NewScanRollup(scans ports.ScanRepository, cfg RollupConfig, log *slog.Logger) *ScanRollup {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 5000
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+	return &ScanRollup{scans: scans, cfg: cfg, log: log}
+}
+
+// Run rolls up scans until ctx is cancelled.
+func (r *ScanRollup) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		rolled, err := r.scans.RollupScans(ctx, r.cfg.BatchSize)
+		if err != nil && ctx.Err() == nil {
+			r.log.Error("failed to roll up scans", "error", err)
+		}
+		if rolled == r.cfg.BatchSize {
+			continue // More are waiting
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
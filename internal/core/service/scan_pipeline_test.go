@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockScanRepository struct {
+	mock.Mock
+}
+
+func (m *MockScanRepository) SaveScans(ctx context.Context, scans []domain.ScanEvent) error {
+	// The recorder reuses its batch: keep a copy
+	return m.Called(ctx, append([]domain.ScanEvent(nil), scans...)).Error(0)
+}
+
+func (m *MockScanRepository) RollupScans(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockScanRepository) FindScanCounts(ctx context.Context, manufacturerID string, query domain.ScanQuery) ([]domain.ScanCount, error) {
+	args := m.Called(ctx, manufacturerID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ScanCount), args.Error(1)
+}
+
+func TestScanRecorder_BatchesAndDrops(t *testing.T) {
+	repo := new(MockScanRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	recorder := service.NewScanRecorder(repo, service.ScanRecorderConfig{Buffer: 3, BatchSize: 2, FlushInterval: time.Hour}, logger)
+
+	scans := make([]domain.ScanEvent, 4)
+	for i := range scans {
+		scans[i] = domain.ScanEvent{PassportID: uuid.New(), Source: domain.ScanSourcePassport}
+	}
+
+	// Expectations: the buffer holds three scans (the fourth is dropped), written as a full
+	// batch and, on shutdown, the remainder
+	flushed := make(chan struct{})
+	repo.On("SaveScans", mock.Anything, scans[:2]).Return(nil).Run(func(mock.Arguments) { close(flushed) }).Once()
+	repo.On("SaveScans", mock.Anything, scans[2:3]).Return(nil).Once()
+
+	for _, scan := range scans {
+		recorder.Record(scan)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		recorder.Run(ctx)
+	}()
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("full batch not written")
+	}
+	cancel()
+	<-done
+
+	repo.AssertExpectations(t)
+}
+
+func TestAnalyticsService_GetScanReport(t *testing.T) {
+	repo := new(MockScanRepository)
+	svc := service.NewAnalyticsService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	// Defaults: the last 30 days, today included, per day
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	repo.On("FindScanCounts", ctx, "mfg-1", domain.ScanQuery{From: tomorrow.AddDate(0, 0, -30), To: tomorrow, Bucket: domain.ScanBucketDay}).
+		Return(nil, nil).Once()
+
+	report, err := svc.GetScanReport(ctx, "mfg-1", domain.ScanQuery{})
+
+	require.NoError(t, err)
+	assert.Equal(t, tomorrow, report.To)
+	assert.Equal(t, []domain.ScanCount{}, report.Counts)
+
+	// Invalid queries never reach the repository
+	_, err = svc.GetScanReport(ctx, "mfg-1", domain.ScanQuery{Bucket: "hour"})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	repo.AssertExpectations(t)
+}
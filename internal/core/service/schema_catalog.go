@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	_ "embed"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Embed the schemas directly into the Go binary
+//
+//go:embed schemas/payloads/battery.json
+var batterySchemaRaw string
+
+//go:embed schemas/payloads/textile.json
+var textileSchemaRaw string
+
+//go:embed schemas/payloads/electronics.json
+var electronicsSchemaRaw string
+
+// BootstrapSchemaVersion is the version of the schemas embedded in the binary.
+// Passports created before the registry existed (empty schemaVersion) were validated against it.
+const BootstrapSchemaVersion = "1.0.0"
+
+// bootstrapSchemas are the defaults used until a registry version is activated.
+var bootstrapSchemas = map[domain.ProductCategory]string{
+	domain.CategoryBattery:    batterySchemaRaw,
+	domain.CategoryTextile:    textileSchemaRaw,
+	domain.CategoryElectronic: electronicsSchemaRaw,
+}
+
+// compiledSchema is a validated schema version plus the access rules and search fields parsed from it.
+type compiledSchema struct {
+	version      string
+	schema       *jsonschema.Schema
+	redactions   []redactionRule
+	searchFields []searchField
+}
+
+// schemaCatalog resolves the schema version to validate or filter a passport with.
+// Compiled versions are cached forever: a stored version is immutable.
+type schemaCatalog struct {
+	registry  ports.SchemaRegistry
+	levels    *domain.AccessLevels
+	bootstrap map[domain.ProductCategory]*compiledSchema
+
+	mu       sync.RWMutex
+	compiled map[string]*compiledSchema
+}
+
+func newSchemaCatalog(registry ports.SchemaRegistry, levels *domain.AccessLevels) (*schemaCatalog, error) {
+	c := &schemaCatalog{
+		registry:  registry,
+		levels:    levels,
+		bootstrap: make(map[domain.ProductCategory]*compiledSchema),
+		compiled:  make(map[string]*compiledSchema),
+	}
+
+	// Register and Compile the embedded Schemas
+	for category, raw := range bootstrapSchemas {
+		cs, err := compileSchema(category, BootstrapSchemaVersion, []byte(raw), levels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s schema: %w", category, err)
+		}
+		c.bootstrap[category] = cs
+	}
+
+	return c, nil
+}
+
+// active returns the schema new passports of this category must be validated against.
+func (c *schemaCatalog) active(ctx context.Context, category domain.ProductCategory) (*compiledSchema, error) {
+	bootstrap, known := c.bootstrap[category]
+	if !known {
+		return nil, fmt.Errorf("%w: unsupported product category %s", domain.ErrInvalidInput, category)
+	}
+	if c.registry == nil {
+		return bootstrap, nil
+	}
+
+	stored, err := c.registry.GetActive(ctx, category)
+	if errors.Is(err, domain.ErrNotFound) {
+		return bootstrap, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve active schema: %w", err)
+	}
+	return c.load(stored)
+}
+
+// version returns a specific schema version (empty means the bootstrap version).
+func (c *schemaCatalog) version(ctx context.Context, category domain.ProductCategory, version string) (*compiledSchema, error) {
+	bootstrap, known := c.bootstrap[category]
+	if !known {
+		return nil, fmt.Errorf("%w: unsupported product category %s", domain.ErrInvalidInput, category)
+	}
+	if version == "" || version == BootstrapSchemaVersion {
+		return bootstrap, nil
+	}
+
+	if cs, ok := c.cached(category, version); ok {
+		return cs, nil
+	}
+	if c.registry == nil {
+		return nil, fmt.Errorf("schema %s@%s: %w", category, version, domain.ErrNotFound)
+	}
+
+	stored, err := c.registry.Get(ctx, category, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema %s@%s: %w", category, version, err)
+	}
+	return c.load(stored)
+}
+
+func (c *schemaCatalog) cached(category domain.ProductCategory, version string) (*compiledSchema, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cs, ok := c.compiled[cacheKeyFor(category, version)]
+	return cs, ok
+}
+
+func (c *schemaCatalog) load(stored *domain.CategorySchema) (*compiledSchema, error) {
+	if cs, ok := c.cached(stored.Category, stored.Version); ok {
+		return cs, nil
+	}
+
+	cs, err := compileSchema(stored.Category, stored.Version, stored.Schema, c.levels)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.compiled[cacheKeyFor(stored.Category, stored.Version)] = cs
+	c.mu.Unlock()
+	return cs, nil
+}
+
+func cacheKeyFor(category domain.ProductCategory, version string) string {
+	return string(category) + "@" + version
+}
+
+// compileSchema compiles a raw JSON Schema (Draft 2020-12) and parses its access annotations
+// against the access vocabulary, and its search annotations.
+func compileSchema(category domain.ProductCategory, version string, raw []byte, levels *domain.AccessLevels) (*compiledSchema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+
+	url := fmt.Sprintf("%s/%s.json", category, version)
+	if err := compiler.AddResource(url, bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("failed to add schema: %w", err)
+	}
+
+	schema, err := compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema: %w", err)
+	}
+
+	redactions, err := parseRedactionRules(raw, levels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse access annotations: %w", err)
+	}
+
+	searchFields, err := parseSearchFields(raw, levels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse search annotations: %w", err)
+	}
+
+	return &compiledSchema{version: version, schema: schema, redactions: redactions, searchFields: searchFields}, nil
+}
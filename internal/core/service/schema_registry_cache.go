@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+// DefaultActiveSchemaTTL bounds how long a process keeps using an active version after another
+// process activated a different one.
+const DefaultActiveSchemaTTL = 30 * time.Second
+
+// cachedSchemaRegistry remembers the active version of each category (or that none is active),
+// since every passport created or imported resolves it. Activations through it clear the entry
+// at once; those made by other processes are seen once it expires.
+type cachedSchemaRegistry struct {
+	ports.SchemaRegistry
+	ttl time.Duration
+
+	mu     sync.Mutex
+	active map[domain.ProductCategory]activeSchemaEntry
+}
+
+type activeSchemaEntry struct {
+	schema  *domain.CategorySchema // nil: the bootstrap version is active
+	expires time.Time
+}
+
+// Ensure interface implementation
+var _ ports.SchemaRegistry = (*cachedSchemaRegistry)(nil)
+
+// NewCachedSchemaRegistry wraps a registry with an active version cache. A zero TTL means
+// DefaultActiveSchemaTTL.
+func NewCachedSchemaRegistry(registry ports.SchemaRegistry, ttl time.Duration) ports.SchemaRegistry {
+	if ttl <= 0 {
+		ttl = DefaultActiveSchemaTTL
+	}
+	return &cachedSchemaRegistry{
+		SchemaRegistry: registry,
+		ttl:            ttl,
+		active:         make(map[domain.ProductCategory]activeSchemaEntry),
+	}
+}
+
+func (r *cachedSchemaRegistry) GetActive(ctx context.Context, category domain.ProductCategory) (*domain.CategorySchema, error) {
+	r.mu.Lock()
+	entry, ok := r.active[category]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		if entry.schema == nil {
+			return nil, domain.ErrNotFound
+		}
+		return entry.schema, nil
+	}
+
+	schema, err := r.SchemaRegistry.GetActive(ctx, category)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.active[category] = activeSchemaEntry{schema: schema, expires: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+	return schema, err
+}
+
+func (r *cachedSchemaRegistry) Activate(ctx context.Context, category domain.ProductCategory, version string) error {
+	defer r.forget(category)
+	return r.SchemaRegistry.Activate(ctx, category, version)
+}
+
+func (r *cachedSchemaRegistry) Deactivate(ctx context.Context, category domain.ProductCategory) error {
+	defer r.forget(category)
+	return r.SchemaRegistry.Deactivate(ctx, category)
+}
+
+func (r *cachedSchemaRegistry) forget(category domain.ProductCategory) {
+	r.mu.Lock()
+	delete(r.active, category)
+	r.mu.Unlock()
+}
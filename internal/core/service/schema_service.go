@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+type schemaService struct {
+	registry ports.SchemaRegistry
+	levels   *domain.AccessLevels
+	log      *slog.Logger
+}
+
+// Ensure interface implementation
+var _ ports.SchemaService = (*schemaService)(nil)
+
+// NewSchemaService wires the schema administration use-cases. A nil access vocabulary
+// means domain.DefaultAccessLevels.
+func NewSchemaService(registry ports.SchemaRegistry, accessLevels *domain.AccessLevels, log *slog.Logger) ports.SchemaService {
+	if accessLevels == nil {
+		accessLevels = domain.MustDefaultAccessLevels()
+	}
+	return &schemaService{registry: registry, levels: accessLevels, log: log}
+}
+
+func (s *schemaService) UploadSchema(ctx context.Context, category domain.ProductCategory, version string, schema []byte) (*domain.CategorySchema, error) {
+	// 1. Check Category & Version format
+	if _, known := bootstrapSchemas[category]; !known {
+		return nil, fmt.Errorf("%w: unsupported product category %s", domain.ErrInvalidInput, category)
+	}
+	if !domain.IsValidSchemaVersion(version) {
+		return nil, fmt.Errorf("%w: version must be MAJOR.MINOR.PATCH", domain.ErrInvalidInput)
+	}
+	if !json.Valid(schema) {
+		return nil, fmt.Errorf("%w: invalid JSON", domain.ErrInvalidInput)
+	}
+
+	// 2. Versions only move forward (the bootstrap version counts as the first one)
+	existing, err := s.registry.List(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema versions: %w", err)
+	}
+	latest := BootstrapSchemaVersion
+	for _, v := range existing {
+		if domain.CompareSchemaVersions(v.Version, latest) > 0 {
+			latest = v.Version
+		}
+	}
+	if domain.CompareSchemaVersions(version, latest) <= 0 {
+		return nil, fmt.Errorf("%w: version %s must be greater than %s", domain.ErrConflict, version, latest)
+	}
+
+	// 3. Make sure it compiles before anyone can activate it
+	if _, err := compileSchema(category, version, schema, s.levels); err != nil {
+		s.log.Warn("rejected invalid schema upload", "category", category, "version", version, "error", err)
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	// 4. Save (inactive)
+	stored := &domain.CategorySchema{
+		Category:  category,
+		Version:   version,
+		Schema:    json.RawMessage(schema),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.registry.Save(ctx, stored); err != nil {
+		return nil, fmt.Errorf("failed to save schema: %w", err)
+	}
+
+	s.log.Info("schema version uploaded", "category", category, "version", version)
+	return stored, nil
+}
+
+func (s *schemaService) ActivateSchema(ctx context.Context, category domain.ProductCategory, version string) (*domain.CategorySchema, error) {
+	raw, known := bootstrapSchemas[category]
+	if !known {
+		return nil, fmt.Errorf("%w: unsupported product category %s", domain.ErrInvalidInput, category)
+	}
+
+	// The built-in version is active whenever no registry version is: rolling back to it
+	// deactivates them all
+	if version == BootstrapSchemaVersion {
+		if err := s.registry.Deactivate(ctx, category); err != nil {
+			return nil, fmt.Errorf("failed to activate schema: %w", err)
+		}
+		s.log.Info("schema version activated", "category", category, "version", version)
+		return &domain.CategorySchema{Category: category, Version: version, Schema: json.RawMessage(raw), Active: true, BuiltIn: true}, nil
+	}
+
+	if err := s.registry.Activate(ctx, category, version); err != nil {
+		return nil, fmt.Errorf("failed to activate schema: %w", err)
+	}
+
+	s.log.Info("schema version activated", "category", category, "version", version)
+	return s.registry.Get(ctx, category, version)
+}
+
+func (s *schemaService) ListSchemas(ctx context.Context, category domain.ProductCategory) ([]*domain.CategorySchema, error) {
+	raw, known := bootstrapSchemas[category]
+	if !known {
+		return nil, fmt.Errorf("%w: unsupported product category %s", domain.ErrInvalidInput, category)
+	}
+
+	stored, err := s.registry.List(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema versions: %w", err)
+	}
+
+	// The embedded schema is active until a registry version is activated
+	anyActive := false
+	for _, v := range stored {
+		anyActive = anyActive || v.Active
+	}
+
+	return append(stored, &domain.CategorySchema{
+		Category: category,
+		Version:  BootstrapSchemaVersion,
+		Schema:   json.RawMessage(raw),
+		Active:   !anyActive,
+		BuiltIn:  true,
+	}), nil
+}
+
+func (s *schemaService) GetSchema(ctx context.Context, category domain.ProductCategory, version string) (*domain.CategorySchema, error) {
+	raw, known := bootstrapSchemas[category]
+	if !known {
+		return nil, fmt.Errorf("%w: unsupported product category %s", domain.ErrInvalidInput, category)
+	}
+	if version == BootstrapSchemaVersion {
+		_, err := s.registry.GetActive(ctx, category)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("failed to resolve active schema: %w", err)
+		}
+		return &domain.CategorySchema{Category: category, Version: version, Schema: json.RawMessage(raw), Active: err != nil, BuiltIn: true}, nil
+	}
+	return s.registry.Get(ctx, category, version)
+}
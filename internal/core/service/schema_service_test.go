@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSchemaRegistry struct {
+	mock.Mock
+}
+
+func (m *MockSchemaRegistry) Save(ctx context.Context, schema *domain.CategorySchema) error {
+	args := m.Called(ctx, schema)
+	return args.Error(0)
+}
+
+func (m *MockSchemaRegistry) Get(ctx context.Context, category domain.ProductCategory, version string) (*domain.CategorySchema, error) {
+	args := m.Called(ctx, category, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategorySchema), args.Error(1)
+}
+
+func (m *MockSchemaRegistry) GetActive(ctx context.Context, category domain.ProductCategory) (*domain.CategorySchema, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategorySchema), args.Error(1)
+}
+
+func (m *MockSchemaRegistry) List(ctx context.Context, category domain.ProductCategory) ([]*domain.CategorySchema, error) {
+	args := m.Called(ctx, category)
+	return args.Get(0).([]*domain.CategorySchema), args.Error(1)
+}
+
+func (m *MockSchemaRegistry) Activate(ctx context.Context, category domain.ProductCategory, version string) error {
+	args := m.Called(ctx, category, version)
+	return args.Error(0)
+}
+
+func (m *MockSchemaRegistry) Deactivate(ctx context.Context, category domain.ProductCategory) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+// textileV2 only requires a product name, so payloads valid under it fail the bootstrap schema.
+const textileV2 = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["productName"],
+	"properties": {
+		"productName": {"type": "string", "access": "public"},
+		"supplierAudit": {"type": "string", "access": "restricted"}
+	}
+}`
+
+func TestUploadSchema_Rules(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		category domain.ProductCategory
+		version  string
+		schema   string
+		existing []*domain.CategorySchema
+		wantErr  error
+	}{
+		{"unknown category", "FURNITURE", "1.1.0", textileV2, nil, domain.ErrInvalidInput},
+		{"bad version", domain.CategoryTextile, "v2", textileV2, nil, domain.ErrInvalidInput},
+		{"not json", domain.CategoryTextile, "1.1.0", "{", nil, domain.ErrInvalidInput},
+		{"not above bootstrap", domain.CategoryTextile, "1.0.0", textileV2, []*domain.CategorySchema{}, domain.ErrConflict},
+		{"not above latest", domain.CategoryTextile, "1.5.0", textileV2, []*domain.CategorySchema{{Version: "2.0.0"}}, domain.ErrConflict},
+		{"does not compile", domain.CategoryTextile, "1.1.0", `{"type": 12}`, []*domain.CategorySchema{}, domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := new(MockSchemaRegistry)
+			if tt.existing != nil {
+				registry.On("List", ctx, tt.category).Return(tt.existing, nil)
+			}
+			svc := service.NewSchemaService(registry, nil, logger)
+
+			_, err := svc.UploadSchema(ctx, tt.category, tt.version, []byte(tt.schema))
+
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+			registry.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUploadSchema_SavesInactive(t *testing.T) {
+	registry := new(MockSchemaRegistry)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc := service.NewSchemaService(registry, nil, logger)
+	ctx := context.Background()
+
+	registry.On("List", ctx, domain.CategoryTextile).Return([]*domain.CategorySchema{{Version: "1.1.0"}}, nil)
+	registry.On("Save", ctx, mock.MatchedBy(func(s *domain.CategorySchema) bool {
+		return s.Version == "1.10.0" && !s.Active
+	})).Return(nil)
+
+	stored, err := svc.UploadSchema(ctx, domain.CategoryTextile, "1.10.0", []byte(textileV2))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.10.0", stored.Version)
+	registry.AssertExpectations(t)
+}
+
+func TestListSchemas_BootstrapActiveUntilReplaced(t *testing.T) {
+	registry := new(MockSchemaRegistry)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc := service.NewSchemaService(registry, nil, logger)
+	ctx := context.Background()
+
+	registry.On("List", ctx, domain.CategoryTextile).Return([]*domain.CategorySchema{{Version: "1.1.0", Active: true}}, nil)
+
+	schemas, err := svc.ListSchemas(ctx, domain.CategoryTextile)
+
+	assert.NoError(t, err)
+	assert.Len(t, schemas, 2)
+	bootstrap := schemas[1]
+	assert.True(t, bootstrap.BuiltIn)
+	assert.Equal(t, service.BootstrapSchemaVersion, bootstrap.Version)
+	assert.False(t, bootstrap.Active)
+}
+
+func TestActivateSchema_RollsBackToBootstrap(t *testing.T) {
+	registry := new(MockSchemaRegistry)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	svc := service.NewSchemaService(registry, nil, logger)
+	ctx := context.Background()
+
+	registry.On("Deactivate", ctx, domain.CategoryTextile).Return(nil).Once()
+
+	schema, err := svc.ActivateSchema(ctx, domain.CategoryTextile, service.BootstrapSchemaVersion)
+
+	assert.NoError(t, err)
+	assert.True(t, schema.BuiltIn)
+	assert.True(t, schema.Active)
+	registry.AssertExpectations(t)
+	registry.AssertNotCalled(t, "Activate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCachedSchemaRegistry_ForgetsOnActivate(t *testing.T) {
+	registry := new(MockSchemaRegistry)
+	cached := service.NewCachedSchemaRegistry(registry, time.Hour)
+	ctx := context.Background()
+	v2 := &domain.CategorySchema{Category: domain.CategoryTextile, Version: "2.0.0", Active: true}
+
+	// Expectations: one lookup until the active version changes
+	registry.On("GetActive", ctx, domain.CategoryTextile).Return(nil, domain.ErrNotFound).Once()
+	registry.On("Activate", ctx, domain.CategoryTextile, "2.0.0").Return(nil).Once()
+	registry.On("GetActive", ctx, domain.CategoryTextile).Return(v2, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		_, err := cached.GetActive(ctx, domain.CategoryTextile)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	}
+	assert.NoError(t, cached.Activate(ctx, domain.CategoryTextile, "2.0.0"))
+	for i := 0; i < 2; i++ {
+		active, err := cached.GetActive(ctx, domain.CategoryTextile)
+		assert.NoError(t, err)
+		assert.Equal(t, v2, active)
+	}
+	registry.AssertExpectations(t)
+}
+
+func TestCreatePassport_UsesActiveRegistrySchema(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	registry := new(MockSchemaRegistry)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	svc, err := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), registry, nil, nil, nil, "https://tapi.eu", logger)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	registry.On("GetActive", ctx, domain.CategoryTextile).Return(&domain.CategorySchema{
+		Category: domain.CategoryTextile,
+		Version:  "2.0.0",
+		Schema:   json.RawMessage(textileV2),
+		Active:   true,
+	}, nil)
+	mockCache.On("GetIdempotency", ctx, mock.Anything).Return("", errors.New("cache miss"))
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(p *domain.Passport) bool {
+		return p.SchemaVersion == "2.0.0"
+	})).Return(nil)
+	mockCache.On("SetIdempotency", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	passport, err := svc.CreatePassport(ctx, "mfg-1", "Manufacturer 1", domain.CategoryTextile, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, []byte(`{"productName": "Shirt"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", passport.SchemaVersion)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetPassport_FiltersWithRecordedSchemaVersion(t *testing.T) {
+	mockRepo := new(MockPassportRepository)
+	mockCache := new(MockCacheRepository)
+	registry := new(MockSchemaRegistry)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	svc, err := service.NewPassportService(mockRepo, mockCache, new(MockBlobStorage), registry, nil, nil, nil, "https://tapi.eu", logger)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	passport := &domain.Passport{
+		ID:              [16]byte{1},
+		ProductCategory: domain.CategoryTextile,
+		ManufacturerID:  "mfg-1",
+		Attributes:      json.RawMessage(`{"productName": "Shirt", "supplierAudit": "internal"}`),
+		SchemaVersion:   "2.0.0",
+	}
+
+	registry.On("Get", mock.Anything, domain.CategoryTextile, "2.0.0").Return(&domain.CategorySchema{
+		Category: domain.CategoryTextile,
+		Version:  "2.0.0",
+		Schema:   json.RawMessage(textileV2),
+	}, nil)
+	mockCache.On("Get", mock.Anything, mock.Anything).Return("", errors.New("cache miss"))
+	mockRepo.On("GetByID", mock.Anything, passport.ID).Return(passport, nil)
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	publicCtx := context.WithValue(ctx, domain.ViewContextKey, domain.ViewContextPublic)
+	result, err := svc.GetPassport(publicCtx, passport.ID)
+
+	assert.NoError(t, err)
+	var attrs map[string]interface{}
+	assert.NoError(t, json.Unmarshal(result.Attributes, &attrs))
+	assert.Equal(t, "Shirt", attrs["productName"])
+	assert.NotContains(t, attrs, "supplierAudit")
+	registry.AssertExpectations(t)
+}
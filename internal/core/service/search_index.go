@@ -0,0 +1,340 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// reindexPageSize is the number of passports indexed per repository round trip.
+const reindexPageSize = 500
+
+// searchField is a schema location whose text is indexed for full-text search. The "search"
+// annotation ("A" to "D") selects it and sets its weight.
+type searchField struct {
+	path   []pathSegment
+	weight domain.SearchWeight
+}
+
+func (f searchField) Pointer() string {
+	return redactionRule{path: f.path}.Pointer()
+}
+
+// parseSearchFields walks the schema like parseRedactionRules and returns one field per location
+// annotated with "search". Unknown weights are rejected.
+func parseSearchFields(rawSchema []byte, levels *domain.AccessLevels) ([]searchField, error) {
+	w, err := walkSchema(rawSchema, levels)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(w.fields, func(i, j int) bool {
+		return w.fields[i].Pointer() < w.fields[j].Pointer()
+	})
+	return w.fields, nil
+}
+
+func (w *schemaWalker) addField(path []pathSegment, weight domain.SearchWeight) {
+	field := searchField{path: append([]pathSegment(nil), path...), weight: weight}
+	if key := field.Pointer() + "#" + string(weight); !w.seen[key] {
+		w.seen[key] = true
+		w.fields = append(w.fields, field)
+	}
+}
+
+// searchDocuments returns the searchable text of a (merged) document for every access rank:
+// each is built from the document as redacted for that rank, so it only holds text viewers of
+// that rank can read. A schema without search fields yields no documents.
+func searchDocuments(compiled *compiledSchema, doc interface{}, topRank int) ([]domain.SearchDocument, error) {
+	docs := []domain.SearchDocument{}
+	if len(compiled.searchFields) == 0 {
+		return docs, nil
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal attributes: %w", err)
+	}
+	for rank := 0; rank <= topRank; rank++ {
+		// redact works in place: every rank starts from a fresh copy
+		attrs, err := decodeAttributes(raw)
+		if err != nil {
+			return nil, err
+		}
+		visible := redact(attrs, compiled.redactions, rank)
+
+		words := make(map[domain.SearchWeight][]string)
+		for _, field := range compiled.searchFields {
+			collectText(visible, field.path, func(text string) {
+				words[field.weight] = append(words[field.weight], text)
+			})
+		}
+		text := make(map[domain.SearchWeight]string, len(words))
+		for weight, w := range words {
+			text[weight] = strings.Join(w, " ")
+		}
+		docs = append(docs, domain.SearchDocument{AccessRank: rank, Text: text})
+	}
+	return docs, nil
+}
+
+// collectText passes every string located at the path (or held by an array there) to add,
+// in document order.
+func collectText(value interface{}, path []pathSegment, add func(string)) {
+	if len(path) == 0 {
+		switch v := value.(type) {
+		case string:
+			add(v)
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					add(s)
+				}
+			}
+		}
+		return
+	}
+
+	seg, rest := path[0], path[1:]
+	if seg.kind == segmentDescendant {
+		// Zero levels: the rest applies here. More levels: every child with the same path.
+		if len(rest) > 0 {
+			collectText(value, rest, add)
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for _, key := range sortedKeys(v) {
+				collectText(v[key], path, add)
+			}
+		case []interface{}:
+			for _, child := range v {
+				collectText(child, path, add)
+			}
+		}
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if seg.matchesKey(key) {
+				collectText(v[key], rest, add)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if seg.matchesIndex(i) {
+				collectText(child, rest, add)
+			}
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// indexText sets the search documents of a passport from its merged document, to be written
+// with it. Indexing never blocks a write: on failure the passport keeps its previous documents.
+func (s *passportService) indexText(passport *domain.Passport, compiled *compiledSchema, doc interface{}) {
+	docs, err := searchDocuments(compiled, doc, s.topRank())
+	if err != nil {
+		s.log.Warn("failed to build search documents", "id", passport.ID, "error", err)
+		return
+	}
+	passport.SearchDocuments = docs
+}
+
+// topRank is the rank of the highest access level (what owners see).
+func (s *passportService) topRank() int {
+	return len(s.levels.Levels()) - 1
+}
+
+func (s *passportService) SearchText(ctx context.Context, text string, limit int) ([]domain.TextSearchResult, error) {
+	// 1. Validate the Search
+	terms, err := domain.ParseSearchTerms(text)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = domain.DefaultTextSearchLimit
+	}
+	if limit < 1 || limit > domain.MaxTextSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, domain.MaxTextSearchLimit)
+	}
+
+	// 2. The Viewer's Ranks (same rules as GetPassport): anonymous viewers only match public text
+	query := domain.TextSearchQuery{Terms: terms, Limit: limit}
+	viewContext, _ := ctx.Value(domain.ViewContextKey).(string)
+	if viewContext == domain.ViewContextRestricted {
+		viewerTenantID, _ := ctx.Value(domain.ViewerTenantIDKey).(string)
+		level, _ := ctx.Value(domain.ViewerAccessLevelKey).(string)
+		query.OwnerID = viewerTenantID
+		query.OwnerRank = s.topRank()
+		query.ViewerRank = min(s.levels.ViewerRank(domain.AccessLevel(level)), s.topRank())
+		query.Grants = s.rankedGrants(ctx, viewerTenantID)
+	}
+
+	// 3. Match
+	hits, err := s.repo.SearchText(ctx, query)
+	if err != nil {
+		s.log.Error("failed to search passports", "error", err)
+		return nil, fmt.Errorf("%w: search failed", domain.ErrInternal)
+	}
+
+	// 4. Serve each Passport as the Resolver would
+	results := make([]domain.TextSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		passport, err := s.GetPassport(ctx, hit.PassportID)
+		if err != nil {
+			s.log.Warn("search hit not found", "id", hit.PassportID, "error", err)
+			continue
+		}
+		results = append(results, domain.TextSearchResult{Score: hit.Score, Passport: passport})
+	}
+	return results, nil
+}
+
+// rankedGrants returns the viewer's active grants with their ranks. Lookup failures fail closed
+// (no grant).
+func (s *passportService) rankedGrants(ctx context.Context, viewerTenantID string) []domain.RankedGrant {
+	if s.grants == nil || viewerTenantID == "" {
+		return nil
+	}
+
+	now := time.Now()
+	grants, err := s.grants.FindActiveForGrantee(ctx, viewerTenantID, now)
+	if err != nil {
+		s.log.Error("failed to look up access grants", "viewer", viewerTenantID, "error", err)
+		return nil
+	}
+
+	var ranked []domain.RankedGrant
+	for _, g := range grants {
+		rank := min(s.levels.ViewerRank(g.AccessLevel), s.topRank())
+		if !g.IsActive(now) || rank <= 0 {
+			continue
+		}
+		grant := domain.RankedGrant{AccessGrant: g, Rank: rank}
+
+		// A grant on any revision of the product covers all of them (same rule as GetPassport)
+		if g.Scope == domain.GrantScopePassport && g.PassportID != nil {
+			versions, err := s.repo.FindVersionHistory(ctx, *g.PassportID)
+			if err != nil {
+				s.log.Error("failed to look up revision history", "id", *g.PassportID, "error", err)
+				continue
+			}
+			grant.Revisions = []uuid.UUID{*g.PassportID}
+			for _, v := range versions {
+				if v.PassportID != *g.PassportID {
+					grant.Revisions = append(grant.Revisions, v.PassportID)
+				}
+			}
+		}
+		ranked = append(ranked, grant)
+	}
+	return ranked
+}
+
+func (s *passportService) ReindexSearch(ctx context.Context, manufacturerID string, progress func(done int)) (*domain.ReindexReport, error) {
+	report := &domain.ReindexReport{}
+	query := domain.PassportQuery{Sort: domain.SortCreatedAt, Limit: reindexPageSize}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// 1. Next Page (oldest first)
+		page, err := s.repo.FindByManufacturer(ctx, manufacturerID, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list passports: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		// 2. Build the Documents (with the schema version each passport was validated against)
+		indexed := make([]*domain.Passport, 0, len(page))
+		for _, passport := range page {
+			compiled, err := s.schemas.version(ctx, passport.ProductCategory, passport.SchemaVersion)
+			if err != nil {
+				s.log.Warn("failed to load schema for indexing", "id", passport.ID, "error", err)
+				report.Failed = append(report.Failed, passport.ID)
+				continue
+			}
+			doc, err := s.mergedAttributes(ctx, passport)
+			if err != nil {
+				s.log.Warn("failed to merge attributes for indexing", "id", passport.ID, "error", err)
+				report.Failed = append(report.Failed, passport.ID)
+				continue
+			}
+			if passport.SearchDocuments, err = searchDocuments(compiled, doc, s.topRank()); err != nil {
+				s.log.Warn("failed to build search documents", "id", passport.ID, "error", err)
+				report.Failed = append(report.Failed, passport.ID)
+				continue
+			}
+			indexed = append(indexed, passport)
+		}
+
+		// 3. Replace them in one Transaction
+		if err := s.repo.SaveSearchDocuments(ctx, indexed); err != nil {
+			return nil, fmt.Errorf("failed to save search documents: %w", err)
+		}
+		report.Indexed += len(indexed)
+		if progress != nil {
+			progress(report.Indexed + len(report.Failed))
+		}
+
+		if len(page) < query.Limit {
+			break
+		}
+		last := page[len(page)-1]
+		query.After = &domain.PassportCursor{Order: query.Order(), Key: query.Sort.Key(last), ID: last.ID}
+	}
+
+	s.log.Info("search documents rebuilt", "manufacturer", manufacturerID, "indexed", report.Indexed, "failed", len(report.Failed))
+	return report, nil
+}
+
+// searchReindexJob runs JobTypeSearchReindex jobs in cmd/api-worker. Rebuilding is idempotent,
+// so a retried job simply starts over.
+type searchReindexJob struct {
+	passports ports.PassportService
+}
+
+func NewSearchReindexJob(passports ports.PassportService) ports.JobHandler {
+	return &searchReindexJob{passports: passports}
+}
+
+func (j *searchReindexJob) Run(ctx context.Context, job *domain.Job, _ []byte, progress func(domain.JobProgress)) ([]byte, error) {
+	report, err := j.passports.ReindexSearch(ctx, job.TenantID, func(done int) {
+		progress(domain.JobProgress{Done: done})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(report)
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDocuments_LeaveOutRestrictedText(t *testing.T) {
+	levels := domain.MustDefaultAccessLevels()
+	compiled, err := compileSchema(domain.CategoryTextile, BootstrapSchemaVersion, []byte(textileSchemaRaw), levels)
+	require.NoError(t, err)
+
+	doc, err := decodeAttributes([]byte(`{
+		"garmentType": "T-Shirt",
+		"fiberComposition": [{"fiberName": "COTTON", "percentage": 60}, {"fiberName": "POLYESTER", "percentage": 40}],
+		"supplyChainDetails": {"spinningFactory": "Secret Spinning Ltd", "dyeingFactory": "Blue Dye Works"}
+	}`))
+	require.NoError(t, err)
+
+	docs, err := searchDocuments(compiled, doc, len(levels.Levels())-1)
+
+	// One document per rank; supplier names only from the first level above public
+	require.NoError(t, err)
+	require.Len(t, docs, 4)
+	assert.Equal(t, map[domain.SearchWeight]string{
+		domain.SearchWeightA: "T-Shirt",
+		domain.SearchWeightB: "COTTON POLYESTER",
+	}, docs[0].Text)
+	assert.Equal(t, 1, docs[1].AccessRank)
+	assert.Equal(t, "Blue Dye Works Secret Spinning Ltd", docs[1].Text[domain.SearchWeightC])
+	assert.Equal(t, docs[1].Text, docs[3].Text)
+}
+
+func TestParseSearchFields_RejectsUnknownWeight(t *testing.T) {
+	_, err := parseSearchFields([]byte(`{"properties": {"model": {"type": "string", "search": "E"}}}`), domain.MustDefaultAccessLevels())
+	assert.Error(t, err)
+}
+
+func TestCreatePassport_IndexesText(t *testing.T) {
+	repo := new(MockRepo)
+	cache := new(MockCache)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	cache.On("GetIdempotency", mock.Anything, mock.Anything).Return("", assert.AnError)
+	cache.On("SetIdempotency", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	payload := []byte(`{"batteryModel": "PowerCell LFP-280", "chemistry": "LITHIUM_ION", "ratedCapacity": 280,
+		"carbonFootprint": {"totalCarbonFootprint": 50, "shareOfRenewables": 90}, "materialComposition": [],
+		"disassemblyInstructions": {"safetyMeasures": "Discharge below 2V"}}`)
+	passport, err := svc.CreatePassport(context.Background(), "mfg-1", "Acme", domain.CategoryBattery, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, payload)
+
+	// The safety measures are restricted: only indexed from the first level above public
+	require.NoError(t, err)
+	require.Len(t, passport.SearchDocuments, 4)
+	assert.Equal(t, "PowerCell LFP-280", passport.SearchDocuments[0].Text[domain.SearchWeightA])
+	assert.Equal(t, "LITHIUM_ION", passport.SearchDocuments[0].Text[domain.SearchWeightB])
+	assert.Empty(t, passport.SearchDocuments[0].Text[domain.SearchWeightD])
+	assert.Equal(t, "Discharge below 2V", passport.SearchDocuments[1].Text[domain.SearchWeightD])
+}
+
+func TestSearchText_SearchesAsTheViewer(t *testing.T) {
+	repo := new(MockRepo)
+	cache := new(MockCache)
+	svc, err := NewPassportService(repo, cache, nil, nil, nil, nil, nil, "https://tapi.eu", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	id := uuid.New()
+	cache.On("Get", mock.Anything, mock.Anything).Return("", assert.AnError)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	stored := func() *domain.Passport {
+		return &domain.Passport{
+			ID:              id,
+			ProductCategory: domain.CategoryTextile,
+			Status:          domain.StatusPublished,
+			ManufacturerID:  "mfg-1",
+			Attributes:      json.RawMessage(`{"garmentType": "T-Shirt", "supplyChainDetails": {"spinningFactory": "Secret Spinning Ltd"}}`),
+		}
+	}
+
+	// Anonymous viewers: public text only, and the passport comes filtered
+	repo.On("SearchText", mock.Anything, domain.TextSearchQuery{Terms: []string{"t", "shirt"}, Limit: domain.DefaultTextSearchLimit}).
+		Return([]domain.TextSearchHit{{PassportID: id, Score: 0.6}}, nil).Once()
+	repo.On("GetByID", mock.Anything, id).Return(stored(), nil).Once()
+
+	results, err := svc.SearchText(context.Background(), "T-Shirt", 0)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 0.6, results[0].Score)
+	assert.NotContains(t, string(results[0].Passport.Attributes), "Secret")
+
+	// Authenticated viewers: their own passports at the top rank, others' at their credential's level
+	ctx := context.WithValue(context.Background(), domain.ViewContextKey, domain.ViewContextRestricted)
+	ctx = context.WithValue(ctx, domain.ViewerTenantIDKey, "recycler-1")
+	ctx = context.WithValue(ctx, domain.ViewerAccessLevelKey, string(domain.AccessRestricted))
+	repo.On("SearchText", mock.Anything, domain.TextSearchQuery{Terms: []string{"secret"}, OwnerID: "recycler-1", OwnerRank: 3, ViewerRank: 1, Limit: 5}).
+		Return([]domain.TextSearchHit{{PassportID: id, Score: 0.2}}, nil).Once()
+	repo.On("GetByID", mock.Anything, id).Return(stored(), nil).Once()
+
+	results, err = svc.SearchText(ctx, "secret", 5)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Contains(t, string(results[0].Passport.Attributes), "Secret Spinning Ltd")
+	repo.AssertExpectations(t)
+
+	// Invalid searches
+	for _, text := range []string{"", " -- "} {
+		_, err := svc.SearchText(context.Background(), text, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	}
+	_, err = svc.SearchText(context.Background(), "shirt", domain.MaxTextSearchLimit+1)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+// Headers of a webhook delivery. The signature covers "<timestamp>.<body>".
+const (
+	WebhookHeaderDelivery  = "X-TraceApi-Delivery"
+	WebhookHeaderEvent     = "X-TraceApi-Event"
+	WebhookHeaderTimestamp = "X-TraceApi-Timestamp"
+	WebhookHeaderSignature = "X-TraceApi-Signature"
+)
+
+// maxWebhookBackoff caps the wait between two attempts.
+const maxWebhookBackoff = 6 * time.Hour
+
+// SignWebhook returns the signature header value of a delivery: "sha256=" and the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription secret.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DispatcherConfig tunes a WebhookDispatcher. Zero values take the defaults.
+type DispatcherConfig struct {
+	Concurrency  int           // Deliveries sent in parallel (default 8)
+	PollInterval time.Duration // Wait when nothing is due (default 2s)
+	Timeout      time.Duration // Per attempt (default 10s)
+	MaxAttempts  int           // Attempts before a delivery is dead-lettered (default 10)
+}
+
+// WebhookDispatcher sends due webhook deliveries, retrying failures with exponential backoff.
+type WebhookDispatcher struct {
+	webhooks ports.WebhookRepository
+	sender   ports.WebhookSender
+	cfg      DispatcherConfig
+	log      *slog.Logger
+}
+
+func NewWebhookDispatcher(webhooks ports.WebhookRepository, sender ports.WebhookSender, cfg DispatcherConfig, log *slog.Logger) *WebhookDispatcher {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 8
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 10
+	}
+	return &WebhookDispatcher{webhooks: webhooks, sender: sender, cfg: cfg, log: log}
+}
+
+// Run sends deliveries until ctx is cancelled, then waits for the attempts in flight.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Claimed deliveries stay hidden for two timeouts: long enough to record the attempt
+		deliveries, err := d.webhooks.ClaimDeliveries(ctx, d.cfg.Concurrency, 2*d.cfg.Timeout)
+		if err != nil && ctx.Err() == nil {
+			d.log.Error("failed to claim webhook deliveries", "error", err)
+		}
+		if len(deliveries) == 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(d.cfg.PollInterval):
+			}
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.Deliver(ctx, delivery)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// Deliver makes one attempt and records its outcome: delivered, retried later, or dead-lettered.
+func (d *WebhookDispatcher) Deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	log := d.log.With("delivery", delivery.ID, "event", delivery.Event, "attempt", delivery.Attempts+1)
+
+	// 1. Load the Endpoint (a deleted subscription takes its deliveries along)
+	sub, err := d.webhooks.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Error("failed to fetch webhook subscription", "error", err) // Retried once the claim expires
+		}
+		return
+	}
+
+	// 2. Send
+	statusCode, err := d.send(ctx, sub, delivery)
+	if ctx.Err() != nil {
+		return // Shutting down: the claim expires and the attempt is made again
+	}
+
+	// 3. Record the Outcome
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	switch {
+	case err == nil:
+		delivery.Status = domain.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = domain.DeliveryDead
+		delivery.LastError = err.Error()
+	default:
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		delivery.LastError = err.Error()
+	}
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := d.webhooks.RecordAttempt(recordCtx, delivery); err != nil {
+		log.Error("failed to record webhook attempt", "error", err)
+		return
+	}
+
+	switch delivery.Status {
+	case domain.DeliveryDelivered:
+		log.Info("webhook delivered", "status", statusCode)
+	case domain.DeliveryDead:
+		log.Warn("webhook dead-lettered", "status", statusCode, "error", delivery.LastError)
+	default:
+		log.Warn("webhook attempt failed", "status", statusCode, "error", delivery.LastError, "retryAt", delivery.NextAttemptAt)
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		WebhookHeaderDelivery:  delivery.ID.String(),
+		WebhookHeaderEvent:     string(delivery.Event),
+		WebhookHeaderTimestamp: strconv.FormatInt(timestamp, 10),
+		WebhookHeaderSignature: SignWebhook(sub.Secret, timestamp, delivery.Payload),
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+	statusCode, err := d.sender.Send(sendCtx, sub.URL, headers, delivery.Payload)
+	if err != nil {
+		return statusCode, err
+	}
+	if statusCode < 200 || statusCode > 299 {
+		return statusCode, fmt.Errorf("endpoint answered %d", statusCode)
+	}
+	return statusCode, nil
+}
+
+// webhookBackoff doubles with every failed attempt: 30s, 1m, 2m... up to maxWebhookBackoff.
+func webhookBackoff(attempts int) time.Duration {
+	backoff := 30 * time.Second
+	for i := 1; i < attempts && backoff < maxWebhookBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxWebhookBackoff)
+}
@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// deliveryListLimit caps GET /webhooks/deliveries.
+const deliveryListLimit = 100
+
+type webhookService struct {
+	webhooks ports.WebhookRepository
+	log      *slog.Logger
+}
+
+// Ensure interface implementation
+var _ ports.WebhookService = (*webhookService)(nil)
+
+// NewWebhookService wires the webhook subscriptions (cmd/api-worker sends the deliveries).
+func NewWebhookService(webhooks ports.WebhookRepository, log *slog.Logger) ports.WebhookService {
+	return &webhookService{webhooks: webhooks, log: log}
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, tenantID string, input domain.WebhookInput) (*domain.WebhookSubscription, error) {
+	// 1. Validate Endpoint
+	endpoint, err := validateWebhookURL(input.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate Events (none means all)
+	events := domain.WebhookEvents
+	if len(input.Events) > 0 {
+		events = nil
+		seen := make(map[domain.WebhookEvent]bool)
+		for _, name := range input.Events {
+			event, err := domain.ParseWebhookEvent(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			if !seen[event] {
+				seen[event] = true
+				events = append(events, event)
+			}
+		}
+	}
+
+	// 3. Generate the Signing Secret
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	// 4. Save
+	sub := &domain.WebhookSubscription{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		URL:       endpoint.String(),
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.webhooks.SaveSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	s.log.Info("webhook subscription created", "id", sub.ID, "tenant", tenantID, "events", events)
+	return sub, nil
+}
+
+// validateWebhookURL only accepts https URLs whose host is not obviously internal. Host names are
+// checked again where it matters, on the address each delivery connects to (webhook.HTTPSender).
+func validateWebhookURL(raw string) (*url.URL, error) {
+	endpoint, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || endpoint.Scheme != "https" || endpoint.Hostname() == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute https URL", domain.ErrInvalidInput)
+	}
+	if endpoint.User != nil {
+		return nil, fmt.Errorf("%w: url must not carry credentials", domain.ErrInvalidInput)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(endpoint.Hostname()), ".")
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !domain.IsPublicWebhookAddress(addr) {
+			return nil, fmt.Errorf("%w: url must not point to a loopback, link-local or private address", domain.ErrInvalidInput)
+		}
+	} else if host == "localhost" || strings.HasSuffix(host, ".localhost") || !strings.Contains(host, ".") {
+		return nil, fmt.Errorf("%w: url must use a public host name", domain.ErrInvalidInput)
+	}
+	return endpoint, nil
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context, tenantID string) ([]*domain.WebhookSubscription, error) {
+	subs, err := s.webhooks.FindSubscriptions(ctx, tenantID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		sub.Secret = "" // Only shown at creation
+	}
+	return subs, nil
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id uuid.UUID, tenantID string) error {
+	sub, err := s.webhooks.GetSubscription(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch webhook subscription: %w", err)
+	}
+	if sub.TenantID != tenantID {
+		return domain.ErrForbidden
+	}
+	return s.webhooks.DeleteSubscription(ctx, id)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, tenantID string, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	switch status {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: unknown delivery status %q", domain.ErrInvalidInput, status)
+	}
+	return s.webhooks.FindDeliveries(ctx, tenantID, status, deliveryListLimit)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, id uuid.UUID, tenantID string) (*domain.WebhookDelivery, error) {
+	delivery, err := s.webhooks.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook delivery: %w", err)
+	}
+	if delivery.TenantID != tenantID {
+		return nil, domain.ErrForbidden
+	}
+
+	delivery, err = s.webhooks.Redeliver(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("webhook delivery queued again", "id", id, "subscription", delivery.SubscriptionID)
+	return delivery, nil
+}
+
+// newWebhookSecret returns a random signing key.
+func newWebhookSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// webhookChannels maps the event bus channels to the webhook events they trigger.
+var webhookChannels = map[string]domain.WebhookEvent{
+	"events:passport_created":   domain.WebhookPassportCreated,
+	"events:passport_updated":   domain.WebhookPassportUpdated,
+	"events:passport_published": domain.WebhookPassportPublished,
+	"events:passport_revoked":   domain.WebhookPassportRevoked,
+}
+
+// WebhookFanout turns the passport lifecycle events read from the event bus into webhook
+// deliveries (cmd/api-worker consumes the channels as the "webhooks" group).
+type WebhookFanout struct {
+	webhooks ports.WebhookRepository
+	log      *slog.Logger
+}
+
+func NewWebhookFanout(webhooks ports.WebhookRepository, log *slog.Logger) *WebhookFanout {
+	return &WebhookFanout{webhooks: webhooks, log: log}
+}
+
+// Channels lists the event bus channels that trigger webhooks.
+func (f *WebhookFanout) Channels() []string {
+	channels := make([]string, 0, len(webhookChannels))
+	for channel := range webhookChannels {
+		channels = append(channels, channel)
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+// Handle queues a delivery for every subscription of the event's tenant. Delivery IDs derive
+// from the subscription and the event's own ID (not its stream entry, which changes when the
+// outbox relays it again), so an event handled twice is only queued once.
+func (f *WebhookFanout) Handle(ctx context.Context, msg domain.EventMessage) error {
+	webhookEvent, ok := webhookChannels[msg.Channel]
+	if !ok {
+		return nil
+	}
+
+	// 1. Find the Subscribers of the Tenant
+	var envelope domain.Event
+	if err := json.Unmarshal(msg.Payload, &envelope); err != nil || envelope.Tenant == "" || envelope.ID == uuid.Nil {
+		f.log.Error("event has no tenant or id, skipping webhooks", "channel", msg.Channel, "id", msg.ID)
+		return nil // Would fail the same way every time
+	}
+
+	subs, err := f.webhooks.FindSubscriptions(ctx, envelope.Tenant, webhookEvent)
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	// 2. Queue one Delivery per Subscription
+	now := time.Now().UTC()
+	deliveries := make([]*domain.WebhookDelivery, 0, len(subs))
+	for _, sub := range subs {
+		id := uuid.NewSHA1(sub.ID, envelope.ID[:])
+		payload, err := json.Marshal(domain.WebhookPayload{DeliveryID: id, Event: webhookEvent, CreatedAt: now, Data: msg.Payload})
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook payload: %w", err)
+		}
+		deliveries = append(deliveries, &domain.WebhookDelivery{
+			ID:             id,
+			SubscriptionID: sub.ID,
+			TenantID:       sub.TenantID,
+			Event:          webhookEvent,
+			Payload:        payload,
+			Status:         domain.DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+	return f.webhooks.SaveDeliveries(ctx, deliveries)
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+var (
+	errJobCancelled  = errors.New("job cancelled")
+	errWorkerStopped = errors.New("worker shutting down")
+	errLeaseLost     = errors.New("job lease lost")
+)
+
+// WorkerConfig tunes a JobWorker. Zero values take the defaults.
+type WorkerConfig struct {
+	ID           string        // Lease owner, unique per process (e.g. hostname-pid)
+	Concurrency  int           // Jobs run in parallel (default 4)
+	PollInterval time.Duration // Wait when the queue is empty (default 2s)
+	Lease        time.Duration // Without a heartbeat for this long, another worker takes the job over (default 1m)
+}
+
+// JobWorker claims queued jobs and runs them with the handler of their type.
+type JobWorker struct {
+	jobs     ports.JobRepository
+	handlers map[domain.JobType]ports.JobHandler
+	cfg      WorkerConfig
+	log      *slog.Logger
+}
+
+func NewJobWorker(jobs ports.JobRepository, handlers map[domain.JobType]ports.JobHandler, cfg WorkerConfig, log *slog.Logger) *JobWorker {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 4
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+	return &JobWorker{jobs: jobs, handlers: handlers, cfg: cfg, log: log}
+}
+
+// Run processes jobs until ctx is cancelled. Running jobs are then interrupted and queued
+// again, and Run returns once they have been handed back.
+func (w *JobWorker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.poll(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *JobWorker) poll(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.jobs.Claim(ctx, w.cfg.ID, w.cfg.Lease)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) && ctx.Err() == nil {
+				w.log.Error("failed to claim job", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.cfg.PollInterval):
+			}
+			continue
+		}
+		w.execute(ctx, job)
+	}
+}
+
+// execute runs a claimed job while a heartbeat keeps its lease, then records the outcome.
+func (w *JobWorker) execute(ctx context.Context, job *domain.Job) {
+	log := w.log.With("job", job.ID, "type", job.Type, "attempt", job.Attempts)
+	log.Info("job started")
+
+	// 1. The job context ends on cancellation, shutdown or a lost lease
+	jobCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	defer cancel(nil)
+	stopOnShutdown := context.AfterFunc(ctx, func() { cancel(errWorkerStopped) })
+	defer stopOnShutdown()
+
+	// 2. Heartbeat
+	var mu sync.Mutex
+	progress := job.Progress
+	report := func(p domain.JobProgress) {
+		mu.Lock()
+		progress = p
+		mu.Unlock()
+	}
+	current := func() domain.JobProgress {
+		mu.Lock()
+		defer mu.Unlock()
+		return progress
+	}
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(w.cfg.Lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				cancelRequested, err := w.jobs.Heartbeat(jobCtx, job.ID, w.cfg.ID, w.cfg.Lease, current())
+				switch {
+				case errors.Is(err, domain.ErrConflict):
+					cancel(errLeaseLost)
+				case err != nil:
+					log.Warn("job heartbeat failed", "error", err)
+				case cancelRequested:
+					cancel(errJobCancelled)
+				}
+			}
+		}
+	}()
+
+	// 3. Run
+	result, err := w.run(jobCtx, job, report)
+	cancel(nil)
+	<-heartbeatDone
+
+	// 4. Record the Outcome (even while shutting down)
+	cause := context.Cause(jobCtx)
+	if errors.Is(cause, errLeaseLost) {
+		log.Warn("job lease lost; another worker owns it now")
+		return
+	}
+
+	now := time.Now().UTC()
+	job.Progress = current()
+	switch {
+	case err == nil:
+		job.Status = domain.JobSucceeded
+		job.FinishedAt = &now
+	case errors.Is(cause, errJobCancelled):
+		job.Status = domain.JobCancelled
+		job.FinishedAt = &now
+		result = nil
+	case errors.Is(cause, errWorkerStopped):
+		job.Status = domain.JobQueued
+		job.RunAfter = now
+		result = nil
+	case errors.Is(err, domain.ErrInvalidInput) || job.Attempts >= job.MaxAttempts:
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+		job.FinishedAt = &now
+		result = nil
+	default:
+		job.Status = domain.JobQueued
+		job.Error = err.Error()
+		job.RunAfter = now.Add(retryBackoff(job.Attempts))
+		result = nil
+	}
+
+	finishCtx, cancelFinish := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancelFinish()
+	if err := w.jobs.Finish(finishCtx, job, w.cfg.ID, result); err != nil {
+		log.Error("failed to record job outcome", "status", job.Status, "error", err)
+		return
+	}
+	log.Info("job finished", "status", job.Status, "error", job.Error)
+}
+
+// run calls the handler, turning a panic into a job failure.
+func (w *JobWorker) run(ctx context.Context, job *domain.Job, progress func(domain.JobProgress)) (result []byte, err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: no handler for job type %s", domain.ErrInvalidInput, job.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+
+	input, err := w.jobs.GetInput(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job input: %w", err)
+	}
+	return handler.Run(ctx, job, input, progress)
+}
+
+// retryBackoff grows quadratically with the attempts made: 10s, 40s, 90s...
+func retryBackoff(attempts int) time.Duration {
+	return time.Duration(attempts*attempts) * 10 * time.Second
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/core/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mocks ---
+
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobRepository) Save(ctx context.Context, job *domain.Job, input []byte) error {
+	args := m.Called(ctx, job, input)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) GetInput(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	args := m.Called(ctx, id)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+func (m *MockJobRepository) GetResult(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	args := m.Called(ctx, id)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+func (m *MockJobRepository) FindByTenant(ctx context.Context, tenantID string, limit int) ([]*domain.Job, error) {
+	args := m.Called(ctx, tenantID, limit)
+	return args.Get(0).([]*domain.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) Claim(ctx context.Context, workerID string, lease time.Duration) (*domain.Job, error) {
+	args := m.Called(ctx, workerID, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) Heartbeat(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration, progress domain.JobProgress) (bool, error) {
+	args := m.Called(ctx, id, workerID, lease, progress)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockJobRepository) Finish(ctx context.Context, job *domain.Job, workerID string, result []byte) error {
+	args := m.Called(ctx, job, workerID, result)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) RequestCancel(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) Requeue(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+// jobHandlerFunc adapts a function to ports.JobHandler.
+type jobHandlerFunc func(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error)
+
+func (f jobHandlerFunc) Run(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error) {
+	return f(ctx, job, input, progress)
+}
+
+// runOneJob lets a worker claim the job, run it with the handler and record the outcome.
+func runOneJob(t *testing.T, job *domain.Job, handler jobHandlerFunc, heartbeatCancels bool) (*domain.Job, []byte) {
+	t.Helper()
+	repo := new(MockJobRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var finished *domain.Job
+	var result []byte
+	repo.On("Claim", mock.Anything, "worker-1", mock.Anything).Return(job, nil).Once()
+	repo.On("Claim", mock.Anything, "worker-1", mock.Anything).Return(nil, domain.ErrNotFound)
+	repo.On("GetInput", mock.Anything, job.ID).Return([]byte("input"), nil)
+	repo.On("Heartbeat", mock.Anything, job.ID, "worker-1", mock.Anything, mock.Anything).Return(heartbeatCancels, nil)
+	repo.On("Finish", mock.Anything, mock.Anything, "worker-1", mock.Anything).Run(func(args mock.Arguments) {
+		finished = args.Get(1).(*domain.Job)
+		result, _ = args.Get(3).([]byte)
+		cancel()
+	}).Return(nil).Once()
+
+	worker := service.NewJobWorker(repo, map[domain.JobType]ports.JobHandler{domain.JobTypeImport: handler}, service.WorkerConfig{
+		ID:           "worker-1",
+		Concurrency:  1,
+		PollInterval: time.Millisecond,
+		Lease:        30 * time.Millisecond,
+	}, logger)
+
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker did not finish the job")
+	}
+	require.NotNil(t, finished)
+	return finished, result
+}
+
+func newClaimedJob(attempts int) *domain.Job {
+	return &domain.Job{ID: uuid.New(), Type: domain.JobTypeImport, TenantID: "mfg-1", Status: domain.JobRunning, Attempts: attempts, MaxAttempts: 3}
+}
+
+// --- Tests ---
+
+func TestJobWorker_Succeeds(t *testing.T) {
+	job, result := runOneJob(t, newClaimedJob(1), func(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error) {
+		assert.Equal(t, []byte("input"), input)
+		progress(domain.JobProgress{Done: 2, Total: 2})
+		return []byte(`{"accepted": 2}`), nil
+	}, false)
+
+	assert.Equal(t, domain.JobSucceeded, job.Status)
+	assert.Equal(t, domain.JobProgress{Done: 2, Total: 2}, job.Progress)
+	assert.NotNil(t, job.FinishedAt)
+	assert.JSONEq(t, `{"accepted": 2}`, string(result))
+}
+
+func TestJobWorker_RetriesTransientFailures(t *testing.T) {
+	failing := func(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error) {
+		return nil, errors.New("database unavailable")
+	}
+
+	t.Run("Queued again with a backoff", func(t *testing.T) {
+		job, _ := runOneJob(t, newClaimedJob(1), failing, false)
+		assert.Equal(t, domain.JobQueued, job.Status)
+		assert.Equal(t, "database unavailable", job.Error)
+		assert.True(t, job.RunAfter.After(time.Now()))
+	})
+
+	t.Run("Failed after the last attempt", func(t *testing.T) {
+		job, _ := runOneJob(t, newClaimedJob(3), failing, false)
+		assert.Equal(t, domain.JobFailed, job.Status)
+		assert.NotNil(t, job.FinishedAt)
+	})
+
+	t.Run("Invalid input is not retried", func(t *testing.T) {
+		job, _ := runOneJob(t, newClaimedJob(1), func(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error) {
+			return nil, fmt.Errorf("%w: unsupported product category", domain.ErrInvalidInput)
+		}, false)
+		assert.Equal(t, domain.JobFailed, job.Status)
+	})
+
+	t.Run("Panics fail the attempt", func(t *testing.T) {
+		job, _ := runOneJob(t, newClaimedJob(3), func(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error) {
+			panic("boom")
+		}, false)
+		assert.Equal(t, domain.JobFailed, job.Status)
+		assert.Contains(t, job.Error, "boom")
+	})
+}
+
+func TestJobWorker_CancellationRequested(t *testing.T) {
+	job, result := runOneJob(t, newClaimedJob(1), func(ctx context.Context, job *domain.Job, input []byte, progress func(domain.JobProgress)) ([]byte, error) {
+		<-ctx.Done() // The heartbeat reports the cancellation
+		return nil, ctx.Err()
+	}, true)
+
+	assert.Equal(t, domain.JobCancelled, job.Status)
+	assert.Nil(t, result)
+}
+
+func TestJobService_TenantIsolation(t *testing.T) {
+	repo := new(MockJobRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewJobService(repo, logger)
+	ctx := context.Background()
+
+	running := newClaimedJob(1)
+	repo.On("GetByID", ctx, running.ID).Return(running, nil)
+
+	_, err := svc.GetJob(ctx, running.ID, "mfg-2")
+	assert.True(t, errors.Is(err, domain.ErrForbidden))
+
+	_, err = svc.CancelJob(ctx, running.ID, "mfg-2")
+	assert.True(t, errors.Is(err, domain.ErrForbidden))
+
+	_, err = svc.GetJobResult(ctx, running.ID, "mfg-1")
+	assert.True(t, errors.Is(err, domain.ErrConflict), "no result while the job runs")
+	repo.AssertNotCalled(t, "RequestCancel", mock.Anything, mock.Anything)
+}
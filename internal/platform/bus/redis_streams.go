@@ -0,0 +1,273 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamConfig tunes a RedisStreamBus. Zero values take the defaults.
+type StreamConfig struct {
+	MaxLen        int64         // Entries kept per stream, trimmed approximately (default 100000)
+	BatchSize     int64         // Entries read per call (default 16)
+	Block         time.Duration // Wait for new entries before checking the pending ones (default 5s)
+	ClaimIdle     time.Duration // Unacknowledged entries idle this long are taken over (default 1m)
+	MaxDeliveries int64         // Deliveries before an entry is moved to "<channel>:dead" (default 10)
+}
+
+// RedisStreamBus publishes events to Redis Streams (one stream per channel) and consumes them
+// through consumer groups. Unlike RedisEventBus, events outlive a subscriber that is down: they
+// wait in the stream, and the entries a crashed consumer did not acknowledge are reclaimed.
+type RedisStreamBus struct {
+	client *redis.Client
+	cfg    StreamConfig
+	log    *slog.Logger
+}
+
+// Ensure we implement the interfaces
+var (
+	_ ports.EventBus        = (*RedisStreamBus)(nil)
+	_ ports.EventSubscriber = (*RedisStreamBus)(nil)
+	_ ports.EventReader     = (*RedisStreamBus)(nil)
+)
+
+func NewRedisStreamBus(client *redis.Client, cfg StreamConfig, log *slog.Logger) *RedisStreamBus {
+	if cfg.MaxLen <= 0 {
+		cfg.MaxLen = 100000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 16
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = 5 * time.Second
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = time.Minute
+	}
+	if cfg.MaxDeliveries <= 0 {
+		cfg.MaxDeliveries = 10
+	}
+	return &RedisStreamBus{client: client, cfg: cfg, log: log}
+}
+
+func (b *RedisStreamBus) Publish(ctx context.Context, channel string, event interface{}) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: channel,
+		MaxLen: b.cfg.MaxLen,
+		Approx: true, // MAXLEN ~ trims whole nodes only, which keeps XADD O(1)
+		Values: map[string]interface{}{"payload": payload},
+	}).Err()
+}
+
+// Subscribe reads new entries, and every ClaimIdle/2 takes over the ones left unacknowledged
+// (including this consumer's own failures). An entry is acknowledged once handler returns nil.
+func (b *RedisStreamBus) Subscribe(ctx context.Context, group, consumer string, channels []string, handler ports.EventHandler) error {
+	// 1. Join the Group (created at the start of the stream on first use)
+	for _, channel := range channels {
+		err := b.client.XGroupCreateMkStream(ctx, channel, group, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("failed to create consumer group %s on %s: %w", group, channel, err)
+		}
+	}
+
+	streams := make([]string, 0, 2*len(channels))
+	streams = append(streams, channels...)
+	for range channels {
+		streams = append(streams, ">") // Entries never delivered to this group
+	}
+
+	// 2. Consume
+	nextReclaim := time.Now()
+	for ctx.Err() == nil {
+		if time.Now().After(nextReclaim) {
+			for _, channel := range channels {
+				b.reclaim(ctx, channel, group, consumer, handler)
+			}
+			nextReclaim = time.Now().Add(b.cfg.ClaimIdle / 2)
+		}
+
+		res, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  streams,
+			Count:    b.cfg.BatchSize,
+			Block:    b.cfg.Block,
+		}).Result()
+		if err == redis.Nil || ctx.Err() != nil {
+			continue
+		}
+		if err != nil {
+			b.log.Error("failed to read event streams", "group", group, "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, stream := range res {
+			for _, msg := range stream.Messages {
+				b.handle(ctx, stream.Stream, group, msg, handler)
+			}
+		}
+	}
+	return nil
+}
+
+// reclaim claims the entries of the group idle for longer than ClaimIdle, and dead-letters
+// the ones already delivered MaxDeliveries times.
+func (b *RedisStreamBus) reclaim(ctx context.Context, channel, group, consumer string, handler ports.EventHandler) {
+	pending, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: channel,
+		Group:  group,
+		Idle:   b.cfg.ClaimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  b.cfg.BatchSize,
+	}).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			b.log.Error("failed to list pending events", "channel", channel, "group", group, "error", err)
+		}
+		return
+	}
+
+	var ids []string
+	for _, p := range pending {
+		if p.RetryCount >= b.cfg.MaxDeliveries {
+			b.deadLetter(ctx, channel, group, p.ID)
+			continue
+		}
+		ids = append(ids, p.ID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	// XCLAIM skips entries another consumer claimed (or acknowledged) in the meantime
+	claimed, err := b.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   channel,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  b.cfg.ClaimIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			b.log.Error("failed to claim pending events", "channel", channel, "group", group, "error", err)
+		}
+		return
+	}
+	for _, msg := range claimed {
+		b.handle(ctx, channel, group, msg, handler)
+	}
+}
+
+func (b *RedisStreamBus) handle(ctx context.Context, channel, group string, msg redis.XMessage, handler ports.EventHandler) {
+	payload, _ := msg.Values["payload"].(string)
+	event := domain.EventMessage{ID: msg.ID, Channel: channel, Payload: json.RawMessage(payload)}
+
+	if err := handler(ctx, event); err != nil {
+		// Left pending: reclaimed once idle for ClaimIdle
+		b.log.Warn("event handler failed", "channel", channel, "group", group, "id", msg.ID, "error", err)
+		return
+	}
+	if err := b.client.XAck(ctx, channel, group, msg.ID).Err(); err != nil && ctx.Err() == nil {
+		b.log.Error("failed to acknowledge event", "channel", channel, "group", group, "id", msg.ID, "error", err)
+	}
+}
+
+// deadLetter copies an entry to "<channel>:dead" for inspection and acknowledges it.
+func (b *RedisStreamBus) deadLetter(ctx context.Context, channel, group, id string) {
+	entries, err := b.client.XRange(ctx, channel, id, id).Result()
+	if err != nil {
+		b.log.Error("failed to read event to dead-letter", "channel", channel, "id", id, "error", err)
+		return
+	}
+
+	// An entry trimmed away meanwhile has nothing left to copy
+	if len(entries) > 0 {
+		err = b.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: channel + ":dead",
+			MaxLen: b.cfg.MaxLen,
+			Approx: true,
+			Values: map[string]interface{}{"payload": entries[0].Values["payload"], "group": group, "id": id},
+		}).Err()
+		if err != nil {
+			b.log.Error("failed to dead-letter event", "channel", channel, "id", id, "error", err)
+			return
+		}
+	}
+
+	if err := b.client.XAck(ctx, channel, group, id).Err(); err != nil {
+		b.log.Error("failed to acknowledge dead-lettered event", "channel", channel, "id", id, "error", err)
+		return
+	}
+	b.log.Warn("event dead-lettered", "channel", channel, "group", group, "id", id)
+}
+
+func (b *RedisStreamBus) ReadEvents(ctx context.Context, positions map[string]string, count int64, block time.Duration) ([]domain.EventMessage, error) {
+	streams := make([]string, 0, 2*len(positions))
+	ids := make([]string, 0, len(positions))
+	for channel, position := range positions {
+		streams = append(streams, channel)
+		ids = append(ids, position)
+	}
+
+	// BLOCK 0 would wait forever
+	res, err := b.client.XRead(ctx, &redis.XReadArgs{
+		Streams: append(streams, ids...),
+		Count:   count,
+		Block:   max(block, time.Millisecond),
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []domain.EventMessage
+	for _, stream := range res {
+		for _, msg := range stream.Messages {
+			payload, _ := msg.Values["payload"].(string)
+			events = append(events, domain.EventMessage{ID: msg.ID, Channel: stream.Stream, Payload: json.RawMessage(payload)})
+		}
+	}
+	return events, nil
+}
+
+func (b *RedisStreamBus) LatestPositions(ctx context.Context, channels []string) (map[string]string, error) {
+	positions := make(map[string]string, len(channels))
+	for _, channel := range channels {
+		last, err := b.client.XRevRangeN(ctx, channel, "+", "-", 1).Result()
+		if err != nil {
+			return nil, err
+		}
+		positions[channel] = "0-0"
+		if len(last) > 0 {
+			positions[channel] = last[0].ID
+		}
+	}
+	return positions, nil
+}
@@ -11,10 +11,14 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
@@ -26,6 +30,7 @@ type RedisAuthRepository struct {
 
 // Ensure interface compliance
 var _ ports.AuthRepository = (*RedisAuthRepository)(nil)
+var _ ports.TenantRepository = (*RedisAuthRepository)(nil)
 
 func NewRedisAuthRepository(client *redis.Client, db *pgxpool.Pool) *RedisAuthRepository {
 	return &RedisAuthRepository{client: client, db: db}
@@ -49,6 +54,17 @@ func (r *RedisAuthRepository) ValidateKey(ctx context.Context, apiKeyHash string
 	return val, true, nil
 }
 
+func (r *RedisAuthRepository) GetKeyAccessLevel(ctx context.Context, apiKeyHash string) (string, error) {
+	// Key format: "auth:apikey:level:{hash}" -> value: "{access_level}"
+	key := fmt.Sprintf("auth:apikey:level:%s", apiKeyHash)
+	val, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		// No level assigned = the holder only gets the public view of foreign passports
+		return "", nil
+	}
+	return val, err
+}
+
 // Warmup loads all active API keys from Postgres into Redis.
 // This should be called on service startup.
 func (r *RedisAuthRepository) Warmup(ctx context.Context) error {
@@ -123,3 +139,54 @@ func (r *RedisAuthRepository) GetTenantName(ctx context.Context, tenantID string
 
 	return orgName, nil
 }
+
+func (r *RedisAuthRepository) GetTenantProfile(ctx context.Context, tenantID string) (*domain.TenantProfile, error) {
+	// 1. Check Redis Cache
+	key := fmt.Sprintf("tenant:profile:%s", tenantID)
+	if val, err := r.client.Get(ctx, key).Result(); err == nil {
+		var profile domain.TenantProfile
+		if json.Unmarshal([]byte(val), &profile) == nil {
+			return &profile, nil
+		}
+	}
+
+	// 2. Fetch from DB
+	// The "tenants" table also carries the economic operator identifiers used in the master envelope (migration 000017)
+	query := `SELECT id, org_name, COALESCE(duns_number, ''), COALESCE(country, '') FROM tenants WHERE id = $1`
+	var profile domain.TenantProfile
+	err := r.db.QueryRow(ctx, query, tenantID).Scan(&profile.ID, &profile.Name, &profile.DUNSNumber, &profile.Country)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("tenant %s: %w", tenantID, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch tenant profile: %w", err)
+	}
+
+	// 3. Cache in Redis (shorter TTL than the name: identifiers get corrected during onboarding)
+	if raw, err := json.Marshal(profile); err == nil {
+		go func() {
+			_ = r.client.Set(context.Background(), key, raw, time.Hour).Err()
+		}()
+	}
+
+	return &profile, nil
+}
+
+func (r *RedisAuthRepository) FindCompanyPrefixOwner(ctx context.Context, gtin string) (string, error) {
+	candidates := domain.CompanyPrefixCandidates(gtin)
+	if len(candidates) == 0 {
+		return "", nil
+	}
+
+	// Prefixes are registered once GS1 membership is verified (migration 000018); not cached, so a
+	// new registration applies at once
+	query := `SELECT tenant_id FROM gs1_company_prefixes WHERE prefix = ANY($1) ORDER BY length(prefix) DESC LIMIT 1`
+	var tenantID string
+	if err := r.db.QueryRow(ctx, query, candidates).Scan(&tenantID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch GS1 company prefix: %w", err)
+	}
+	return tenantID, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AccessRequestRepository struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.AccessRequestRepository = (*AccessRequestRepository)(nil)
+
+func NewAccessRequestRepository(db *pgxpool.Pool) *AccessRequestRepository {
+	return &AccessRequestRepository{db: db}
+}
+
+const accessRequestColumns = `id, passport_id, manufacturer_id, requester_tenant_id, purpose, access_level,
+		       status, created_at, decided_at, COALESCE(decision_note, ''), grant_id`
+
+func (r *AccessRequestRepository) Save(ctx context.Context, req *domain.AccessRequest) error {
+	query := `
+		INSERT INTO access_requests (
+			id, passport_id, manufacturer_id, requester_tenant_id, purpose, access_level,
+			status, created_at, decided_at, decision_note, grant_id
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11
+		)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		req.ID,
+		req.PassportID,
+		req.ManufacturerID,
+		req.RequesterTenantID,
+		req.Purpose,
+		req.AccessLevel,
+		req.Status,
+		req.CreatedAt,
+		req.DecidedAt,
+		req.DecisionNote,
+		req.GrantID,
+	)
+	return mapWriteError(err)
+}
+
+func (r *AccessRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessRequest, error) {
+	query := `SELECT ` + accessRequestColumns + ` FROM access_requests WHERE id = $1`
+
+	req, err := scanAccessRequest(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("access request not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return req, nil
+}
+
+func (r *AccessRequestRepository) FindByManufacturer(ctx context.Context, manufacturerID string, status domain.AccessRequestStatus) ([]*domain.AccessRequest, error) {
+	query := `
+		SELECT ` + accessRequestColumns + `
+		FROM access_requests
+		WHERE manufacturer_id = $1
+		  AND ($2::text = '' OR status = $2::text)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, manufacturerID, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*domain.AccessRequest
+	for rows.Next() {
+		req, err := scanAccessRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, rows.Err()
+}
+
+func (r *AccessRequestRepository) Decide(ctx context.Context, req *domain.AccessRequest) error {
+	// Only a pending request can be decided: two concurrent decisions cannot both win
+	query := `
+		UPDATE access_requests
+		SET status = $2, decided_at = $3, decision_note = NULLIF($4, ''), grant_id = $5
+		WHERE id = $1 AND status = 'PENDING'
+	`
+
+	tag, err := r.db.Exec(ctx, query, req.ID, req.Status, req.DecidedAt, req.DecisionNote, req.GrantID)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: access request already decided", domain.ErrConflict)
+	}
+	return nil
+}
+
+func scanAccessRequest(row pgx.Row) (*domain.AccessRequest, error) {
+	var req domain.AccessRequest
+	err := row.Scan(
+		&req.ID,
+		&req.PassportID,
+		&req.ManufacturerID,
+		&req.RequesterTenantID,
+		&req.Purpose,
+		&req.AccessLevel,
+		&req.Status,
+		&req.CreatedAt,
+		&req.DecidedAt,
+		&req.DecisionNote,
+		&req.GrantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GrantRepository struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.GrantRepository = (*GrantRepository)(nil)
+
+func NewGrantRepository(db *pgxpool.Pool) *GrantRepository {
+	return &GrantRepository{db: db}
+}
+
+const grantColumns = `id, manufacturer_id, grantee_tenant_id, scope, passport_id, COALESCE(product_category, ''),
+		       access_level, expires_at, created_at, revoked_at`
+
+func (r *GrantRepository) Save(ctx context.Context, g *domain.AccessGrant) error {
+	query := `
+		INSERT INTO access_grants (
+			id, manufacturer_id, grantee_tenant_id, scope, passport_id, product_category,
+			access_level, expires_at, created_at, revoked_at
+		) VALUES (
+			$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10
+		)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		g.ID,
+		g.ManufacturerID,
+		g.GranteeTenantID,
+		g.Scope,
+		g.PassportID,
+		g.ProductCategory,
+		g.AccessLevel,
+		g.ExpiresAt,
+		g.CreatedAt,
+		g.RevokedAt,
+	)
+	return mapWriteError(err)
+}
+
+func (r *GrantRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AccessGrant, error) {
+	query := `SELECT ` + grantColumns + ` FROM access_grants WHERE id = $1`
+
+	g, err := scanGrant(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("grant not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return g, nil
+}
+
+func (r *GrantRepository) FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.AccessGrant, error) {
+	query := `SELECT ` + grantColumns + ` FROM access_grants WHERE manufacturer_id = $1 ORDER BY created_at DESC`
+	return r.queryGrants(ctx, query, manufacturerID)
+}
+
+func (r *GrantRepository) FindActiveForPassport(ctx context.Context, granteeTenantID string, passport *domain.Passport, revisions []uuid.UUID, now time.Time) ([]*domain.AccessGrant, error) {
+	query := `
+		SELECT ` + grantColumns + `
+		FROM access_grants
+		WHERE grantee_tenant_id = $1
+		  AND manufacturer_id = $2
+		  AND revoked_at IS NULL
+		  AND expires_at > $5
+		  AND (
+		        scope = 'MANUFACTURER'
+		     OR (scope = 'CATEGORY' AND product_category = $4)
+		     OR (scope = 'PASSPORT' AND passport_id = ANY($3))
+		  )
+	`
+	return r.queryGrants(ctx, query, granteeTenantID, passport.ManufacturerID, revisions, passport.ProductCategory, now)
+}
+
+func (r *GrantRepository) FindActiveForGrantee(ctx context.Context, granteeTenantID string, now time.Time) ([]*domain.AccessGrant, error) {
+	query := `
+		SELECT ` + grantColumns + `
+		FROM access_grants
+		WHERE grantee_tenant_id = $1
+		  AND revoked_at IS NULL
+		  AND expires_at > $2
+	`
+	return r.queryGrants(ctx, query, granteeTenantID, now)
+}
+
+func (r *GrantRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	query := `UPDATE access_grants SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+
+	tag, err := r.db.Exec(ctx, query, id, revokedAt)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("active grant not found: %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *GrantRepository) queryGrants(ctx context.Context, query string, args ...any) ([]*domain.AccessGrant, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var grants []*domain.AccessGrant
+	for rows.Next() {
+		g, err := scanGrant(rows)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, g)
+	}
+	return grants, rows.Err()
+}
+
+func scanGrant(row pgx.Row) (*domain.AccessGrant, error) {
+	var g domain.AccessGrant
+	err := row.Scan(
+		&g.ID,
+		&g.ManufacturerID,
+		&g.GranteeTenantID,
+		&g.Scope,
+		&g.PassportID,
+		&g.ProductCategory,
+		&g.AccessLevel,
+		&g.ExpiresAt,
+		&g.CreatedAt,
+		&g.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
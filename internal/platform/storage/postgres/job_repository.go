@@ -0,0 +1,280 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JobRepository struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.JobRepository = (*JobRepository)(nil)
+
+func NewJobRepository(db *pgxpool.Pool) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `id, type, tenant_id, status, params, COALESCE(error, ''), progress_done, progress_total,
+		       attempts, max_attempts, cancel_requested, run_after, created_at, updated_at, started_at, finished_at`
+
+func (r *JobRepository) Save(ctx context.Context, job *domain.Job, input []byte) error {
+	query := `
+		INSERT INTO jobs (
+			id, type, tenant_id, status, params, input, progress_done, progress_total,
+			attempts, max_attempts, run_after, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		job.ID,
+		job.Type,
+		job.TenantID,
+		job.Status,
+		job.Params,
+		input,
+		job.Progress.Done,
+		job.Progress.Total,
+		job.Attempts,
+		job.MaxAttempts,
+		job.RunAfter,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	return mapWriteError(err)
+}
+
+func (r *JobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1`
+
+	job, err := scanJob(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("job not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return job, nil
+}
+
+func (r *JobRepository) GetInput(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	return r.getBlob(ctx, `SELECT input FROM jobs WHERE id = $1`, id)
+}
+
+func (r *JobRepository) GetResult(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	return r.getBlob(ctx, `SELECT result FROM jobs WHERE id = $1`, id)
+}
+
+func (r *JobRepository) getBlob(ctx context.Context, query string, id uuid.UUID) ([]byte, error) {
+	var data []byte
+	if err := r.db.QueryRow(ctx, query, id).Scan(&data); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("job not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return data, nil
+}
+
+func (r *JobRepository) FindByTenant(ctx context.Context, tenantID string, limit int) ([]*domain.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *JobRepository) Claim(ctx context.Context, workerID string, lease time.Duration) (*domain.Job, error) {
+	// 1. Settle the jobs of vanished workers that must not run again
+	abandon := `
+		UPDATE jobs SET
+			status = CASE WHEN cancel_requested THEN 'CANCELLED' ELSE 'FAILED' END,
+			error = CASE WHEN cancel_requested THEN error ELSE 'worker stopped responding' END,
+			finished_at = now(), updated_at = now(), locked_by = NULL, lease_until = NULL
+		WHERE status = 'RUNNING' AND lease_until < now()
+		  AND (cancel_requested OR attempts >= max_attempts)
+	`
+	if _, err := r.db.Exec(ctx, abandon); err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	// 2. Claim the oldest due job; SKIP LOCKED lets concurrent workers pass each other
+	claim := `
+		UPDATE jobs SET
+			status = 'RUNNING', attempts = attempts + 1, locked_by = $1,
+			lease_until = now() + make_interval(secs => $2),
+			started_at = COALESCE(started_at, now()), updated_at = now()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'QUEUED' AND run_after <= now())
+			   OR (status = 'RUNNING' AND lease_until < now())
+			ORDER BY run_after
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRow(ctx, claim, workerID, lease.Seconds()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return job, nil
+}
+
+func (r *JobRepository) Heartbeat(ctx context.Context, id uuid.UUID, workerID string, lease time.Duration, progress domain.JobProgress) (bool, error) {
+	query := `
+		UPDATE jobs SET
+			lease_until = now() + make_interval(secs => $3),
+			progress_done = $4, progress_total = $5, updated_at = now()
+		WHERE id = $1 AND locked_by = $2 AND status = 'RUNNING'
+		RETURNING cancel_requested
+	`
+
+	var cancelRequested bool
+	err := r.db.QueryRow(ctx, query, id, workerID, lease.Seconds(), progress.Done, progress.Total).Scan(&cancelRequested)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("%w: job lease lost", domain.ErrConflict)
+		}
+		return false, fmt.Errorf("database error: %w", err)
+	}
+	return cancelRequested, nil
+}
+
+func (r *JobRepository) Finish(ctx context.Context, job *domain.Job, workerID string, result []byte) error {
+	// The input is only kept while the job may run again
+	query := `
+		UPDATE jobs SET
+			status = $3::text, error = NULLIF($4, ''), result = $5, run_after = $6,
+			progress_done = $7, progress_total = $8, finished_at = $9,
+			locked_by = NULL, lease_until = NULL, updated_at = now(),
+			input = CASE WHEN $3::text = 'SUCCEEDED' THEN NULL ELSE input END
+		WHERE id = $1 AND locked_by = $2 AND status = 'RUNNING'
+	`
+
+	tag, err := r.db.Exec(ctx, query,
+		job.ID,
+		workerID,
+		string(job.Status),
+		job.Error,
+		result,
+		job.RunAfter,
+		job.Progress.Done,
+		job.Progress.Total,
+		job.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: job lease lost", domain.ErrConflict)
+	}
+	return nil
+}
+
+func (r *JobRepository) RequestCancel(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	// A queued job is cancelled on the spot; a running one is flagged for its worker
+	query := `
+		UPDATE jobs SET
+			status = CASE WHEN status = 'QUEUED' THEN 'CANCELLED' ELSE status END,
+			finished_at = CASE WHEN status = 'QUEUED' THEN now() ELSE finished_at END,
+			cancel_requested = TRUE, updated_at = now()
+		WHERE id = $1 AND status IN ('QUEUED', 'RUNNING')
+		RETURNING ` + jobColumns
+
+	return r.transition(ctx, query, id, "job already finished")
+}
+
+func (r *JobRepository) Requeue(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	query := `
+		UPDATE jobs SET
+			status = 'QUEUED', attempts = 0, error = NULL, result = NULL, cancel_requested = FALSE,
+			progress_done = 0, progress_total = 0, run_after = now(),
+			started_at = NULL, finished_at = NULL, updated_at = now()
+		WHERE id = $1 AND status IN ('FAILED', 'CANCELLED')
+		RETURNING ` + jobColumns
+
+	return r.transition(ctx, query, id, "only failed or cancelled jobs can be retried")
+}
+
+// transition runs a conditional status update; no row means the job is missing or in the wrong state.
+func (r *JobRepository) transition(ctx context.Context, query string, id uuid.UUID, conflict string) (*domain.Job, error) {
+	job, err := scanJob(r.db.QueryRow(ctx, query, id))
+	if err == nil {
+		return job, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrConflict, conflict)
+}
+
+func scanJob(row pgx.Row) (*domain.Job, error) {
+	var job domain.Job
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&job.TenantID,
+		&job.Status,
+		&job.Params,
+		&job.Error,
+		&job.Progress.Done,
+		&job.Progress.Total,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.CancelRequested,
+		&job.RunAfter,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
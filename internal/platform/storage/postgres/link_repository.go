@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LinkRepository struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.LinkRepository = (*LinkRepository)(nil)
+
+func NewLinkRepository(db *pgxpool.Pool) *LinkRepository {
+	return &LinkRepository{db: db}
+}
+
+const linkColumns = `id, manufacturer_id, passport_id, link_type, href, title, language, media_type, created_at`
+
+func (r *LinkRepository) Save(ctx context.Context, l *domain.PassportLink) error {
+	query := `
+		INSERT INTO passport_links (` + linkColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		l.ID,
+		l.ManufacturerID,
+		l.PassportID,
+		l.LinkType,
+		l.Href,
+		l.Title,
+		l.Language,
+		l.MediaType,
+		l.CreatedAt,
+	)
+	return mapWriteError(err)
+}
+
+func (r *LinkRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PassportLink, error) {
+	query := `SELECT ` + linkColumns + ` FROM passport_links WHERE id = $1`
+
+	l, err := scanLink(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("link not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return l, nil
+}
+
+func (r *LinkRepository) FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.PassportLink, error) {
+	query := `SELECT ` + linkColumns + ` FROM passport_links WHERE manufacturer_id = $1 ORDER BY created_at, id`
+	return r.queryLinks(ctx, query, manufacturerID)
+}
+
+func (r *LinkRepository) FindForPassports(ctx context.Context, manufacturerID string, passportIDs []uuid.UUID) ([]*domain.PassportLink, error) {
+	query := `
+		SELECT ` + linkColumns + `
+		FROM passport_links
+		WHERE manufacturer_id = $1
+		  AND (passport_id IS NULL OR passport_id = ANY($2))
+		ORDER BY created_at, id
+	`
+	return r.queryLinks(ctx, query, manufacturerID, passportIDs)
+}
+
+func (r *LinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM passport_links WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("link not found: %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *LinkRepository) queryLinks(ctx context.Context, query string, args ...any) ([]*domain.PassportLink, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*domain.PassportLink
+	for rows.Next() {
+		l, err := scanLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+func scanLink(row pgx.Row) (*domain.PassportLink, error) {
+	var l domain.PassportLink
+	err := row.Scan(
+		&l.ID,
+		&l.ManufacturerID,
+		&l.PassportID,
+		&l.LinkType,
+		&l.Href,
+		&l.Title,
+		&l.Language,
+		&l.MediaType,
+		&l.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
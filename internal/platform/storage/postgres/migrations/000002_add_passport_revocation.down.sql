@@ -0,0 +1,3 @@
+ALTER TABLE passports
+    DROP COLUMN IF EXISTS revocation_reason,
+    DROP COLUMN IF EXISTS revoked_at;
@@ -0,0 +1,3 @@
+ALTER TABLE passports
+    ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS revocation_reason TEXT;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_passports_previous_version;
+
+ALTER TABLE passports
+    DROP COLUMN IF EXISTS superseded_by,
+    DROP COLUMN IF EXISTS previous_version_id,
+    DROP COLUMN IF EXISTS version;
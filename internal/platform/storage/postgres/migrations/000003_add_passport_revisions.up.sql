@@ -0,0 +1,7 @@
+ALTER TABLE passports
+    ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS previous_version_id UUID REFERENCES passports (id),
+    ADD COLUMN IF NOT EXISTS superseded_by UUID REFERENCES passports (id);
+
+-- A published passport can only ever have one successor: the chain stays linear.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_passports_previous_version ON passports (previous_version_id) WHERE previous_version_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE passports DROP COLUMN IF EXISTS schema_version;
+
+DROP TABLE IF EXISTS category_schemas;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS category_schemas (
+    product_category VARCHAR(50) NOT NULL,
+    version VARCHAR(32) NOT NULL,
+    schema JSONB NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    activated_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (product_category, version)
+);
+
+-- At most one active version per category.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_category_schemas_active ON category_schemas (product_category) WHERE active;
+
+ALTER TABLE passports ADD COLUMN IF NOT EXISTS schema_version VARCHAR(32);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS access_grants (
+    id UUID PRIMARY KEY,
+    manufacturer_id VARCHAR(100) NOT NULL,
+    grantee_tenant_id VARCHAR(100) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    passport_id UUID REFERENCES passports (id),
+    product_category VARCHAR(50),
+    access_level VARCHAR(50) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Lookup on every authenticated resolve: "which grants does this viewer hold from this manufacturer?"
+CREATE INDEX IF NOT EXISTS idx_access_grants_grantee ON access_grants (grantee_tenant_id, manufacturer_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_access_grants_manufacturer ON access_grants (manufacturer_id, created_at DESC);
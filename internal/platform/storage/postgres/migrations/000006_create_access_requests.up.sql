@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS access_requests (
+    id UUID PRIMARY KEY,
+    passport_id UUID NOT NULL REFERENCES passports (id),
+    manufacturer_id VARCHAR(100) NOT NULL,
+    requester_tenant_id VARCHAR(100) NOT NULL,
+    purpose TEXT NOT NULL,
+    access_level VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    decision_note TEXT,
+    grant_id UUID REFERENCES access_grants (id)
+);
+
+-- One open request per requester and passport.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_access_requests_pending ON access_requests (passport_id, requester_tenant_id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_access_requests_manufacturer ON access_requests (manufacturer_id, status, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE passports
+    DROP COLUMN IF EXISTS manufacturer_country,
+    DROP COLUMN IF EXISTS manufacturer_duns;
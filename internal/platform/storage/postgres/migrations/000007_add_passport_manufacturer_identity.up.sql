@@ -0,0 +1,3 @@
+ALTER TABLE passports
+    ADD COLUMN IF NOT EXISTS manufacturer_duns VARCHAR(9),
+    ADD COLUMN IF NOT EXISTS manufacturer_country CHAR(2);
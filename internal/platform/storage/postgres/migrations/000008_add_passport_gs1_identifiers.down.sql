@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_passports_gtin;
+DROP INDEX IF EXISTS idx_passports_gtin_batch;
+DROP INDEX IF EXISTS idx_passports_gtin_serial;
+
+ALTER TABLE passports
+    DROP COLUMN IF EXISTS serial_number,
+    DROP COLUMN IF EXISTS batch_number,
+    DROP COLUMN IF EXISTS gtin;
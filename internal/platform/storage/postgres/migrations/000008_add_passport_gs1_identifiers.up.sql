@@ -0,0 +1,15 @@
+ALTER TABLE passports
+    ADD COLUMN IF NOT EXISTS gtin VARCHAR(14),
+    ADD COLUMN IF NOT EXISTS batch_number VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS serial_number VARCHAR(20);
+
+-- Identifiers are registered by the first revision of a chain; later revisions inherit them.
+-- A serial number is unique per GTIN, whatever the batch.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_passports_gtin_serial ON passports (manufacturer_id, gtin, serial_number)
+    WHERE serial_number IS NOT NULL AND previous_version_id IS NULL;
+
+-- One model-level (no batch) or batch-level passport per GTIN and batch.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_passports_gtin_batch ON passports (manufacturer_id, gtin, COALESCE(batch_number, ''))
+    WHERE gtin IS NOT NULL AND serial_number IS NULL AND previous_version_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_passports_gtin ON passports (gtin) WHERE gtin IS NOT NULL;
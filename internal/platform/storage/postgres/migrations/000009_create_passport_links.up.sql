@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS passport_links (
+    id UUID PRIMARY KEY,
+    manufacturer_id VARCHAR(100) NOT NULL,
+    passport_id UUID REFERENCES passports (id),
+    link_type VARCHAR(100) NOT NULL,
+    href TEXT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    language VARCHAR(35) NOT NULL DEFAULT '',
+    media_type VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- One destination per link type, language and media type, at each level (manufacturer-wide or passport).
+CREATE UNIQUE INDEX IF NOT EXISTS idx_passport_links_unique ON passport_links
+    (manufacturer_id, COALESCE(passport_id, '00000000-0000-0000-0000-000000000000'), link_type, language, media_type);
+
+-- Lookup on every linkType resolve: "which links apply to this passport chain?"
+CREATE INDEX IF NOT EXISTS idx_passport_links_passport ON passport_links (passport_id) WHERE passport_id IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_passports_parent;
+
+ALTER TABLE passports
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS level;
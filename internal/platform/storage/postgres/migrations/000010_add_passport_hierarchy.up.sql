@@ -0,0 +1,6 @@
+ALTER TABLE passports
+    ADD COLUMN IF NOT EXISTS level VARCHAR(10) NOT NULL DEFAULT 'ITEM',
+    ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES passports (id);
+
+-- "Which items inherit from this model/batch?"
+CREATE INDEX IF NOT EXISTS idx_passports_parent ON passports (parent_id) WHERE parent_id IS NOT NULL;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    tenant_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    input BYTEA,
+    result JSONB,
+    error TEXT,
+    progress_done INTEGER NOT NULL DEFAULT 0,
+    progress_total INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_by VARCHAR(100),
+    lease_until TIMESTAMP WITH TIME ZONE,
+    run_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Worker poll: "oldest queued job that is due"
+CREATE INDEX IF NOT EXISTS idx_jobs_queued ON jobs (run_after) WHERE status = 'QUEUED';
+
+-- Lost workers: running jobs whose lease expired
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs (lease_until) WHERE status = 'RUNNING';
+
+CREATE INDEX IF NOT EXISTS idx_jobs_tenant ON jobs (tenant_id, created_at DESC);
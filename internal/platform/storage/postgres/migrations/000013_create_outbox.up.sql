@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    seq BIGSERIAL PRIMARY KEY, -- Commit order of the events
+    id UUID NOT NULL UNIQUE,
+    passport_id UUID NOT NULL,
+    channel VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Relay: "is there an older event of the same passport still waiting?"
+CREATE INDEX IF NOT EXISTS idx_outbox_events_passport ON outbox_events (passport_id, seq);
+
+-- Relay poll: "events that are due"
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events (next_attempt_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_passports_manufacturer_category;
+DROP INDEX IF EXISTS idx_passports_manufacturer_status;
+DROP INDEX IF EXISTS idx_passports_manufacturer_serial;
+DROP INDEX IF EXISTS idx_passports_manufacturer_published;
+DROP INDEX IF EXISTS idx_passports_manufacturer_updated;
+DROP INDEX IF EXISTS idx_passports_manufacturer_created;
@@ -0,0 +1,10 @@
+-- Keyset pagination of GET /passports: one index per sort order, within the manufacturer.
+-- The expressions must match the ones of the list query (NULLs sort as the lowest key).
+CREATE INDEX IF NOT EXISTS idx_passports_manufacturer_created ON passports (manufacturer_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_passports_manufacturer_updated ON passports (manufacturer_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_passports_manufacturer_published ON passports (manufacturer_id, COALESCE(published_at, '-infinity'::timestamptz), id);
+CREATE INDEX IF NOT EXISTS idx_passports_manufacturer_serial ON passports (manufacturer_id, COALESCE(serial_number, ''), id);
+
+-- Common filters, newest first: "drafts of this tenant", "batteries of this tenant"
+CREATE INDEX IF NOT EXISTS idx_passports_manufacturer_status ON passports (manufacturer_id, status, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_passports_manufacturer_category ON passports (manufacturer_id, product_category, created_at, id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS passport_search_documents;
@@ -0,0 +1,11 @@
+-- Full-text search: one document per passport and access rank, holding the schema-selected text
+-- that viewers of that rank can read (weights A to D set by the schema's "search" annotations).
+-- Passports without search fields have no rows.
+CREATE TABLE IF NOT EXISTS passport_search_documents (
+    passport_id UUID NOT NULL REFERENCES passports (id) ON DELETE CASCADE,
+    access_rank INTEGER NOT NULL,
+    document TSVECTOR NOT NULL,
+    PRIMARY KEY (passport_id, access_rank)
+);
+
+CREATE INDEX IF NOT EXISTS idx_passport_search_documents_document ON passport_search_documents USING GIN (document);
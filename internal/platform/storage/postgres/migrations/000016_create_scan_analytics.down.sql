@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scan_counts_tenant_daily;
+DROP TABLE IF EXISTS scan_counts_passport_daily;
+DROP TABLE IF EXISTS scan_events;
@@ -0,0 +1,35 @@
+-- Scan log: resolver hits waiting to be rolled up (no IP addresses nor raw headers)
+CREATE TABLE IF NOT EXISTS scan_events (
+    seq BIGSERIAL PRIMARY KEY, -- Arrival order: the rollup takes the oldest first
+    passport_id UUID NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    agent_class VARCHAR(20) NOT NULL,
+    country VARCHAR(2) NOT NULL DEFAULT '', -- ISO 3166-1 alpha-2, '' when unknown
+    referrer VARCHAR(255) NOT NULL DEFAULT '', -- Host only
+    access_tier VARCHAR(50) NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Daily scans of each passport (UTC days)
+CREATE TABLE IF NOT EXISTS scan_counts_passport_daily (
+    passport_id UUID NOT NULL,
+    day DATE NOT NULL,
+    manufacturer_id VARCHAR(100) NOT NULL,
+    scans BIGINT NOT NULL,
+    PRIMARY KEY (passport_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scan_counts_passport_daily_manufacturer ON scan_counts_passport_daily (manufacturer_id, day);
+
+-- Daily scans of each tenant, per combination of dimensions (UTC days)
+CREATE TABLE IF NOT EXISTS scan_counts_tenant_daily (
+    manufacturer_id VARCHAR(100) NOT NULL,
+    day DATE NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    agent_class VARCHAR(20) NOT NULL,
+    country VARCHAR(2) NOT NULL,
+    referrer VARCHAR(255) NOT NULL,
+    access_tier VARCHAR(50) NOT NULL,
+    scans BIGINT NOT NULL,
+    PRIMARY KEY (manufacturer_id, day, source, agent_class, country, referrer, access_tier)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS country,
+    DROP COLUMN IF EXISTS duns_number;
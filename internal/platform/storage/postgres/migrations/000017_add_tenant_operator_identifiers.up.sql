@@ -0,0 +1,10 @@
+-- Tenants are provisioned by the auth service; create the table for standalone setups
+CREATE TABLE IF NOT EXISTS tenants (
+    id VARCHAR(100) PRIMARY KEY,
+    org_name VARCHAR(255) NOT NULL
+);
+
+-- Economic operator identifiers, stamped on the master envelope at publication
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS duns_number VARCHAR(9),
+    ADD COLUMN IF NOT EXISTS country CHAR(2);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS gs1_company_prefixes;
@@ -0,0 +1,9 @@
+-- GS1 company prefixes verified as belonging to a tenant. GTINs under a registered prefix can
+-- only be used by its tenant, and resolve to its passports when several tenants used them.
+CREATE TABLE IF NOT EXISTS gs1_company_prefixes (
+    prefix VARCHAR(12) PRIMARY KEY CHECK (prefix ~ '^[0-9]{4,12}$'),
+    tenant_id VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gs1_company_prefixes_tenant ON gs1_company_prefixes (tenant_id);
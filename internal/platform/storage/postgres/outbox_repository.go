@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.OutboxRepository = (*OutboxRepository)(nil)
+
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+const outboxColumns = `id, passport_id, channel, payload, attempts, next_attempt_at, COALESCE(last_error, ''), created_at`
+
+func (r *OutboxRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	// An event waits while an older one of its passport is still in the outbox (claimed, or
+	// failing): relays running in parallel can never overtake each other on a passport
+	query := `
+		UPDATE outbox_events SET next_attempt_at = now() + make_interval(secs => $2)
+		WHERE seq IN (
+			SELECT seq FROM outbox_events e
+			WHERE next_attempt_at <= now()
+			  AND NOT EXISTS (
+				SELECT 1 FROM outbox_events older
+				WHERE older.passport_id = e.passport_id AND older.seq < e.seq
+			  )
+			ORDER BY seq
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+
+	rows, err := r.db.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.OutboxEvent
+	for rows.Next() {
+		var e domain.OutboxEvent
+		err := rows.Scan(&e.ID, &e.PassportID, &e.Channel, &e.Payload, &e.Attempts, &e.NextAttemptAt, &e.LastError, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+func (r *OutboxRepository) MarkRelayed(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.db.Exec(ctx, `DELETE FROM outbox_events WHERE id = ANY($1)`, ids); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) RecordFailure(ctx context.Context, e *domain.OutboxEvent) error {
+	query := `UPDATE outbox_events SET attempts = $2, next_attempt_at = $3, last_error = NULLIF($4, '') WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, e.ID, e.Attempts, e.NextAttemptAt, e.LastError); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
+// withOutbox runs a passport write in a transaction that also stores the passports' recorded
+// events in the outbox (and their pending search documents), then clears them once committed.
+// The events are written after the passport rows, whose locks order concurrent changes of a
+// passport (and so their seq).
+func (r *PostgresRepository) withOutbox(ctx context.Context, passports []*domain.Passport, write func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := write(tx); err != nil {
+		return err
+	}
+	if err := writeSearchDocuments(ctx, tx, passports); err != nil {
+		return err
+	}
+
+	var events []domain.OutboxEvent
+	for _, p := range passports {
+		events = append(events, p.Events...)
+	}
+	if len(events) > 0 {
+		columns := []string{"id", "passport_id", "channel", "payload", "attempts", "next_attempt_at", "created_at"}
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"outbox_events"}, columns,
+			pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+				e := events[i]
+				return []any{e.ID, e.PassportID, e.Channel, []byte(e.Payload), e.Attempts, e.NextAttemptAt, e.CreatedAt}, nil
+			}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to write outbox: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	for _, p := range passports {
+		p.Events = nil
+		p.SearchDocuments = nil
+	}
+	return nil
+}
@@ -13,12 +13,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/core/ports"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -37,9 +39,14 @@ func (r *PostgresRepository) Save(ctx context.Context, p *domain.Passport) error
 	query := `
 		INSERT INTO passports (
 			id, product_category, status, manufacturer_id, manufacturer_name, 
-			attributes, created_at, updated_at, published_at, immutability_hash, storage_location
+			attributes, created_at, updated_at, published_at, immutability_hash, storage_location,
+			revoked_at, revocation_reason, version, previous_version_id, schema_version,
+			manufacturer_duns, manufacturer_country, gtin, batch_number, serial_number,
+			level, parent_id
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''),
+			NULLIF($17, ''), NULLIF($18, ''), NULLIF($19, ''), NULLIF($20, ''), NULLIF($21, ''),
+			$22, $23
 		)
 		ON CONFLICT (id) DO UPDATE SET
 			status = EXCLUDED.status,
@@ -47,7 +54,12 @@ func (r *PostgresRepository) Save(ctx context.Context, p *domain.Passport) error
 			updated_at = EXCLUDED.updated_at,
 			published_at = EXCLUDED.published_at,
 			immutability_hash = EXCLUDED.immutability_hash,
-			storage_location = EXCLUDED.storage_location;
+			storage_location = EXCLUDED.storage_location,
+			revoked_at = EXCLUDED.revoked_at,
+			revocation_reason = EXCLUDED.revocation_reason,
+			schema_version = EXCLUDED.schema_version,
+			manufacturer_duns = EXCLUDED.manufacturer_duns,
+			manufacturer_country = EXCLUDED.manufacturer_country;
 	`
 
 	// Handle nullable PublishedAt
@@ -55,24 +67,75 @@ func (r *PostgresRepository) Save(ctx context.Context, p *domain.Passport) error
 	if p.PublishedAt != nil {
 		publishedAt = p.PublishedAt
 	}
+	revokedAt, revocationReason := revocationColumns(p)
 
-	_, err := r.db.Exec(ctx, query,
-		p.ID,
-		p.ProductCategory,
-		p.Status,
-		p.ManufacturerID,
-		p.ManufacturerName,
-		p.Attributes,
-		p.CreatedAt,
-		p.UpdatedAt,
-		publishedAt,
-		p.ImmutabilityHash,
-		p.StorageLocation,
-	)
-	return err
+	return r.withOutbox(ctx, []*domain.Passport{p}, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query,
+			p.ID,
+			p.ProductCategory,
+			p.Status,
+			p.ManufacturerID,
+			p.ManufacturerName,
+			p.Attributes,
+			p.CreatedAt,
+			p.UpdatedAt,
+			publishedAt,
+			p.ImmutabilityHash,
+			p.StorageLocation,
+			revokedAt,
+			revocationReason,
+			versionOrDefault(p.Version),
+			p.PreviousVersionID,
+			p.SchemaVersion,
+			p.ManufacturerDUNS,
+			p.ManufacturerCountry,
+			p.GTIN,
+			p.BatchNumber,
+			p.SerialNumber,
+			levelOrDefault(p.Level),
+			p.ParentID,
+		)
+		return mapWriteError(err)
+	})
 }
 
-func (r *PostgresRepository) Update(ctx context.Context, p *domain.Passport) error {
+// SaveBatch streams new passports with COPY. Lifecycle columns (publication, revocation,
+// revisions) keep their defaults: imported passports are always first-version drafts.
+func (r *PostgresRepository) SaveBatch(ctx context.Context, passports []*domain.Passport) error {
+	columns := []string{
+		"id", "product_category", "status", "manufacturer_id", "manufacturer_name",
+		"attributes", "created_at", "updated_at", "version", "schema_version",
+		"gtin", "batch_number", "serial_number", "level", "parent_id",
+	}
+
+	return r.withOutbox(ctx, passports, func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"passports"}, columns,
+			pgx.CopyFromSlice(len(passports), func(i int) ([]any, error) {
+				p := passports[i]
+				return []any{
+					p.ID,
+					string(p.ProductCategory),
+					string(p.Status),
+					p.ManufacturerID,
+					p.ManufacturerName,
+					[]byte(p.Attributes),
+					p.CreatedAt,
+					p.UpdatedAt,
+					versionOrDefault(p.Version),
+					nullIfEmpty(p.SchemaVersion),
+					nullIfEmpty(p.GTIN),
+					nullIfEmpty(p.BatchNumber),
+					nullIfEmpty(p.SerialNumber),
+					string(levelOrDefault(p.Level)),
+					p.ParentID,
+				}, nil
+			}),
+		)
+		return mapWriteError(err)
+	})
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, p *domain.Passport, expected domain.PassportStatus) error {
 	query := `
 		UPDATE passports SET
 			status = $2,
@@ -80,32 +143,60 @@ func (r *PostgresRepository) Update(ctx context.Context, p *domain.Passport) err
 			published_at = $4,
 			storage_location = $5,
 			updated_at = $6,
-			attributes = $7
-		WHERE id = $1
+			attributes = $7,
+			revoked_at = $8,
+			revocation_reason = $9,
+			schema_version = NULLIF($10, ''),
+			manufacturer_duns = NULLIF($11, ''),
+			manufacturer_country = NULLIF($12, '')
+		WHERE id = $1 AND status = $13
 	`
 
-	_, err := r.db.Exec(ctx, query,
-		p.ID,
-		p.Status,
-		p.ImmutabilityHash,
-		p.PublishedAt,
-		p.StorageLocation,
-		time.Now(),
-		p.Attributes,
-	)
-	return err
+	revokedAt, revocationReason := revocationColumns(p)
+
+	return r.withOutbox(ctx, []*domain.Passport{p}, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query,
+			p.ID,
+			p.Status,
+			p.ImmutabilityHash,
+			p.PublishedAt,
+			p.StorageLocation,
+			time.Now(),
+			p.Attributes,
+			revokedAt,
+			revocationReason,
+			p.SchemaVersion,
+			p.ManufacturerDUNS,
+			p.ManufacturerCountry,
+			expected,
+		)
+		if err != nil {
+			return fmt.Errorf("database error: %w", err)
+		}
+		// Published, revoked or replaced by another revision since it was read
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: passport %s is no longer %s", domain.ErrConflict, p.ID, strings.ToLower(string(expected)))
+		}
+		return nil
+	})
 }
 
 func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Passport, error) {
 	query := `
 		SELECT id, product_category, status, manufacturer_id, manufacturer_name, 
-		       attributes, created_at, updated_at, published_at, immutability_hash
+		       attributes, created_at, updated_at, published_at, immutability_hash,
+		       COALESCE(storage_location, ''), revoked_at, revocation_reason,
+		       version, previous_version_id, superseded_by, COALESCE(schema_version, ''),
+		       COALESCE(manufacturer_duns, ''), COALESCE(manufacturer_country, ''),
+		       COALESCE(gtin, ''), COALESCE(batch_number, ''), COALESCE(serial_number, ''),
+		       level, parent_id
 		FROM passports
 		WHERE id = $1
 	`
 
 	var p domain.Passport
-	var publishedAt *time.Time
+	var publishedAt, revokedAt *time.Time
+	var revocationReason *string
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&p.ID,
@@ -118,16 +209,36 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 		&p.UpdatedAt,
 		&publishedAt,
 		&p.ImmutabilityHash,
+		&p.StorageLocation,
+		&revokedAt,
+		&revocationReason,
+		&p.Version,
+		&p.PreviousVersionID,
+		&p.SupersededByID,
+		&p.SchemaVersion,
+		&p.ManufacturerDUNS,
+		&p.ManufacturerCountry,
+		&p.GTIN,
+		&p.BatchNumber,
+		&p.SerialNumber,
+		&p.Level,
+		&p.ParentID,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("passport not found: %w", err)
+			return nil, fmt.Errorf("passport not found: %w", domain.ErrNotFound)
 		}
 		return nil, fmt.Errorf("database error: %w", err)
 	}
 
 	p.PublishedAt = publishedAt
+	if revokedAt != nil {
+		p.Revocation = &domain.Revocation{RevokedAt: *revokedAt}
+		if revocationReason != nil {
+			p.Revocation.Reason = *revocationReason
+		}
+	}
 	return &p, nil
 }
 
@@ -158,17 +269,82 @@ func (r *PostgresRepository) FindByCategory(ctx context.Context, category domain
 	return passports, nil
 }
 
-func (r *PostgresRepository) FindByManufacturer(ctx context.Context, manufacturerID string) ([]*domain.Passport, error) {
+// passportSortKeys are the sort expressions of the passport list, as indexed by migration 000014
+// (with the type of their keys, see domain.PassportSort.Key).
+var passportSortKeys = map[domain.PassportSort]struct{ expr, cast string }{
+	domain.SortCreatedAt:    {"created_at", "timestamptz"},
+	domain.SortUpdatedAt:    {"updated_at", "timestamptz"},
+	domain.SortPublishedAt:  {"COALESCE(published_at, '-infinity'::timestamptz)", "timestamptz"},
+	domain.SortSerialNumber: {"COALESCE(serial_number, '')", "text"},
+}
+
+func (r *PostgresRepository) FindByManufacturer(ctx context.Context, manufacturerID string, q domain.PassportQuery) ([]*domain.Passport, error) {
+	sort, ok := passportSortKeys[q.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort %q", domain.ErrInvalidInput, q.Sort)
+	}
+
+	// 1. Filters (every value is a parameter)
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where := []string{"manufacturer_id = " + arg(manufacturerID)}
+	f := q.Filter
+	if f.Status != "" {
+		where = append(where, "status = "+arg(f.Status))
+	}
+	if f.ProductCategory != "" {
+		where = append(where, "product_category = "+arg(f.ProductCategory))
+	}
+	if f.CreatedFrom != nil {
+		where = append(where, "created_at >= "+arg(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		where = append(where, "created_at < "+arg(*f.CreatedTo))
+	}
+	if f.PublishedFrom != nil {
+		where = append(where, "published_at >= "+arg(*f.PublishedFrom))
+	}
+	if f.PublishedTo != nil {
+		where = append(where, "published_at < "+arg(*f.PublishedTo))
+	}
+	if f.SerialNumber != "" {
+		where = append(where, "COALESCE(serial_number, '') = "+arg(f.SerialNumber)) // Served by the serial number sort index
+	}
+	if f.Attributes != nil {
+		condition, err := searchCondition(f.Attributes, arg)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, condition)
+	}
+
+	// 2. Keyset: the rows after the cursor, in the sort order
+	direction, after := "ASC", ">"
+	if q.Descending {
+		direction, after = "DESC", "<"
+	}
+	if q.After != nil {
+		where = append(where, fmt.Sprintf("(%s, id) %s (%s::%s, %s)", sort.expr, after, arg(q.After.Key), sort.cast, arg(q.After.ID)))
+	}
+	limit := arg(q.Limit)
+
 	query := `
-		SELECT id, product_category, status, manufacturer_id, manufacturer_name, attributes, created_at, updated_at, published_at
+		SELECT id, product_category, status, manufacturer_id, manufacturer_name, attributes, created_at, updated_at, published_at,
+		       version, previous_version_id, superseded_by, COALESCE(schema_version, ''),
+		       COALESCE(manufacturer_duns, ''), COALESCE(manufacturer_country, ''),
+		       COALESCE(gtin, ''), COALESCE(batch_number, ''), COALESCE(serial_number, ''),
+		       level, parent_id
 		FROM passports
-		WHERE manufacturer_id = $1
-		ORDER BY created_at DESC
-	`
+		WHERE ` + strings.Join(where, " AND ") + fmt.Sprintf(`
+		ORDER BY %s %s, id %s
+		LIMIT %s`, sort.expr, direction, direction, limit)
 
-	rows, err := r.db.Query(ctx, query, manufacturerID)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("database error: %w", err)
 	}
 	defer rows.Close()
 
@@ -176,12 +352,253 @@ func (r *PostgresRepository) FindByManufacturer(ctx context.Context, manufacture
 	for rows.Next() {
 		var p domain.Passport
 		var publishedAt *time.Time
-		if err := rows.Scan(&p.ID, &p.ProductCategory, &p.Status, &p.ManufacturerID, &p.ManufacturerName, &p.Attributes, &p.CreatedAt, &p.UpdatedAt, &publishedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.ProductCategory, &p.Status, &p.ManufacturerID, &p.ManufacturerName, &p.Attributes, &p.CreatedAt, &p.UpdatedAt, &publishedAt,
+			&p.Version, &p.PreviousVersionID, &p.SupersededByID, &p.SchemaVersion, &p.ManufacturerDUNS, &p.ManufacturerCountry,
+			&p.GTIN, &p.BatchNumber, &p.SerialNumber, &p.Level, &p.ParentID); err != nil {
 			return nil, err
 		}
 		p.PublishedAt = publishedAt
 		passports = append(passports, &p)
 	}
 
-	return passports, nil
+	return passports, rows.Err()
+}
+
+func (r *PostgresRepository) FindDrafts(ctx context.Context, manufacturerID string, category domain.ProductCategory, createdBefore *time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM passports
+		WHERE manufacturer_id = $1 AND status = $2
+		  AND ($3::text = '' OR product_category = $3)
+		  AND ($4::timestamptz IS NULL OR created_at < $4)
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, manufacturerID, domain.StatusDraft, string(category), createdBefore)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *PostgresRepository) PublishBatch(ctx context.Context, passports []*domain.Passport) error {
+	if len(passports) == 0 {
+		return nil
+	}
+
+	publish := `
+		UPDATE passports SET
+			status = $2,
+			immutability_hash = $3,
+			published_at = $4,
+			storage_location = $5,
+			manufacturer_duns = NULLIF($6, ''),
+			manufacturer_country = NULLIF($7, ''),
+			updated_at = $8
+		WHERE id = $1 AND status = $9
+	`
+	supersede := `
+		UPDATE passports SET
+			status = $3,
+			superseded_by = $2,
+			updated_at = $4
+		WHERE id = $1 AND status = $5
+	`
+
+	return r.withOutbox(ctx, passports, func(tx pgx.Tx) error {
+		// 1. Queue one update per passport (plus the supersession of its predecessor)
+		now := time.Now()
+		batch := &pgx.Batch{}
+		for _, p := range passports {
+			batch.Queue(publish, p.ID, p.Status, p.ImmutabilityHash, p.PublishedAt, p.StorageLocation,
+				p.ManufacturerDUNS, p.ManufacturerCountry, now, domain.StatusDraft)
+			if p.PreviousVersionID != nil {
+				batch.Queue(supersede, *p.PreviousVersionID, p.ID, domain.StatusSuperseded, now, domain.StatusPublished)
+			}
+		}
+
+		// 2. Send them in one round trip; a passport that left DRAFT, or whose predecessor left
+		// PUBLISHED (revoked, or replaced by another revision) meanwhile, fails the batch
+		results := tx.SendBatch(ctx, batch)
+		for _, p := range passports {
+			tag, err := results.Exec()
+			if err != nil {
+				results.Close()
+				return fmt.Errorf("database error: %w", err)
+			}
+			if tag.RowsAffected() == 0 {
+				results.Close()
+				return fmt.Errorf("%w: passport %s is no longer a draft", domain.ErrConflict, p.ID)
+			}
+			if p.PreviousVersionID != nil {
+				tag, err := results.Exec()
+				if err != nil {
+					results.Close()
+					return fmt.Errorf("database error: %w", err)
+				}
+				if tag.RowsAffected() == 0 {
+					results.Close()
+					return fmt.Errorf("%w: the previous revision of passport %s is no longer published", domain.ErrConflict, p.ID)
+				}
+			}
+		}
+		if err := results.Close(); err != nil {
+			return fmt.Errorf("database error: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) FindVersionHistory(ctx context.Context, id uuid.UUID) ([]domain.PassportVersion, error) {
+	// 1. Walk back to the first revision, 2. walk forward from it to the newest one.
+	query := `
+		WITH RECURSIVE back AS (
+			SELECT id, previous_version_id, 0 AS depth
+			FROM passports
+			WHERE id = $1
+			UNION ALL
+			SELECT p.id, p.previous_version_id, b.depth + 1
+			FROM passports p
+			JOIN back b ON p.id = b.previous_version_id
+			WHERE b.depth < $2
+		),
+		root AS (
+			SELECT id FROM back ORDER BY depth DESC LIMIT 1
+		),
+		fwd AS (
+			SELECT p.id, 0 AS depth
+			FROM passports p
+			WHERE p.id = (SELECT id FROM root)
+			UNION ALL
+			SELECT p.id, f.depth + 1
+			FROM passports p
+			JOIN fwd f ON p.previous_version_id = f.id
+			WHERE f.depth < $2
+		)
+		SELECT p.id, p.version, p.status, p.published_at, COALESCE(p.immutability_hash, '')
+		FROM fwd
+		JOIN passports p ON p.id = fwd.id
+		ORDER BY fwd.depth
+	`
+
+	rows, err := r.db.Query(ctx, query, id, maxRevisionDepth)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []domain.PassportVersion
+	for rows.Next() {
+		var v domain.PassportVersion
+		if err := rows.Scan(&v.PassportID, &v.Version, &v.Status, &v.PublishedAt, &v.ImmutabilityHash); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("passport not found: %w", domain.ErrNotFound)
+	}
+	return versions, nil
+}
+
+// maxRevisionDepth bounds the recursive chain walk.
+const maxRevisionDepth = 1000
+
+// versionOrDefault treats an unset version as the first revision.
+func versionOrDefault(v int) int {
+	if v < 1 {
+		return 1
+	}
+	return v
+}
+
+// levelOrDefault stores passports created without a hierarchy as items.
+func levelOrDefault(l domain.PassportLevel) domain.PassportLevel {
+	if l == "" {
+		return domain.LevelItem
+	}
+	return l
+}
+
+// uniqueViolation is the Postgres SQLSTATE for a unique constraint violation.
+const uniqueViolation = "23505"
+
+func (r *PostgresRepository) FindByIdentifiers(ctx context.Context, ids domain.ProductIdentifiers) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM passports
+		WHERE gtin = $1
+		  AND previous_version_id IS NULL
+		  AND status <> $4
+		  AND (
+		        ($3::text <> '' AND serial_number = $3::text AND ($2::text = '' OR batch_number IS NULL OR batch_number = $2::text))
+		     OR ($3::text = '' AND serial_number IS NULL AND COALESCE(batch_number, '') = $2::text)
+		  )
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, ids.GTIN, ids.BatchNumber, ids.SerialNumber, domain.StatusDraft)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		matches = append(matches, id)
+	}
+	return matches, rows.Err()
+}
+
+func (r *PostgresRepository) CountPublishedChildren(ctx context.Context, parentIDs []uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM passports WHERE parent_id = ANY($1) AND status = $2`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, parentIDs, domain.StatusPublished).Scan(&count); err != nil {
+		return 0, fmt.Errorf("database error: %w", err)
+	}
+	return count, nil
+}
+
+// mapWriteError turns constraint violations into domain errors.
+func mapWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", domain.ErrConflict, pgErr.ConstraintName)
+	}
+	return err
+}
+
+// nullIfEmpty stores empty strings as NULL, like the NULLIF calls of Save.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// revocationColumns flattens the optional Revocation block into its nullable columns.
+func revocationColumns(p *domain.Passport) (*time.Time, *string) {
+	if p.Revocation == nil {
+		return nil, nil
+	}
+	return &p.Revocation.RevokedAt, &p.Revocation.Reason
 }
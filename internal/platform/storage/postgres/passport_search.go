@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+)
+
+// searchCondition translates an attribute search into a SQL condition on passports.attributes.
+// Paths and values only ever reach the query as parameters, through arg.
+//
+// = is JSONB containment (attributes @> '{"a":{"b":v}}'), served by the GIN index on
+// attributes. The ordering operators compare the JSONB value at the path with the value, once
+// both are of the same type: numbers compare numerically, strings by collation.
+//
+// A passport without the attribute matches no comparison on it, negated or not. NOT is pushed
+// down to the comparisons (De Morgan), where it becomes "the attribute is present and the
+// comparison does not hold"; != is the negation of =.
+func searchCondition(expr domain.SearchExpr, arg func(any) string) (string, error) {
+	switch e := expr.(type) {
+	case domain.SearchAnd:
+		return joinSearchConditions(e, " AND ", searchCondition, arg)
+	case domain.SearchOr:
+		return joinSearchConditions(e, " OR ", searchCondition, arg)
+	case domain.SearchNot:
+		return negatedCondition(e.Expr, arg)
+	case domain.SearchComparison:
+		if e.Op == domain.SearchNe {
+			e.Op = domain.SearchEq
+			return negatedCondition(e, arg)
+		}
+		return comparisonCondition(e, arg)
+	}
+	return "", fmt.Errorf("%w: unsupported search expression %T", domain.ErrInvalidInput, expr)
+}
+
+// negatedCondition translates NOT expr.
+func negatedCondition(expr domain.SearchExpr, arg func(any) string) (string, error) {
+	switch e := expr.(type) {
+	case domain.SearchAnd:
+		return joinSearchConditions(e, " OR ", negatedCondition, arg)
+	case domain.SearchOr:
+		return joinSearchConditions(e, " AND ", negatedCondition, arg)
+	case domain.SearchNot:
+		return searchCondition(e.Expr, arg)
+	case domain.SearchComparison:
+		if e.Op == domain.SearchNe {
+			e.Op = domain.SearchEq
+			return searchCondition(e, arg)
+		}
+		condition, err := comparisonCondition(e, arg)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(attributes #> %s::text[] IS NOT NULL AND NOT %s)", arg(e.Path), condition), nil
+	}
+	return "", fmt.Errorf("%w: unsupported search expression %T", domain.ErrInvalidInput, expr)
+}
+
+func joinSearchConditions(terms []domain.SearchExpr, operator string, translate func(domain.SearchExpr, func(any) string) (string, error), arg func(any) string) (string, error) {
+	conditions := make([]string, 0, len(terms))
+	for _, term := range terms {
+		condition, err := translate(term, arg)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+	return "(" + strings.Join(conditions, operator) + ")", nil
+}
+
+func comparisonCondition(c domain.SearchComparison, arg func(any) string) (string, error) {
+	value, err := json.Marshal(c.Value)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid search value: %v", domain.ErrInvalidInput, err)
+	}
+
+	switch c.Op {
+	case domain.SearchEq:
+		// {"a":{"b":v}} for the path a.b
+		document := json.RawMessage(value)
+		for i := len(c.Path) - 1; i >= 0; i-- {
+			nested, _ := json.Marshal(map[string]json.RawMessage{c.Path[i]: document})
+			document = nested
+		}
+		return fmt.Sprintf("(attributes @> %s::jsonb)", arg(string(document))), nil
+
+	case domain.SearchLt, domain.SearchLe, domain.SearchGt, domain.SearchGe:
+		valueType := "string"
+		if _, ok := c.Value.(json.Number); ok {
+			valueType = "number"
+		}
+		path := arg(c.Path)
+		return fmt.Sprintf("(jsonb_typeof(attributes #> %s::text[]) = %s AND attributes #> %s::text[] %s %s::jsonb)",
+			path, arg(valueType), path, c.Op, arg(string(value))), nil
+	}
+	return "", fmt.Errorf("%w: unsupported search operator %q", domain.ErrInvalidInput, c.Op)
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCondition_MissingAttributes(t *testing.T) {
+	const (
+		eq         = `(attributes @> $1::jsonb)`
+		hasEq      = `attributes #> $2::text[] IS NOT NULL`
+		lt         = `(jsonb_typeof(attributes #> $1::text[]) = $2 AND attributes #> $1::text[] < $3::jsonb)`
+		hasLt      = `attributes #> $4::text[] IS NOT NULL`
+		ltAfterEq  = `(jsonb_typeof(attributes #> $3::text[]) = $4 AND attributes #> $3::text[] < $5::jsonb)`
+		hasLtAfter = `attributes #> $6::text[] IS NOT NULL`
+	)
+	// Negations and != never match a passport without the attribute, like the other comparisons
+	tests := map[string]string{
+		`chemistry = LEAD_ACID`:                           eq,
+		`chemistry != LEAD_ACID`:                          `(` + hasEq + ` AND NOT ` + eq + `)`,
+		`NOT chemistry = LEAD_ACID`:                       `(` + hasEq + ` AND NOT ` + eq + `)`,
+		`NOT chemistry != LEAD_ACID`:                      eq,
+		`weight < 10`:                                     lt,
+		`NOT weight < 10`:                                 `(` + hasLt + ` AND NOT ` + lt + `)`,
+		`NOT (chemistry = LEAD_ACID OR weight < 10)`:      `((` + hasEq + ` AND NOT ` + eq + `) AND (` + hasLtAfter + ` AND NOT ` + ltAfterEq + `))`,
+		`NOT (chemistry = LEAD_ACID AND NOT weight < 10)`: `((` + hasEq + ` AND NOT ` + eq + `) OR ` + ltAfterEq + `)`,
+	}
+	for query, want := range tests {
+		expr, err := domain.ParseSearchQuery(query)
+		require.NoError(t, err, query)
+		var args []any
+		arg := func(value any) string {
+			args = append(args, value)
+			return fmt.Sprintf("$%d", len(args))
+		}
+
+		condition, err := searchCondition(expr, arg)
+
+		require.NoError(t, err, query)
+		assert.Equal(t, want, condition, query)
+	}
+}
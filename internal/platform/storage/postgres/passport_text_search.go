@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// writeSearchDocuments replaces the search documents of the passports that carry some. Text is
+// indexed with the 'simple' configuration (lowercased words, no stemming or stop words), so that
+// model names, codes and supplier names match as typed.
+func writeSearchDocuments(ctx context.Context, tx pgx.Tx, passports []*domain.Passport) error {
+	insert := `
+		INSERT INTO passport_search_documents (passport_id, access_rank, document)
+		VALUES ($1, $2,
+			setweight(to_tsvector('simple', $3), 'A') || setweight(to_tsvector('simple', $4), 'B') ||
+			setweight(to_tsvector('simple', $5), 'C') || setweight(to_tsvector('simple', $6), 'D'))
+	`
+
+	var ids []uuid.UUID
+	batch := &pgx.Batch{}
+	for _, p := range passports {
+		if p.SearchDocuments == nil {
+			continue
+		}
+		ids = append(ids, p.ID)
+		for _, d := range p.SearchDocuments {
+			batch.Queue(insert, p.ID, d.AccessRank,
+				d.Text[domain.SearchWeightA], d.Text[domain.SearchWeightB], d.Text[domain.SearchWeightC], d.Text[domain.SearchWeightD])
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM passport_search_documents WHERE passport_id = ANY($1)`, ids); err != nil {
+		return fmt.Errorf("failed to write search documents: %w", err)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to write search documents: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SaveSearchDocuments(ctx context.Context, passports []*domain.Passport) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := writeSearchDocuments(ctx, tx, passports); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	for _, p := range passports {
+		p.SearchDocuments = nil
+	}
+	return nil
+}
+
+// SearchText matches every term as a prefix (term:*) against the one document of each passport
+// the viewer may read: the document of the rank the viewer gets on that passport, the way
+// GetPassport computes it (a PASSPORT grant covers every revision of its passport). ts_rank
+// weighs A to D matches 1, 0.4, 0.2 and 0.1.
+func (r *PostgresRepository) SearchText(ctx context.Context, q domain.TextSearchQuery) ([]domain.TextSearchHit, error) {
+	query := `
+		SELECT d.passport_id, ts_rank(d.document, terms) AS score
+		FROM passport_search_documents d
+		JOIN passports p ON p.id = d.passport_id
+		CROSS JOIN to_tsquery('simple', $1) AS terms
+		WHERE d.document @@ terms
+		  AND p.status <> 'SUPERSEDED'
+		  AND (p.manufacturer_id = $2 OR p.status = 'PUBLISHED')
+		  AND d.access_rank = CASE WHEN p.manufacturer_id = $2 THEN $3::int ELSE GREATEST($4::int, COALESCE((
+		        SELECT max(g.rank)
+		        FROM unnest($5::text[], $6::text[], $7::text[], $8::uuid[], $9::int[])
+		             AS g(manufacturer_id, scope, product_category, passport_id, rank)
+		        WHERE g.manufacturer_id = p.manufacturer_id
+		          AND (
+		                g.scope = 'MANUFACTURER'
+		             OR (g.scope = 'CATEGORY' AND g.product_category = p.product_category)
+		             OR (g.scope = 'PASSPORT' AND g.passport_id = p.id)
+		          )
+		      ), 0)) END
+		ORDER BY score DESC, d.passport_id
+		LIMIT $10
+	`
+
+	prefixes := make([]string, len(q.Terms))
+	for i, term := range q.Terms {
+		prefixes[i] = term + ":*"
+	}
+
+	// The viewer's grants, one array per column
+	manufacturers := make([]string, 0, len(q.Grants))
+	scopes := make([]string, 0, len(q.Grants))
+	categories := make([]string, 0, len(q.Grants))
+	passportIDs := make([]uuid.UUID, 0, len(q.Grants))
+	ranks := make([]int, 0, len(q.Grants))
+	for _, g := range q.Grants {
+		// A PASSPORT grant gets one row per revision of its passport
+		revisions := g.Revisions
+		if len(revisions) == 0 {
+			revisions = []uuid.UUID{uuid.Nil}
+			if g.PassportID != nil {
+				revisions[0] = *g.PassportID
+			}
+		}
+		for _, passportID := range revisions {
+			manufacturers = append(manufacturers, g.ManufacturerID)
+			scopes = append(scopes, string(g.Scope))
+			categories = append(categories, string(g.ProductCategory))
+			passportIDs = append(passportIDs, passportID)
+			ranks = append(ranks, g.Rank)
+		}
+	}
+
+	rows, err := r.db.Query(ctx, query, strings.Join(prefixes, " & "), q.OwnerID, q.OwnerRank, q.ViewerRank,
+		manufacturers, scopes, categories, passportIDs, ranks, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []domain.TextSearchHit
+	for rows.Next() {
+		var hit domain.TextSearchHit
+		var score float32
+		if err := rows.Scan(&hit.PassportID, &score); err != nil {
+			return nil, err
+		}
+		hit.Score = float64(score)
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ScanRepository struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.ScanRepository = (*ScanRepository)(nil)
+
+func NewScanRepository(db *pgxpool.Pool) *ScanRepository {
+	return &ScanRepository{db: db}
+}
+
+// scanDimensionColumns maps the dimensions of scan analytics to their rollup columns
+// (the only column names ever put into a query).
+var scanDimensionColumns = map[domain.ScanDimension]string{
+	domain.ScanBySource:     "source",
+	domain.ScanByAgentClass: "agent_class",
+	domain.ScanByCountry:    "country",
+	domain.ScanByReferrer:   "referrer",
+	domain.ScanByAccessTier: "access_tier",
+}
+
+func (r *ScanRepository) SaveScans(ctx context.Context, scans []domain.ScanEvent) error {
+	if len(scans) == 0 {
+		return nil
+	}
+
+	columns := []string{"passport_id", "source", "agent_class", "country", "referrer", "access_tier", "occurred_at"}
+	_, err := r.db.CopyFrom(ctx, pgx.Identifier{"scan_events"}, columns,
+		pgx.CopyFromSlice(len(scans), func(i int) ([]any, error) {
+			s := scans[i]
+			return []any{s.PassportID, string(s.Source), string(s.AgentClass), s.Country, s.Referrer, s.AccessTier, s.OccurredAt}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
+// RollupScans deletes a batch of logged scans and adds them to both daily counts in a single
+// statement, so a scan is counted exactly once. Scans of passports deleted in the meantime
+// have no tenant and are dropped.
+func (r *ScanRepository) RollupScans(ctx context.Context, limit int) (int, error) {
+	query := `
+		WITH batch AS (
+			DELETE FROM scan_events
+			WHERE seq IN (
+				SELECT seq FROM scan_events
+				ORDER BY seq
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING passport_id, source, agent_class, country, referrer, access_tier,
+			          (occurred_at AT TIME ZONE 'UTC')::date AS day
+		), owned AS (
+			SELECT b.*, p.manufacturer_id
+			FROM batch b
+			JOIN passports p ON p.id = b.passport_id
+		), per_passport AS (
+			INSERT INTO scan_counts_passport_daily (passport_id, day, manufacturer_id, scans)
+			SELECT passport_id, day, manufacturer_id, count(*)
+			FROM owned
+			GROUP BY passport_id, day, manufacturer_id
+			ORDER BY passport_id, day
+			ON CONFLICT (passport_id, day) DO UPDATE SET scans = scan_counts_passport_daily.scans + EXCLUDED.scans
+		), per_tenant AS (
+			INSERT INTO scan_counts_tenant_daily (manufacturer_id, day, source, agent_class, country, referrer, access_tier, scans)
+			SELECT manufacturer_id, day, source, agent_class, country, referrer, access_tier, count(*)
+			FROM owned
+			GROUP BY manufacturer_id, day, source, agent_class, country, referrer, access_tier
+			ORDER BY manufacturer_id, day, source, agent_class, country, referrer, access_tier
+			ON CONFLICT (manufacturer_id, day, source, agent_class, country, referrer, access_tier)
+			DO UPDATE SET scans = scan_counts_tenant_daily.scans + EXCLUDED.scans
+		)
+		SELECT count(*) FROM batch
+	`
+
+	var rolled int
+	if err := r.db.QueryRow(ctx, query, limit).Scan(&rolled); err != nil {
+		return 0, fmt.Errorf("database error: %w", err)
+	}
+	return rolled, nil
+}
+
+// FindScanCounts reads the per-passport counts when the query names a passport, the per-tenant
+// counts otherwise. Weeks start on Monday (date_trunc follows ISO 8601).
+func (r *ScanRepository) FindScanCounts(ctx context.Context, manufacturerID string, q domain.ScanQuery) ([]domain.ScanCount, error) {
+	group := "''"
+	if q.GroupBy != "" {
+		column, ok := scanDimensionColumns[q.GroupBy]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown groupBy %q", domain.ErrInvalidInput, q.GroupBy)
+		}
+		group = column
+	}
+
+	table := "scan_counts_tenant_daily"
+	args := []any{manufacturerID, string(q.Bucket), q.From, q.To}
+	filter := ""
+	if q.PassportID != nil {
+		table = "scan_counts_passport_daily"
+		args = append(args, *q.PassportID)
+		filter = " AND passport_id = $5"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT date_trunc($2, day::timestamp)::date AS bucket, %s AS grp, sum(scans)::bigint
+		FROM %s
+		WHERE manufacturer_id = $1 AND day >= $3::date AND day < $4::date%s
+		GROUP BY bucket, grp
+		ORDER BY bucket, grp
+	`, group, table, filter)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []domain.ScanCount
+	for rows.Next() {
+		var c domain.ScanCount
+		if err := rows.Scan(&c.Start, &c.Group, &c.Scans); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SchemaRegistry struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.SchemaRegistry = (*SchemaRegistry)(nil)
+
+func NewSchemaRegistry(db *pgxpool.Pool) *SchemaRegistry {
+	return &SchemaRegistry{db: db}
+}
+
+func (r *SchemaRegistry) Save(ctx context.Context, s *domain.CategorySchema) error {
+	query := `
+		INSERT INTO category_schemas (product_category, version, schema, active, created_at)
+		VALUES ($1, $2, $3, FALSE, $4)
+	`
+
+	_, err := r.db.Exec(ctx, query, s.Category, s.Version, s.Schema, s.CreatedAt)
+	return mapWriteError(err)
+}
+
+func (r *SchemaRegistry) Get(ctx context.Context, category domain.ProductCategory, version string) (*domain.CategorySchema, error) {
+	query := `
+		SELECT product_category, version, schema, active, created_at, activated_at
+		FROM category_schemas
+		WHERE product_category = $1 AND version = $2
+	`
+	return r.scanOne(r.db.QueryRow(ctx, query, category, version))
+}
+
+func (r *SchemaRegistry) GetActive(ctx context.Context, category domain.ProductCategory) (*domain.CategorySchema, error) {
+	query := `
+		SELECT product_category, version, schema, active, created_at, activated_at
+		FROM category_schemas
+		WHERE product_category = $1 AND active
+	`
+	return r.scanOne(r.db.QueryRow(ctx, query, category))
+}
+
+func (r *SchemaRegistry) List(ctx context.Context, category domain.ProductCategory) ([]*domain.CategorySchema, error) {
+	query := `
+		SELECT product_category, version, schema, active, created_at, activated_at
+		FROM category_schemas
+		WHERE product_category = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, category)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []*domain.CategorySchema
+	for rows.Next() {
+		var s domain.CategorySchema
+		if err := rows.Scan(&s.Category, &s.Version, &s.Schema, &s.Active, &s.CreatedAt, &s.ActivatedAt); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, &s)
+	}
+
+	return schemas, rows.Err()
+}
+
+func (r *SchemaRegistry) Activate(ctx context.Context, category domain.ProductCategory, version string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Deactivate the current version (the partial unique index allows only one active)
+	if _, err := tx.Exec(ctx, `UPDATE category_schemas SET active = FALSE WHERE product_category = $1 AND active AND version <> $2`, category, version); err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	// 2. Activate the requested one
+	tag, err := tx.Exec(ctx, `UPDATE category_schemas SET active = TRUE, activated_at = $3 WHERE product_category = $1 AND version = $2`, category, version, time.Now())
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("schema %s@%s: %w", category, version, domain.ErrNotFound)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *SchemaRegistry) Deactivate(ctx context.Context, category domain.ProductCategory) error {
+	_, err := r.db.Exec(ctx, `UPDATE category_schemas SET active = FALSE WHERE product_category = $1 AND active`, category)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
+func (r *SchemaRegistry) scanOne(row pgx.Row) (*domain.CategorySchema, error) {
+	var s domain.CategorySchema
+	err := row.Scan(&s.Category, &s.Version, &s.Schema, &s.Active, &s.CreatedAt, &s.ActivatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("schema not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookRepository struct {
+	db *pgxpool.Pool
+}
+
+// Ensure we implement the interface
+var _ ports.WebhookRepository = (*WebhookRepository)(nil)
+
+func NewWebhookRepository(db *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const (
+	subscriptionColumns = `id, tenant_id, url, events, secret, created_at`
+	deliveryColumns     = `id, subscription_id, tenant_id, event, payload, status, attempts, next_attempt_at,
+			       COALESCE(last_error, ''), COALESCE(last_status_code, 0), created_at, delivered_at`
+)
+
+func (r *WebhookRepository) SaveSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (` + subscriptionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	events := make([]string, len(sub.Events))
+	for i, e := range sub.Events {
+		events[i] = string(e)
+	}
+
+	_, err := r.db.Exec(ctx, query, sub.ID, sub.TenantID, sub.URL, events, sub.Secret, sub.CreatedAt)
+	return mapWriteError(err)
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+
+	sub, err := scanSubscription(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("webhook subscription not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return sub, nil
+}
+
+func (r *WebhookRepository) FindSubscriptions(ctx context.Context, tenantID string, event domain.WebhookEvent) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE tenant_id = $1 AND ($2::text = '' OR $2::text = ANY(events))
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, string(event))
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []*domain.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	// Deliveries go with it (ON DELETE CASCADE)
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("webhook subscription not found: %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) SaveDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	// Not COPY: an event handled again must not fail on the deliveries it already queued
+	query := `
+		INSERT INTO webhook_deliveries (id, subscription_id, tenant_id, event, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, d := range deliveries {
+		batch.Queue(query,
+			d.ID,
+			d.SubscriptionID,
+			d.TenantID,
+			string(d.Event),
+			[]byte(d.Payload),
+			string(d.Status),
+			d.Attempts,
+			d.NextAttemptAt,
+			d.CreatedAt,
+		)
+	}
+	return mapWriteError(r.db.SendBatch(ctx, batch).Close())
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	d, err := scanDelivery(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("webhook delivery not found: %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return d, nil
+}
+
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, tenantID string, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE tenant_id = $1 AND ($2::text = '' OR status = $2::text)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	return r.queryDeliveries(ctx, query, tenantID, string(status), limit)
+}
+
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	// SKIP LOCKED lets concurrent dispatchers pass each other; the lease hides a claimed
+	// delivery until its attempt is recorded (or the dispatcher is gone)
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	return r.queryDeliveries(ctx, query, limit, lease.Seconds())
+}
+
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, d *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			status = $2, attempts = $3, next_attempt_at = $4,
+			last_error = NULLIF($5, ''), last_status_code = NULLIF($6, 0), delivered_at = $7
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		d.ID,
+		string(d.Status),
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastError,
+		d.LastStatusCode,
+		d.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) Redeliver(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries SET
+			status = 'PENDING', attempts = 0, next_attempt_at = now(),
+			last_error = NULL, last_status_code = NULL, delivered_at = NULL
+		WHERE id = $1 AND status <> 'PENDING'
+		RETURNING ` + deliveryColumns
+
+	d, err := scanDelivery(r.db.QueryRow(ctx, query, id))
+	if err == nil {
+		return d, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if _, err := r.GetDelivery(ctx, id); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: delivery is still pending", domain.ErrConflict)
+}
+
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanSubscription(row pgx.Row) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	var events []string
+	if err := row.Scan(&sub.ID, &sub.TenantID, &sub.URL, &events, &sub.Secret, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		sub.Events = append(sub.Events, domain.WebhookEvent(e))
+	}
+	return &sub, nil
+}
+
+func scanDelivery(row pgx.Row) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	err := row.Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.TenantID,
+		&d.Event,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastError,
+		&d.LastStatusCode,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
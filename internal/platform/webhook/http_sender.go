@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+)
+
+// maxResponseBytes is how much of an endpoint's answer is read (and discarded).
+const maxResponseBytes = 64 << 10
+
+// errBlockedAddress fails deliveries whose host resolves to an internal address.
+var errBlockedAddress = errors.New("webhook host resolves to a non-public address")
+
+type HTTPSender struct {
+	client *http.Client
+}
+
+// Ensure we implement the interface
+var _ ports.WebhookSender = (*HTTPSender)(nil)
+
+// NewHTTPSender returns a sender that does not follow redirects: the subscribed URL must answer itself.
+// It connects to public addresses only, checked on the address actually dialed (after DNS
+// resolution), so a host name cannot be pointed at the internal network once subscribed.
+func NewHTTPSender() *HTTPSender {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !domain.IsPublicWebhookAddress(addr.Addr()) {
+				return fmt.Errorf("%w: %s", errBlockedAddress, address)
+			}
+			return nil
+		},
+	}
+	return &HTTPSender{client: &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext, // No proxy: it would dial on our behalf
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("User-Agent", "TraceApi-Webhooks/1.0")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	return resp.StatusCode, nil
+}
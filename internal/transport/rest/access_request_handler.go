@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type AccessRequestHandler struct {
+	service ports.AccessRequestService
+	log     *slog.Logger
+}
+
+func NewAccessRequestHandler(s ports.AccessRequestService, log *slog.Logger) *AccessRequestHandler {
+	return &AccessRequestHandler{service: s, log: log}
+}
+
+// RegisterResolverRoutes wires up the requester side (authenticated resolver routes)
+func (h *AccessRequestHandler) RegisterResolverRoutes(r chi.Router) {
+	r.Post("/r/{id}/access-requests", h.RequestAccess)
+}
+
+// RegisterRoutes wires up the owner side (ingest API)
+func (h *AccessRequestHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/access-requests", h.ListAccessRequests)
+	r.Post("/access-requests/{id}/approve", h.ApproveAccessRequest)
+	r.Post("/access-requests/{id}/deny", h.DenyAccessRequest)
+}
+
+// RequestAccess handles POST /r/{id}/access-requests
+func (h *AccessRequestHandler) RequestAccess(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Requester Tenant ID
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Parse ID & Body
+	passportID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid passport id", http.StatusBadRequest)
+		return
+	}
+	var input domain.AccessRequestInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	req, err := h.service.RequestAccess(r.Context(), passportID, tenantID, input)
+	if err != nil {
+		h.writeError(w, "failed to request access", err)
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(req)
+}
+
+// ListAccessRequests handles GET /access-requests?status=PENDING
+func (h *AccessRequestHandler) ListAccessRequests(w http.ResponseWriter, r *http.Request) {
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	status := domain.AccessRequestStatus(strings.ToUpper(r.URL.Query().Get("status")))
+	requests, err := h.service.ListAccessRequests(r.Context(), manufacturerID, status)
+	if err != nil {
+		h.writeError(w, "failed to list access requests", err)
+		return
+	}
+	if requests == nil {
+		requests = []*domain.AccessRequest{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// ApproveAccessRequest handles POST /access-requests/{id}/approve
+func (h *AccessRequestHandler) ApproveAccessRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.ApproveAccessRequest, "failed to approve access request")
+}
+
+// DenyAccessRequest handles POST /access-requests/{id}/deny
+func (h *AccessRequestHandler) DenyAccessRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.DenyAccessRequest, "failed to deny access request")
+}
+
+type decideFunc func(ctx context.Context, id uuid.UUID, manufacturerID string, decision domain.AccessDecision) (*domain.AccessRequest, error)
+
+func (h *AccessRequestHandler) decide(w http.ResponseWriter, r *http.Request, fn decideFunc, msg string) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Parse ID & optional Body
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid access request id", http.StatusBadRequest)
+		return
+	}
+	var decision domain.AccessDecision
+	if err := json.NewDecoder(r.Body).Decode(&decision); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	req, err := fn(r.Context(), id, manufacturerID, decision)
+	if err != nil {
+		h.writeError(w, msg, err)
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(req)
+}
+
+func (h *AccessRequestHandler) writeError(w http.ResponseWriter, msg string, err error) {
+	h.log.Error(msg, "error", err)
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrPassportRevoked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
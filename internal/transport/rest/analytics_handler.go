@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type AnalyticsHandler struct {
+	service ports.AnalyticsService
+	log     *slog.Logger
+}
+
+func NewAnalyticsHandler(s ports.AnalyticsService, log *slog.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{service: s, log: log}
+}
+
+// RegisterRoutes wires up the endpoints to the router
+func (h *AnalyticsHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/analytics/scans", h.GetScans)
+}
+
+// GetScans handles GET /analytics/scans?from=2025-01-01&to=2025-02-01&bucket=week&groupBy=country
+func (h *AnalyticsHandler) GetScans(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Parse Query
+	query, err := parseScanQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	report, err := h.service.GetScanReport(r.Context(), manufacturerID, query)
+	if err != nil {
+		h.log.Error("failed to get scan report", "error", err)
+		if errors.Is(err, domain.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseScanQuery reads the report period as UTC dates (to is exclusive), the bucket, the
+// dimension and the optional passport.
+func parseScanQuery(params url.Values) (domain.ScanQuery, error) {
+	query := domain.ScanQuery{
+		Bucket:  domain.ScanBucketSize(params.Get("bucket")),
+		GroupBy: domain.ScanDimension(params.Get("groupBy")),
+	}
+
+	dates := map[string]*time.Time{"from": &query.From, "to": &query.To}
+	for name, field := range dates {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return query, fmt.Errorf("invalid '%s' query parameter: expected YYYY-MM-DD", name)
+		}
+		*field = t
+	}
+	if value := params.Get("passportId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return query, errors.New("invalid 'passportId' query parameter")
+		}
+		query.PassportID = &id
+	}
+	return query, nil
+}
@@ -0,0 +1,353 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+)
+
+// GS1 Digital Link URI compression (GS1 Digital Link: Compression, release 1.0).
+// A compressed URI carries its AIs as one base64url path segment: each AI is written as
+// 4-bit digits followed by its value in the most compact encoding, and the bit string is
+// zero-padded to a multiple of 6. Only the AIs a passport is keyed by are supported, and
+// the optional optimisation codes (AI groups written as a single hex code) are rejected.
+
+const (
+	aiGTIN   = "01"
+	aiBatch  = "10"
+	aiSerial = "21"
+
+	digitalLinkAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// aiFormat describes how the value of an AI is encoded.
+type aiFormat struct {
+	fixedDigits int // fixed-length numeric value
+	maxLength   int // variable-length alphanumeric value
+}
+
+var digitalLinkAIs = map[string]aiFormat{
+	aiGTIN:   {fixedDigits: 14},
+	aiBatch:  {maxLength: 20},
+	aiSerial: {maxLength: 20},
+}
+
+// Encodings of variable-length values (3-bit indicator)
+const (
+	encodingNumeric   = 0 // digits only, as one binary integer
+	encodingHexLower  = 1 // 0-9a-f, 4 bits per character
+	encodingHexUpper  = 2 // 0-9A-F, 4 bits per character
+	encodingBase64URL = 3 // A-Za-z0-9-_, 6 bits per character
+	encodingASCII     = 4 // 7-bit ASCII
+)
+
+// digitalLinkElement is one AI and its value, in URI order.
+type digitalLinkElement struct {
+	ai    string
+	value string
+}
+
+// identifiersFrom maps Digital Link elements to passport keys.
+func identifiersFrom(elements []digitalLinkElement) domain.ProductIdentifiers {
+	var ids domain.ProductIdentifiers
+	for _, e := range elements {
+		switch e.ai {
+		case aiGTIN:
+			ids.GTIN = e.value
+		case aiBatch:
+			ids.BatchNumber = e.value
+		case aiSerial:
+			ids.SerialNumber = e.value
+		}
+	}
+	return ids
+}
+
+// compressDigitalLink encodes the elements as a compressed Digital Link path segment.
+func compressDigitalLink(elements []digitalLinkElement) (string, error) {
+	var w bitWriter
+	for _, e := range elements {
+		format, ok := digitalLinkAIs[e.ai]
+		if !ok {
+			return "", fmt.Errorf("unsupported AI (%s)", e.ai)
+		}
+		for _, d := range e.ai {
+			w.writeUint(uint64(d-'0'), 4)
+		}
+
+		if format.fixedDigits > 0 {
+			if len(e.value) != format.fixedDigits || !isDigits(e.value) {
+				return "", fmt.Errorf("AI (%s) needs %d digits", e.ai, format.fixedDigits)
+			}
+			w.writeDecimal(e.value, numericBits(format.fixedDigits))
+			continue
+		}
+
+		if e.value == "" || len(e.value) > format.maxLength {
+			return "", fmt.Errorf("AI (%s) needs 1 to %d characters", e.ai, format.maxLength)
+		}
+		encoding := bestEncoding(e.value)
+		if encoding < 0 {
+			return "", fmt.Errorf("AI (%s) contains non-ASCII characters", e.ai)
+		}
+		w.writeUint(uint64(encoding), 3)
+		w.writeUint(uint64(len(e.value)), lengthBits(format.maxLength))
+		switch encoding {
+		case encodingNumeric:
+			w.writeDecimal(e.value, numericBits(len(e.value)))
+		case encodingHexLower, encodingHexUpper:
+			for _, c := range strings.ToLower(e.value) {
+				w.writeUint(uint64(strings.IndexRune("0123456789abcdef", c)), 4)
+			}
+		case encodingBase64URL:
+			for _, c := range e.value {
+				w.writeUint(uint64(strings.IndexRune(digitalLinkAlphabet, c)), 6)
+			}
+		default:
+			for _, c := range e.value {
+				w.writeUint(uint64(c), 7)
+			}
+		}
+	}
+	return w.base64(), nil
+}
+
+// decompressDigitalLink decodes a compressed Digital Link path segment.
+func decompressDigitalLink(segment string) ([]digitalLinkElement, error) {
+	r, err := newBitReader(segment)
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []digitalLinkElement
+	// Anything shorter than an AI is padding
+	for r.remaining() >= 8 {
+		first, _ := r.readUint(4)
+		if first > 9 {
+			return nil, fmt.Errorf("optimisation codes are not supported")
+		}
+		second, _ := r.readUint(4)
+		if second > 9 {
+			return nil, fmt.Errorf("invalid AI digit")
+		}
+		ai := fmt.Sprintf("%d%d", first, second)
+		format, ok := digitalLinkAIs[ai]
+		if !ok {
+			return nil, fmt.Errorf("unsupported AI (%s)", ai)
+		}
+
+		var value string
+		if format.fixedDigits > 0 {
+			if value, err = r.readDecimal(format.fixedDigits); err != nil {
+				return nil, fmt.Errorf("AI (%s): %w", ai, err)
+			}
+		} else if value, err = r.readVariable(format.maxLength); err != nil {
+			return nil, fmt.Errorf("AI (%s): %w", ai, err)
+		}
+		elements = append(elements, digitalLinkElement{ai: ai, value: value})
+	}
+
+	if !r.zeroPadding() {
+		return nil, fmt.Errorf("trailing data")
+	}
+	if len(elements) == 0 || elements[0].ai != aiGTIN {
+		return nil, fmt.Errorf("a GTIN is required")
+	}
+	return elements, nil
+}
+
+// numericBits is the number of bits holding any n-digit number.
+func numericBits(n int) int {
+	return int(math.Ceil(float64(n) * math.Log2(10)))
+}
+
+// lengthBits is the size of the length indicator of a value of at most maxLength characters.
+func lengthBits(maxLength int) int {
+	return bits.Len(uint(maxLength))
+}
+
+func bestEncoding(value string) int {
+	switch {
+	case isDigits(value):
+		return encodingNumeric
+	case strings.Trim(value, "0123456789abcdef") == "":
+		return encodingHexLower
+	case strings.Trim(value, "0123456789ABCDEF") == "":
+		return encodingHexUpper
+	case strings.Trim(value, digitalLinkAlphabet) == "":
+		return encodingBase64URL
+	}
+	for _, c := range value {
+		if c > 127 {
+			return -1
+		}
+	}
+	return encodingASCII
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// --- Bit Strings ---
+
+type bitWriter struct {
+	bits []byte // one 0/1 per entry; URIs are short
+}
+
+func (w *bitWriter) writeUint(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.bits = append(w.bits, byte(v>>uint(i))&1)
+	}
+}
+
+func (w *bitWriter) writeDecimal(digits string, n int) {
+	v, _ := new(big.Int).SetString(digits, 10)
+	for i := n - 1; i >= 0; i-- {
+		w.bits = append(w.bits, byte(v.Bit(i)))
+	}
+}
+
+func (w *bitWriter) base64() string {
+	for len(w.bits)%6 != 0 {
+		w.bits = append(w.bits, 0)
+	}
+	var b strings.Builder
+	for i := 0; i < len(w.bits); i += 6 {
+		v := 0
+		for _, bit := range w.bits[i : i+6] {
+			v = v<<1 | int(bit)
+		}
+		b.WriteByte(digitalLinkAlphabet[v])
+	}
+	return b.String()
+}
+
+type bitReader struct {
+	bits []byte
+	pos  int
+}
+
+func newBitReader(segment string) (*bitReader, error) {
+	r := &bitReader{bits: make([]byte, 0, len(segment)*6)}
+	for _, c := range segment {
+		v := strings.IndexRune(digitalLinkAlphabet, c)
+		if v < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		for i := 5; i >= 0; i-- {
+			r.bits = append(r.bits, byte(v>>uint(i))&1)
+		}
+	}
+	return r, nil
+}
+
+func (r *bitReader) remaining() int {
+	return len(r.bits) - r.pos
+}
+
+func (r *bitReader) readUint(n int) (uint64, error) {
+	if r.remaining() < n {
+		return 0, fmt.Errorf("truncated value")
+	}
+	var v uint64
+	for _, bit := range r.bits[r.pos : r.pos+n] {
+		v = v<<1 | uint64(bit)
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *bitReader) readDecimal(digits int) (string, error) {
+	n := numericBits(digits)
+	if r.remaining() < n {
+		return "", fmt.Errorf("truncated value")
+	}
+	v := new(big.Int)
+	for _, bit := range r.bits[r.pos : r.pos+n] {
+		v.Lsh(v, 1)
+		v.SetBit(v, 0, uint(bit))
+	}
+	r.pos += n
+
+	s := v.String()
+	if len(s) > digits {
+		return "", fmt.Errorf("value exceeds %d digits", digits)
+	}
+	return strings.Repeat("0", digits-len(s)) + s, nil
+}
+
+func (r *bitReader) readVariable(maxLength int) (string, error) {
+	encoding, err := r.readUint(3)
+	if err != nil {
+		return "", err
+	}
+	length, err := r.readUint(lengthBits(maxLength))
+	if err != nil {
+		return "", err
+	}
+	if length == 0 || int(length) > maxLength {
+		return "", fmt.Errorf("invalid length %d", length)
+	}
+
+	switch encoding {
+	case encodingNumeric:
+		return r.readDecimal(int(length))
+	case encodingHexLower, encodingHexUpper:
+		alphabet := "0123456789abcdef"
+		if encoding == encodingHexUpper {
+			alphabet = "0123456789ABCDEF"
+		}
+		return r.readChars(int(length), 4, alphabet)
+	case encodingBase64URL:
+		return r.readChars(int(length), 6, digitalLinkAlphabet)
+	case encodingASCII:
+		return r.readChars(int(length), 7, "")
+	default:
+		return "", fmt.Errorf("unsupported encoding %d", encoding)
+	}
+}
+
+// readChars reads fixed-width characters; an empty alphabet means raw ASCII codes.
+func (r *bitReader) readChars(n, width int, alphabet string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		v, err := r.readUint(width)
+		if err != nil {
+			return "", err
+		}
+		if alphabet != "" {
+			b.WriteByte(alphabet[v])
+		} else {
+			b.WriteByte(byte(v))
+		}
+	}
+	return b.String(), nil
+}
+
+func (r *bitReader) zeroPadding() bool {
+	for _, bit := range r.bits[r.pos:] {
+		if bit != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigitalLinkCompression_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []digitalLinkElement
+	}{
+		{"gtin only", []digitalLinkElement{{aiGTIN, "09506000134352"}}},
+		{"numeric serial", []digitalLinkElement{{aiGTIN, "09506000134352"}, {aiSerial, "0012345"}}},
+		{"lower hex lot", []digitalLinkElement{{aiGTIN, "09506000134352"}, {aiBatch, "ab12ef"}}},
+		{"upper hex serial", []digitalLinkElement{{aiGTIN, "09506000134352"}, {aiSerial, "AB12EF"}}},
+		{"base64url serial", []digitalLinkElement{{aiGTIN, "09506000134352"}, {aiSerial, "Xyz-9_q"}}},
+		{"ascii lot and serial", []digitalLinkElement{{aiGTIN, "00614141000036"}, {aiBatch, "LOT/7"}, {aiSerial, "12345678901234567890"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compressed, err := compressDigitalLink(tt.elements)
+			require.NoError(t, err)
+			assert.Regexp(t, `^[A-Za-z0-9_-]+$`, compressed)
+
+			decoded, err := decompressDigitalLink(compressed)
+			require.NoError(t, err)
+			assert.Equal(t, tt.elements, decoded)
+		})
+	}
+}
+
+func TestDigitalLinkCompression_Sizes(t *testing.T) {
+	// AI 01 = 8 bits + 47 bits of value, padded to 60 bits
+	compressed, err := compressDigitalLink([]digitalLinkElement{{aiGTIN, "09506000134352"}})
+	require.NoError(t, err)
+	assert.Len(t, compressed, 10)
+
+	// + AI 21: 8 bits + 3 bits encoding + 5 bits length + 4 digits in 14 bits = 30 bits
+	compressed, err = compressDigitalLink([]digitalLinkElement{{aiGTIN, "09506000134352"}, {aiSerial, "1234"}})
+	require.NoError(t, err)
+	assert.Len(t, compressed, 15)
+}
+
+func TestDecompressDigitalLink_Rejects(t *testing.T) {
+	for name, segment := range map[string]string{
+		"not base64url":     "ABC.DEF+GHIJ",
+		"optimisation code": "oAAAAAAAAAAAAA",
+		"unsupported AI":    "AIAAAAAAAAAAAA", // AI (00)
+		"trailing bits":     "ARFKk4XBoB",     // GTIN followed by non-zero padding
+	} {
+		_, err := decompressDigitalLink(segment)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// keepAliveInterval is the longest the stream stays silent; proxies drop idle connections.
+const keepAliveInterval = 15 * time.Second
+
+type EventHandler struct {
+	service ports.EventStreamService
+	log     *slog.Logger
+}
+
+func NewEventHandler(s ports.EventStreamService, log *slog.Logger) *EventHandler {
+	return &EventHandler{service: s, log: log}
+}
+
+// RegisterRoutes wires up the endpoints to the router. The stream is long-lived: mount it
+// outside any request timeout.
+func (h *EventHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/events/stream", h.Stream)
+}
+
+// Stream handles GET /events/stream, sending the tenant's lifecycle events as Server-Sent Events
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Tenant ID
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Open the Stream (resuming after the last event the client received)
+	stream, err := h.service.Open(r.Context(), tenantID, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		h.log.Error("failed to open event stream", "error", err)
+		if errors.Is(err, domain.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stops nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		h.log.Error("event stream not supported", "error", err)
+		return
+	}
+
+	// 3. Stream until the client goes away
+	for {
+		events, err := stream.Next(r.Context(), keepAliveInterval)
+		if r.Context().Err() != nil {
+			return
+		}
+		if err != nil {
+			// The client reconnects with its Last-Event-ID
+			h.log.Error("failed to stream events", "tenant", tenantID, "error", err)
+			return
+		}
+
+		if len(events) == 0 {
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.Cursor, event.Type, event.Payload)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type GrantHandler struct {
+	service ports.GrantService
+	log     *slog.Logger
+}
+
+func NewGrantHandler(s ports.GrantService, log *slog.Logger) *GrantHandler {
+	return &GrantHandler{service: s, log: log}
+}
+
+// RegisterRoutes wires up the endpoints to the router
+func (h *GrantHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/grants", h.CreateGrant)
+	r.Get("/grants", h.ListGrants)
+	r.Delete("/grants/{id}", h.RevokeGrant)
+}
+
+// CreateGrant handles POST /grants
+func (h *GrantHandler) CreateGrant(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Decode Body
+	var req domain.GrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	grant, err := h.service.CreateGrant(r.Context(), manufacturerID, req)
+	if err != nil {
+		h.writeError(w, "failed to create grant", err)
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(grant)
+}
+
+// ListGrants handles GET /grants
+func (h *GrantHandler) ListGrants(w http.ResponseWriter, r *http.Request) {
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	grants, err := h.service.ListGrants(r.Context(), manufacturerID)
+	if err != nil {
+		h.writeError(w, "failed to list grants", err)
+		return
+	}
+	if grants == nil {
+		grants = []*domain.AccessGrant{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grants)
+}
+
+// RevokeGrant handles DELETE /grants/{id}
+func (h *GrantHandler) RevokeGrant(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Parse ID
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid grant id", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	grant, err := h.service.RevokeGrant(r.Context(), id, manufacturerID)
+	if err != nil {
+		h.writeError(w, "failed to revoke grant", err)
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grant)
+}
+
+func (h *GrantHandler) writeError(w http.ResponseWriter, msg string, err error) {
+	h.log.Error(msg, "error", err)
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
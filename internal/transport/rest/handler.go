@@ -12,10 +12,14 @@ package rest
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/core/ports"
@@ -24,32 +28,53 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxImportBodyBytes bounds a bulk import upload.
+const maxImportBodyBytes = 64 << 20
+
 type PassportHandler struct {
 	service ports.PassportService
+	jobs    ports.JobService // Optional: without it, imports run within the request
 	log     *slog.Logger
 }
 
-func NewPassportHandler(s ports.PassportService, log *slog.Logger) *PassportHandler {
-	return &PassportHandler{service: s, log: log}
+func NewPassportHandler(s ports.PassportService, jobs ports.JobService, log *slog.Logger) *PassportHandler {
+	return &PassportHandler{service: s, jobs: jobs, log: log}
 }
 
 // RegisterRoutes wires up the endpoints to the router
 func (h *PassportHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/passports", h.CreatePassport)
+	r.Post("/passports/import", h.ImportPassports)
+	r.Post("/passports/publish", h.PublishPassports)
 	r.Get("/passports", h.ListPassports)
+	r.Post("/passports/search", h.SearchPassports)
+	r.Post("/passports/search/reindex", h.ReindexSearch)
 	r.Put("/passports/{id}", h.UpdatePassport)
 	r.Post("/passports/{id}/publish", h.PublishPassport)
+	r.Post("/passports/{id}/revoke", h.RevokePassport)
+	r.Post("/passports/{id}/revisions", h.CreateRevision)
 }
 
-// CreatePassport handles POST /passports?category=BATTERY_INDUSTRIAL
+// CreatePassport handles POST /passports?category=BATTERY_INDUSTRIAL[&gtin=...&lot=...&serial=...]
 func (h *PassportHandler) CreatePassport(w http.ResponseWriter, r *http.Request) {
-	// 1. Parse Query Param for Category
-	catParam := r.URL.Query().Get("category")
+	// 1. Parse Query Params for Category, GS1 Keys and Hierarchy
+	query := r.URL.Query()
+	catParam := query.Get("category")
 	if catParam == "" {
 		http.Error(w, "missing 'category' query parameter", http.StatusBadRequest)
 		return
 	}
 	category := domain.ProductCategory(catParam)
+	ids := domain.ProductIdentifiers{
+		GTIN:         query.Get("gtin"),
+		BatchNumber:  query.Get("lot"),
+		SerialNumber: query.Get("serial"),
+	}
+	hierarchy, err := parseHierarchy(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// 2. Get Manufacturer ID from Context (set by AuthMiddleware)
 	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
@@ -73,7 +98,7 @@ func (h *PassportHandler) CreatePassport(w http.ResponseWriter, r *http.Request)
 		// Fallback to ID if name is missing (should be handled by middleware, but safe guard)
 		manufacturerName = manufacturerID
 	}
-	passport, err := h.service.CreatePassport(r.Context(), manufacturerID, manufacturerName, category, body)
+	passport, err := h.service.CreatePassport(r.Context(), manufacturerID, manufacturerName, category, ids, hierarchy, body)
 	if err != nil {
 		h.log.Error("failed to create passport", "error", err)
 
@@ -81,6 +106,10 @@ func (h *PassportHandler) CreatePassport(w http.ResponseWriter, r *http.Request)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, domain.ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		if errors.Is(err, domain.ErrConflict) {
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
@@ -96,8 +125,195 @@ func (h *PassportHandler) CreatePassport(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(passport)
 }
 
+// ImportPassports handles POST /passports/import?category=...&format=ndjson|csv[&map=column=target...]
+func (h *PassportHandler) ImportPassports(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Query Params for Category, Format, Column Mapping and Hierarchy
+	query := r.URL.Query()
+	catParam := query.Get("category")
+	if catParam == "" {
+		http.Error(w, "missing 'category' query parameter", http.StatusBadRequest)
+		return
+	}
+	req := domain.ImportRequest{
+		Category: domain.ProductCategory(catParam),
+		Format:   importFormat(query.Get("format"), r.Header.Get("Content-Type")),
+	}
+	for _, m := range query["map"] {
+		column, target, ok := strings.Cut(m, "=")
+		if !ok || column == "" {
+			http.Error(w, "invalid 'map' query parameter: expected column=target", http.StatusBadRequest)
+			return
+		}
+		parsed, err := domain.ParseColumnTarget(target)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Mapping == nil {
+			req.Mapping = domain.ColumnMapping{}
+		}
+		req.Mapping[column] = parsed
+	}
+	hierarchy, err := parseHierarchy(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Hierarchy = hierarchy
+
+	// 2. Get Manufacturer Identity from Context
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized: missing manufacturer identity", http.StatusUnauthorized)
+		return
+	}
+	manufacturerName, ok := middleware.GetManufacturerName(r.Context())
+	if !ok || manufacturerName == "" {
+		manufacturerName = manufacturerID
+	}
+
+	// 3. Queue a Job (large imports outlive the request timeout)
+	defer r.Body.Close()
+	if h.jobs != nil {
+		h.submitImport(w, r, manufacturerID, manufacturerName, req)
+		return
+	}
+
+	// 3b. Or import inline (the body is streamed, not buffered)
+	report, err := h.service.ImportPassports(r.Context(), manufacturerID, manufacturerName, req, r.Body)
+	if err != nil {
+		h.log.Error("failed to import passports", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrForbidden):
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 4. Respond (rejected lines do not fail the import)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// submitImport stores the body with an import job and answers 202 with the job handle.
+func (h *PassportHandler) submitImport(w http.ResponseWriter, r *http.Request, manufacturerID, manufacturerName string, req domain.ImportRequest) {
+	if req.Format != domain.ImportFormatNDJSON && req.Format != domain.ImportFormatCSV {
+		http.Error(w, "format must be ndjson or csv", http.StatusBadRequest)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBodyBytes))
+	if err != nil {
+		http.Error(w, "import body too large or unreadable", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	params := domain.ImportJobParams{ManufacturerName: manufacturerName, Request: req}
+	job, err := h.jobs.SubmitJob(r.Context(), manufacturerID, domain.JobTypeImport, params, body)
+	if err != nil {
+		h.log.Error("failed to queue import job", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/jobs/"+job.ID.String())
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// importFormat takes the explicit format, or infers it from the Content-Type.
+func importFormat(format string, contentType string) domain.ImportFormat {
+	if format != "" {
+		return domain.ImportFormat(strings.ToLower(format))
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "text/csv":
+		return domain.ImportFormatCSV
+	case "application/x-ndjson", "application/jsonl":
+		return domain.ImportFormatNDJSON
+	}
+	return ""
+}
+
+// parseHierarchy reads the level and parent query parameters of the creation endpoints.
+func parseHierarchy(query url.Values) (domain.PassportHierarchy, error) {
+	hierarchy := domain.PassportHierarchy{Level: domain.PassportLevel(query.Get("level"))}
+	if parentParam := query.Get("parent"); parentParam != "" {
+		parentID, err := uuid.Parse(parentParam)
+		if err != nil {
+			return hierarchy, errors.New("invalid 'parent' query parameter")
+		}
+		hierarchy.ParentID = &parentID
+	}
+	return hierarchy, nil
+}
+
+// PublishPassports handles POST /passports/publish: publishes a list of drafts, or those matching a filter
+func (h *PassportHandler) PublishPassports(w http.ResponseWriter, r *http.Request) {
+	// 1. Decode & Validate the Selection
+	var req domain.BulkPublishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 2. Get Manufacturer Identity from Context
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized: missing manufacturer identity", http.StatusUnauthorized)
+		return
+	}
+
+	// 3. Queue a Job (every passport is an upload)
+	if h.jobs != nil {
+		job, err := h.jobs.SubmitJob(r.Context(), manufacturerID, domain.JobTypePublish, req, nil)
+		if err != nil {
+			h.log.Error("failed to queue publish job", "error", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/jobs/"+job.ID.String())
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+		return
+	}
+
+	// 3b. Or publish inline
+	report, err := h.service.PublishPassports(r.Context(), manufacturerID, req)
+	if err != nil {
+		h.log.Error("failed to publish passports", "error", err)
+		if errors.Is(err, domain.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// 4. Respond (failed passports do not fail the operation)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
 // PublishPassport handles POST /passports/{id}/publish
 func (h *PassportHandler) PublishPassport(w http.ResponseWriter, r *http.Request) {
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -105,10 +321,14 @@ func (h *PassportHandler) PublishPassport(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	passport, err := h.service.PublishPassport(r.Context(), id)
+	passport, err := h.service.PublishPassport(r.Context(), id, manufacturerID)
 	if err != nil {
 		h.log.Error("failed to publish passport", "error", err)
-		if errors.Is(err, domain.ErrPassportAlreadyPublished) {
+		if errors.Is(err, domain.ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, domain.ErrPassportAlreadyPublished) || errors.Is(err, domain.ErrPassportRevoked) || errors.Is(err, domain.ErrConflict) {
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
 		}
@@ -125,7 +345,7 @@ func (h *PassportHandler) PublishPassport(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(passport)
 }
 
-// ListPassports handles GET /passports
+// ListPassports handles GET /passports[?status=&category=&createdFrom=&createdTo=&publishedFrom=&publishedTo=&serial=&sort=&limit=&cursor=]
 func (h *PassportHandler) ListPassports(w http.ResponseWriter, r *http.Request) {
 	// 1. Get Manufacturer ID from Context
 	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
@@ -134,19 +354,172 @@ func (h *PassportHandler) ListPassports(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// 2. Call Service
-	passports, err := h.service.ListPassports(r.Context(), manufacturerID)
+	// 2. Parse Filters, Sort & Cursor
+	query, err := parsePassportQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	page, err := h.service.ListPassports(r.Context(), manufacturerID, query)
 	if err != nil {
 		h.log.Error("failed to list passports", "error", err)
+		if errors.Is(err, domain.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// 3. Respond
+	// 4. Respond (the next page, if any, is linked with the same parameters)
+	if page.Next != nil {
+		next := r.URL.Query()
+		next.Set("cursor", page.Next.Encode())
+		w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, next.Encode()))
+	}
+	passports := page.Passports
+	if passports == nil {
+		passports = []*domain.Passport{}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(passports)
 }
 
+// SearchResponse is one page of search results; nextCursor, if set, continues the search.
+type SearchResponse struct {
+	Passports  []*domain.Passport `json:"passports"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// SearchPassports handles POST /passports/search: an attribute search over the caller's passports
+func (h *PassportHandler) SearchPassports(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID from Context
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized: missing manufacturer identity", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Decode & Parse the Search
+	var req domain.PassportSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	query, err := req.PassportQuery()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	page, err := h.service.ListPassports(r.Context(), manufacturerID, query)
+	if err != nil {
+		h.log.Error("failed to search passports", "error", err)
+		if errors.Is(err, domain.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// 4. Respond
+	resp := SearchResponse{Passports: page.Passports}
+	if resp.Passports == nil {
+		resp.Passports = []*domain.Passport{}
+	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.Encode()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ReindexSearch handles POST /passports/search/reindex: rebuilds the full-text search documents of
+// the caller's passports (e.g. after new search fields were added to a schema)
+func (h *PassportHandler) ReindexSearch(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID from Context
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized: missing manufacturer identity", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Queue a Job
+	if h.jobs != nil {
+		job, err := h.jobs.SubmitJob(r.Context(), manufacturerID, domain.JobTypeSearchReindex, nil, nil)
+		if err != nil {
+			h.log.Error("failed to queue search reindex job", "error", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/jobs/"+job.ID.String())
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(job)
+		return
+	}
+
+	// 2b. Or rebuild inline
+	report, err := h.service.ReindexSearch(r.Context(), manufacturerID, nil)
+	if err != nil {
+		h.log.Error("failed to rebuild search documents", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func parsePassportQuery(params url.Values) (domain.PassportQuery, error) {
+	var query domain.PassportQuery
+	var err error
+
+	query.Sort, query.Descending, err = domain.ParsePassportSort(params.Get("sort"))
+	if err != nil {
+		return query, err
+	}
+	if limit := params.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 1 {
+			return query, errors.New("invalid 'limit' query parameter")
+		}
+	}
+	if cursor := params.Get("cursor"); cursor != "" {
+		if query.After, err = domain.DecodePassportCursor(cursor); err != nil {
+			return query, err
+		}
+	}
+
+	query.Filter = domain.PassportFilter{
+		Status:          domain.PassportStatus(strings.ToUpper(params.Get("status"))),
+		ProductCategory: domain.ProductCategory(params.Get("category")),
+		SerialNumber:    params.Get("serial"),
+	}
+	dates := map[string]**time.Time{
+		"createdFrom":   &query.Filter.CreatedFrom,
+		"createdTo":     &query.Filter.CreatedTo,
+		"publishedFrom": &query.Filter.PublishedFrom,
+		"publishedTo":   &query.Filter.PublishedTo,
+	}
+	for name, field := range dates {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("invalid '%s' query parameter: expected RFC 3339", name)
+		}
+		*field = &t
+	}
+	return query, nil
+}
+
 // UpdatePassport handles PUT /passports/{id}
 func (h *PassportHandler) UpdatePassport(w http.ResponseWriter, r *http.Request) {
 	// 1. Get Manufacturer ID
@@ -180,6 +553,10 @@ func (h *PassportHandler) UpdatePassport(w http.ResponseWriter, r *http.Request)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -188,3 +565,97 @@ func (h *PassportHandler) UpdatePassport(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(passport)
 }
+
+type RevokeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RevokePassport handles POST /passports/{id}/revoke
+func (h *PassportHandler) RevokePassport(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Parse ID
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid passport id", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Decode Body
+	var req RevokeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 4. Call Service
+	passport, err := h.service.RevokePassport(r.Context(), id, manufacturerID, req.Reason)
+	if err != nil {
+		h.log.Error("failed to revoke passport", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, "passport not found", http.StatusNotFound)
+		case errors.Is(err, domain.ErrForbidden):
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case errors.Is(err, domain.ErrPassportRevoked), errors.Is(err, domain.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 5. Respond
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(passport)
+}
+
+// CreateRevision handles POST /passports/{id}/revisions
+func (h *PassportHandler) CreateRevision(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Parse ID
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid passport id", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	revision, err := h.service.CreateRevision(r.Context(), id, manufacturerID)
+	if err != nil {
+		h.log.Error("failed to create revision", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, "passport not found", http.StatusNotFound)
+		case errors.Is(err, domain.ErrForbidden):
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrPassportRevoked):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(revision)
+}
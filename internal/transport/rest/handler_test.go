@@ -14,6 +14,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -35,14 +36,30 @@ type MockPassportService struct {
 	mock.Mock
 }
 
-func (m *MockPassportService) CreatePassport(ctx context.Context, manufacturerID string, manufacturerName string, category domain.ProductCategory, payload []byte) (*domain.Passport, error) {
-	args := m.Called(ctx, manufacturerID, manufacturerName, category, payload)
+func (m *MockPassportService) CreatePassport(ctx context.Context, manufacturerID string, manufacturerName string, category domain.ProductCategory, ids domain.ProductIdentifiers, hierarchy domain.PassportHierarchy, payload []byte) (*domain.Passport, error) {
+	args := m.Called(ctx, manufacturerID, manufacturerName, category, ids, hierarchy, payload)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Passport), args.Error(1)
 }
 
+func (m *MockPassportService) ImportPassports(ctx context.Context, manufacturerID string, manufacturerName string, req domain.ImportRequest, body io.Reader) (*domain.ImportReport, error) {
+	args := m.Called(ctx, manufacturerID, manufacturerName, req, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportReport), args.Error(1)
+}
+
+func (m *MockPassportService) PublishPassports(ctx context.Context, manufacturerID string, req domain.BulkPublishRequest) (*domain.PublishReport, error) {
+	args := m.Called(ctx, manufacturerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PublishReport), args.Error(1)
+}
+
 func (m *MockPassportService) GetPassport(ctx context.Context, id uuid.UUID) (*domain.Passport, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -51,20 +68,34 @@ func (m *MockPassportService) GetPassport(ctx context.Context, id uuid.UUID) (*d
 	return args.Get(0).(*domain.Passport), args.Error(1)
 }
 
-func (m *MockPassportService) PublishPassport(ctx context.Context, id uuid.UUID) (*domain.Passport, error) {
-	args := m.Called(ctx, id)
+func (m *MockPassportService) PublishPassport(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.Passport, error) {
+	args := m.Called(ctx, id, manufacturerID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Passport), args.Error(1)
 }
 
-func (m *MockPassportService) ListPassports(ctx context.Context, manufacturerID string) ([]*domain.Passport, error) {
-	args := m.Called(ctx, manufacturerID)
+func (m *MockPassportService) ListPassports(ctx context.Context, manufacturerID string, query domain.PassportQuery) (*domain.PassportPage, error) {
+	args := m.Called(ctx, manufacturerID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PassportPage), args.Error(1)
+}
+
+func (m *MockPassportService) SearchText(ctx context.Context, text string, limit int) ([]domain.TextSearchResult, error) {
+	args := m.Called(ctx, text, limit)
+	results, _ := args.Get(0).([]domain.TextSearchResult)
+	return results, args.Error(1)
+}
+
+func (m *MockPassportService) ReindexSearch(ctx context.Context, manufacturerID string, progress func(done int)) (*domain.ReindexReport, error) {
+	args := m.Called(ctx, manufacturerID, progress)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*domain.Passport), args.Error(1)
+	return args.Get(0).(*domain.ReindexReport), args.Error(1)
 }
 
 func (m *MockPassportService) UpdatePassport(ctx context.Context, id uuid.UUID, manufacturerID string, payload []byte) (*domain.Passport, error) {
@@ -75,13 +106,42 @@ func (m *MockPassportService) UpdatePassport(ctx context.Context, id uuid.UUID,
 	return args.Get(0).(*domain.Passport), args.Error(1)
 }
 
+func (m *MockPassportService) RevokePassport(ctx context.Context, id uuid.UUID, manufacturerID string, reason string) (*domain.Passport, error) {
+	args := m.Called(ctx, id, manufacturerID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Passport), args.Error(1)
+}
+
+func (m *MockPassportService) CreateRevision(ctx context.Context, id uuid.UUID, manufacturerID string) (*domain.Passport, error) {
+	args := m.Called(ctx, id, manufacturerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Passport), args.Error(1)
+}
+
+func (m *MockPassportService) GetRevisionHistory(ctx context.Context, id uuid.UUID) ([]domain.PassportVersion, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PassportVersion), args.Error(1)
+}
+
+func (m *MockPassportService) ResolveIdentifiers(ctx context.Context, ids domain.ProductIdentifiers) (uuid.UUID, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
 // --- Tests ---
 
 func TestCreatePassport_Handler_Success(t *testing.T) {
 	// Setup
 	mockSvc := new(MockPassportService)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	handler := rest.NewPassportHandler(mockSvc, logger)
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
 
 	// Router
 	r := chi.NewRouter()
@@ -104,7 +164,7 @@ func TestCreatePassport_Handler_Success(t *testing.T) {
 		ProductCategory: domain.CategoryBattery,
 		Status:          domain.StatusDraft,
 	}
-	mockSvc.On("CreatePassport", mock.Anything, "mfg-1", "Test Manufacturer Inc.", domain.CategoryBattery, mock.Anything).Return(expectedPassport, nil)
+	mockSvc.On("CreatePassport", mock.Anything, "mfg-1", "Test Manufacturer Inc.", domain.CategoryBattery, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, mock.Anything).Return(expectedPassport, nil)
 
 	// Execute
 	rr := httptest.NewRecorder()
@@ -122,7 +182,7 @@ func TestCreatePassport_Handler_Success(t *testing.T) {
 func TestCreatePassport_Handler_MissingCategory(t *testing.T) {
 	mockSvc := new(MockPassportService)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	handler := rest.NewPassportHandler(mockSvc, logger)
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
 	r := chi.NewRouter()
 	handler.RegisterRoutes(r)
 
@@ -142,7 +202,7 @@ func TestCreatePassport_Handler_MissingCategory(t *testing.T) {
 func TestCreatePassport_Handler_ServiceError(t *testing.T) {
 	mockSvc := new(MockPassportService)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	handler := rest.NewPassportHandler(mockSvc, logger)
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
 	r := chi.NewRouter()
 	handler.RegisterRoutes(r)
 
@@ -152,7 +212,7 @@ func TestCreatePassport_Handler_ServiceError(t *testing.T) {
 	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
 	req = req.WithContext(ctx)
 
-	mockSvc.On("CreatePassport", mock.Anything, "mfg-1", "mfg-1", domain.CategoryBattery, mock.Anything).Return(nil, domain.ErrInvalidInput)
+	mockSvc.On("CreatePassport", mock.Anything, "mfg-1", "mfg-1", domain.CategoryBattery, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, mock.Anything).Return(nil, domain.ErrInvalidInput)
 
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
@@ -163,7 +223,7 @@ func TestCreatePassport_Handler_ServiceError(t *testing.T) {
 func TestCreatePassport_Handler_InternalError(t *testing.T) {
 	mockSvc := new(MockPassportService)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	handler := rest.NewPassportHandler(mockSvc, logger)
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
 	r := chi.NewRouter()
 	handler.RegisterRoutes(r)
 
@@ -173,7 +233,7 @@ func TestCreatePassport_Handler_InternalError(t *testing.T) {
 	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
 	req = req.WithContext(ctx)
 
-	mockSvc.On("CreatePassport", mock.Anything, "mfg-1", "mfg-1", domain.CategoryBattery, mock.Anything).Return(nil, errors.New("db error"))
+	mockSvc.On("CreatePassport", mock.Anything, "mfg-1", "mfg-1", domain.CategoryBattery, domain.ProductIdentifiers{}, domain.PassportHierarchy{}, mock.Anything).Return(nil, errors.New("db error"))
 
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
@@ -181,10 +241,99 @@ func TestCreatePassport_Handler_InternalError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
 
+func TestListPassports_Handler_LinksNextPage(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req, _ := http.NewRequest("GET", "/passports?status=draft&createdFrom=2025-11-01T00:00:00Z&sort=serialNumber&limit=1", nil)
+
+	// Inject Auth Context
+	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
+	req = req.WithContext(ctx)
+
+	next := &domain.PassportCursor{Order: "serialNumber", Key: "S1", ID: uuid.New()}
+	mockSvc.On("ListPassports", mock.Anything, "mfg-1", mock.MatchedBy(func(q domain.PassportQuery) bool {
+		return q.Filter.Status == domain.StatusDraft && q.Filter.CreatedFrom != nil &&
+			q.Sort == domain.SortSerialNumber && !q.Descending && q.Limit == 1
+	})).Return(&domain.PassportPage{Passports: []*domain.Passport{{ID: next.ID, SerialNumber: "S1"}}, Next: next}, nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Link"), "cursor="+next.Encode())
+	assert.Contains(t, rr.Header().Get("Link"), `sort=serialNumber`)
+	assert.Contains(t, rr.Header().Get("Link"), `rel="next"`)
+	var resp []domain.Passport
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.Len(t, resp, 1)
+}
+
+func TestListPassports_Handler_InvalidParameters(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	for _, query := range []string{"sort=-colour", "limit=zero", "cursor=not-a-cursor", "publishedTo=yesterday"} {
+		req, _ := http.NewRequest("GET", "/passports?"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1"))
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	mockSvc.AssertNotCalled(t, "ListPassports", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchPassports_Handler(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	body := `{"query": "chemistry = LITHIUM_ION AND carbonFootprint.totalCarbonFootprint < 60", "limit": 1}`
+	req, _ := http.NewRequest("POST", "/passports/search", bytes.NewBufferString(body))
+
+	// Inject Auth Context
+	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
+	req = req.WithContext(ctx)
+
+	next := &domain.PassportCursor{Order: "-createdAt", Key: "2025-11-28T09:30:00Z", ID: uuid.New()}
+	mockSvc.On("ListPassports", mock.Anything, "mfg-1", mock.MatchedBy(func(q domain.PassportQuery) bool {
+		and, ok := q.Filter.Attributes.(domain.SearchAnd)
+		return ok && len(and) == 2 && q.Limit == 1
+	})).Return(&domain.PassportPage{Passports: []*domain.Passport{{ID: next.ID}}, Next: next}, nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp rest.SearchResponse
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.Len(t, resp.Passports, 1)
+	assert.Equal(t, next.Encode(), resp.NextCursor)
+
+	// A malformed query never reaches the service
+	req, _ = http.NewRequest("POST", "/passports/search", bytes.NewBufferString(`{"query": "chemistry ="}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1"))
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNumberOfCalls(t, "ListPassports", 1)
+}
+
 func TestPublishPassport_Handler_Success(t *testing.T) {
 	mockSvc := new(MockPassportService)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	handler := rest.NewPassportHandler(mockSvc, logger)
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
 	r := chi.NewRouter()
 	handler.RegisterRoutes(r)
 
@@ -197,7 +346,7 @@ func TestPublishPassport_Handler_Success(t *testing.T) {
 	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
 	req = req.WithContext(ctx)
 
-	mockSvc.On("PublishPassport", mock.Anything, id).Return(passport, nil)
+	mockSvc.On("PublishPassport", mock.Anything, id, "mfg-1").Return(passport, nil)
 
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
@@ -211,7 +360,7 @@ func TestPublishPassport_Handler_Success(t *testing.T) {
 func TestPublishPassport_Handler_Conflict(t *testing.T) {
 	mockSvc := new(MockPassportService)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	handler := rest.NewPassportHandler(mockSvc, logger)
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
 	r := chi.NewRouter()
 	handler.RegisterRoutes(r)
 
@@ -223,7 +372,7 @@ func TestPublishPassport_Handler_Conflict(t *testing.T) {
 	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
 	req = req.WithContext(ctx)
 
-	mockSvc.On("PublishPassport", mock.Anything, id).Return(nil, domain.ErrPassportAlreadyPublished)
+	mockSvc.On("PublishPassport", mock.Anything, id, "mfg-1").Return(nil, domain.ErrPassportAlreadyPublished)
 
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
@@ -231,3 +380,119 @@ func TestPublishPassport_Handler_Conflict(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, rr.Code)
 	assert.Contains(t, rr.Body.String(), "passport already published")
 }
+
+func TestRevokePassport_Handler_Success(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	id := uuid.New()
+	passport := &domain.Passport{
+		ID:         id,
+		Status:     domain.StatusRevoked,
+		Revocation: &domain.Revocation{Reason: "Thermal runaway recall"},
+	}
+
+	body, _ := json.Marshal(rest.RevokeRequest{Reason: "Thermal runaway recall"})
+	req, _ := http.NewRequest("POST", "/passports/"+id.String()+"/revoke", bytes.NewBuffer(body))
+
+	// Inject Auth Context
+	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
+	req = req.WithContext(ctx)
+
+	mockSvc.On("RevokePassport", mock.Anything, id, "mfg-1", "Thermal runaway recall").Return(passport, nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp domain.Passport
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.Equal(t, domain.StatusRevoked, resp.Status)
+	assert.Equal(t, "Thermal runaway recall", resp.Revocation.Reason)
+}
+
+func TestRevokePassport_Handler_Forbidden(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	id := uuid.New()
+	req, _ := http.NewRequest("POST", "/passports/"+id.String()+"/revoke", bytes.NewBufferString(`{"reason":"recall"}`))
+
+	// Inject Auth Context
+	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-2")
+	req = req.WithContext(ctx)
+
+	mockSvc.On("RevokePassport", mock.Anything, id, "mfg-2", "recall").Return(nil, domain.ErrForbidden)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestImportPassports_Handler_CSVMapping(t *testing.T) {
+	// Setup
+	mockSvc := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req, _ := http.NewRequest("POST", "/passports/import?category=TEXTILE_APPAREL&map=sn=serial&map=pct=/fiberComposition/0/percentage:number", bytes.NewBufferString("sn,pct\nS1,100\n"))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
+	req = req.WithContext(ctx)
+
+	// Expectations: format inferred from the Content-Type, mapping parsed
+	expected := domain.ImportRequest{
+		Category: domain.CategoryTextile,
+		Format:   domain.ImportFormatCSV,
+		Mapping: domain.ColumnMapping{
+			"sn":  {Pointer: domain.ImportTargetSerial, Type: domain.ColumnString},
+			"pct": {Pointer: "/fiberComposition/0/percentage", Type: domain.ColumnNumber},
+		},
+		Hierarchy: domain.PassportHierarchy{},
+	}
+	id := uuid.New()
+	report := &domain.ImportReport{Accepted: 1, Lines: []domain.ImportLineResult{{Line: 2, PassportID: &id}}}
+	mockSvc.On("ImportPassports", mock.Anything, "mfg-1", "mfg-1", expected, mock.Anything).Return(report, nil)
+
+	// Execute
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response domain.ImportReport
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Accepted)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestImportPassports_Handler_InvalidMapping(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := rest.NewPassportHandler(mockSvc, nil, logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	for _, mapping := range []string{"pct=percentage", "pct=/materials/999999999/share:number"} {
+		req, _ := http.NewRequest("POST", "/passports/import?category=TEXTILE_APPAREL&format=csv&map="+mapping, bytes.NewBufferString("pct\n100\n"))
+		ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, mapping)
+	}
+	mockSvc.AssertNotCalled(t, "ImportPassports", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
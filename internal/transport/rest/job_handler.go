@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type JobHandler struct {
+	service ports.JobService
+	log     *slog.Logger
+}
+
+func NewJobHandler(s ports.JobService, log *slog.Logger) *JobHandler {
+	return &JobHandler{service: s, log: log}
+}
+
+// RegisterRoutes wires up the endpoints to the router
+func (h *JobHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/jobs", h.ListJobs)
+	r.Get("/jobs/{id}", h.GetJob)
+	r.Get("/jobs/{id}/result", h.GetJobResult)
+	r.Post("/jobs/{id}/cancel", h.CancelJob)
+	r.Post("/jobs/{id}/retry", h.RetryJob)
+}
+
+// ListJobs handles GET /jobs
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobs, err := h.service.ListJobs(r.Context(), tenantID)
+	if err != nil {
+		h.writeError(w, "failed to list jobs", err)
+		return
+	}
+	if jobs == nil {
+		jobs = []*domain.Job{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// GetJob handles GET /jobs/{id}: status and progress
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	h.jobAction(w, r, "failed to get job", h.service.GetJob)
+}
+
+// CancelJob handles POST /jobs/{id}/cancel
+func (h *JobHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	h.jobAction(w, r, "failed to cancel job", h.service.CancelJob)
+}
+
+// RetryJob handles POST /jobs/{id}/retry
+func (h *JobHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	h.jobAction(w, r, "failed to retry job", h.service.RetryJob)
+}
+
+// GetJobResult handles GET /jobs/{id}/result
+func (h *JobHandler) GetJobResult(w http.ResponseWriter, r *http.Request) {
+	tenantID, id, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetJobResult(r.Context(), id, tenantID)
+	if err != nil {
+		h.writeError(w, "failed to get job result", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(result)
+}
+
+// jobAction runs a service call taking the job ID and responds with the job.
+func (h *JobHandler) jobAction(w http.ResponseWriter, r *http.Request, msg string, call func(context.Context, uuid.UUID, string) (*domain.Job, error)) {
+	tenantID, id, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := call(r.Context(), id, tenantID)
+	if err != nil {
+		h.writeError(w, msg, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *JobHandler) parseRequest(w http.ResponseWriter, r *http.Request) (string, uuid.UUID, bool) {
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return "", uuid.Nil, false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return "", uuid.Nil, false
+	}
+	return tenantID, id, true
+}
+
+func (h *JobHandler) writeError(w http.ResponseWriter, msg string, err error) {
+	h.log.Error(msg, "error", err)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
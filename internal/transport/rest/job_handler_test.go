@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/transport/rest"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) SubmitJob(ctx context.Context, tenantID string, jobType domain.JobType, params interface{}, input []byte) (*domain.Job, error) {
+	args := m.Called(ctx, tenantID, jobType, params, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *MockJobService) GetJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error) {
+	args := m.Called(ctx, id, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *MockJobService) ListJobs(ctx context.Context, tenantID string) ([]*domain.Job, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).([]*domain.Job), args.Error(1)
+}
+
+func (m *MockJobService) GetJobResult(ctx context.Context, id uuid.UUID, tenantID string) ([]byte, error) {
+	args := m.Called(ctx, id, tenantID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+func (m *MockJobService) CancelJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error) {
+	args := m.Called(ctx, id, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *MockJobService) RetryJob(ctx context.Context, id uuid.UUID, tenantID string) (*domain.Job, error) {
+	args := m.Called(ctx, id, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func newJobRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	ctx := context.WithValue(req.Context(), middleware.ManufacturerIDKey, "mfg-1")
+	return req.WithContext(ctx)
+}
+
+func TestImportPassports_Handler_QueuesJob(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	mockJobs := new(MockJobService)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := chi.NewRouter()
+	rest.NewPassportHandler(mockSvc, mockJobs, logger).RegisterRoutes(r)
+
+	body := `{"serial": "S1", "attributes": {}}` + "\n"
+	job := &domain.Job{ID: uuid.New(), Type: domain.JobTypeImport, Status: domain.JobQueued}
+	params := domain.ImportJobParams{
+		ManufacturerName: "mfg-1",
+		Request:          domain.ImportRequest{Category: domain.CategoryBattery, Format: domain.ImportFormatNDJSON},
+	}
+	mockJobs.On("SubmitJob", mock.Anything, "mfg-1", domain.JobTypeImport, params, []byte(body)).Return(job, nil)
+
+	rr := httptest.NewRecorder()
+	req := newJobRequest("POST", "/passports/import?category=BATTERY_INDUSTRIAL", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/jobs/"+job.ID.String(), rr.Header().Get("Location"))
+	mockJobs.AssertExpectations(t)
+	mockSvc.AssertNotCalled(t, "ImportPassports", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobHandler_StatusAndResult(t *testing.T) {
+	mockJobs := new(MockJobService)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := chi.NewRouter()
+	rest.NewJobHandler(mockJobs, logger).RegisterRoutes(r)
+
+	id := uuid.New()
+	running := &domain.Job{ID: id, Status: domain.JobRunning, Progress: domain.JobProgress{Done: 500, Total: 50000}}
+	mockJobs.On("GetJob", mock.Anything, id, "mfg-1").Return(running, nil)
+	mockJobs.On("GetJobResult", mock.Anything, id, "mfg-1").Return(nil, fmt.Errorf("%w: job is RUNNING", domain.ErrConflict))
+
+	t.Run("Progress", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, newJobRequest("GET", "/jobs/"+id.String(), nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response domain.Job
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, domain.JobProgress{Done: 500, Total: 50000}, response.Progress)
+	})
+
+	t.Run("Result not ready", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, newJobRequest("GET", "/jobs/"+id.String()+"/result", nil))
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
+
+func TestPublishPassports_Handler_QueuesJob(t *testing.T) {
+	mockSvc := new(MockPassportService)
+	mockJobs := new(MockJobService)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := chi.NewRouter()
+	rest.NewPassportHandler(mockSvc, mockJobs, logger).RegisterRoutes(r)
+
+	job := &domain.Job{ID: uuid.New(), Type: domain.JobTypePublish, Status: domain.JobQueued}
+	mockJobs.On("SubmitJob", mock.Anything, "mfg-1", domain.JobTypePublish, mock.MatchedBy(func(req domain.BulkPublishRequest) bool {
+		return req.Category == domain.CategoryBattery && req.CreatedBefore != nil &&
+			req.CreatedBefore.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+	}), []byte(nil)).Return(job, nil)
+
+	rr := httptest.NewRecorder()
+	body := `{"category": "BATTERY_INDUSTRIAL", "createdBefore": "2025-12-01T00:00:00Z"}`
+	r.ServeHTTP(rr, newJobRequest("POST", "/passports/publish", bytes.NewBufferString(body)))
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/jobs/"+job.ID.String(), rr.Header().Get("Location"))
+	mockJobs.AssertExpectations(t)
+
+	// Neither ids nor a filter
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, newJobRequest("POST", "/passports/publish", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
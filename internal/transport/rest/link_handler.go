@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type LinkHandler struct {
+	service ports.LinkService
+	log     *slog.Logger
+}
+
+func NewLinkHandler(s ports.LinkService, log *slog.Logger) *LinkHandler {
+	return &LinkHandler{service: s, log: log}
+}
+
+// RegisterRoutes wires up the endpoints to the router
+func (h *LinkHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/links", h.CreateLink)
+	r.Get("/links", h.ListLinks)
+	r.Delete("/links/{id}", h.DeleteLink)
+}
+
+// CreateLink handles POST /links
+func (h *LinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Decode Body
+	var input domain.LinkInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	link, err := h.service.CreateLink(r.Context(), manufacturerID, input)
+	if err != nil {
+		h.writeError(w, "failed to create link", err)
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(link)
+}
+
+// ListLinks handles GET /links
+func (h *LinkHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	links, err := h.service.ListLinks(r.Context(), manufacturerID)
+	if err != nil {
+		h.writeError(w, "failed to list links", err)
+		return
+	}
+	if links == nil {
+		links = []*domain.PassportLink{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+// DeleteLink handles DELETE /links/{id}
+func (h *LinkHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Manufacturer ID
+	manufacturerID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Parse ID
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid link id", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	if err := h.service.DeleteLink(r.Context(), id, manufacturerID); err != nil {
+		h.writeError(w, "failed to delete link", err)
+		return
+	}
+
+	// 4. Respond
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *LinkHandler) writeError(w http.ResponseWriter, msg string, err error) {
+	h.log.Error(msg, "error", err)
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, "a link with this type, language and media type already exists", http.StatusConflict)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+)
+
+// linksetTarget is a target object of an RFC 9264 linkset.
+type linksetTarget struct {
+	Href     string   `json:"href"`
+	Title    string   `json:"title,omitempty"`
+	HrefLang []string `json:"hreflang,omitempty"`
+	Type     string   `json:"type,omitempty"`
+}
+
+// buildLinkset renders the links of a passport as an application/linkset+json document.
+// The passport page itself is listed as the GS1 default link.
+func buildLinkset(anchor, passportURL string, links []*domain.PassportLink) map[string]interface{} {
+	linkContext := map[string]interface{}{
+		"anchor":                     anchor,
+		domain.LinkTypeDefault.URI(): []linksetTarget{{Href: passportURL, Title: "Digital Product Passport"}},
+	}
+
+	for _, l := range links {
+		target := linksetTarget{Href: l.Href, Title: l.Title, Type: l.MediaType}
+		if l.Language != "" {
+			target.HrefLang = []string{l.Language}
+		}
+		rel := l.LinkType.URI()
+		targets, _ := linkContext[rel].([]linksetTarget)
+		linkContext[rel] = append(targets, target)
+	}
+
+	return map[string]interface{}{"linkset": []interface{}{linkContext}}
+}
+
+// selectLink picks the destination of a link type for the client's Accept-Language and Accept
+// headers: exact language first, then the same primary language, then language-neutral links.
+// The media type only breaks ties. Returns nil if the type has no link.
+func selectLink(links []*domain.PassportLink, linkType domain.LinkType, acceptLanguage, accept string) *domain.PassportLink {
+	var candidates []*domain.PassportLink
+	for _, l := range links {
+		if l.LinkType == linkType {
+			candidates = append(candidates, l)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	candidates = byLanguage(candidates, acceptLanguage)
+	return byMediaType(candidates, accept)
+}
+
+func byLanguage(links []*domain.PassportLink, acceptLanguage string) []*domain.PassportLink {
+	for _, tag := range acceptedValues(acceptLanguage) {
+		if tag == "*" {
+			break
+		}
+		if exact := filterLinks(links, func(l *domain.PassportLink) bool { return strings.EqualFold(l.Language, tag) }); len(exact) > 0 {
+			return exact
+		}
+		primary := primaryLanguage(tag)
+		if related := filterLinks(links, func(l *domain.PassportLink) bool {
+			return l.Language != "" && primaryLanguage(l.Language) == primary
+		}); len(related) > 0 {
+			return related
+		}
+	}
+
+	if neutral := filterLinks(links, func(l *domain.PassportLink) bool { return l.Language == "" }); len(neutral) > 0 {
+		return neutral
+	}
+	// A destination in another language beats none
+	return links
+}
+
+func byMediaType(links []*domain.PassportLink, accept string) *domain.PassportLink {
+	for _, mediaRange := range acceptedValues(accept) {
+		if mediaRange == "*/*" {
+			break
+		}
+		for _, l := range links {
+			if l.MediaType != "" && mediaTypeMatches(mediaRange, l.MediaType) {
+				return l
+			}
+		}
+	}
+
+	for _, l := range links {
+		if l.MediaType == "" {
+			return l
+		}
+	}
+	return links[0]
+}
+
+func filterLinks(links []*domain.PassportLink, keep func(*domain.PassportLink) bool) []*domain.PassportLink {
+	var out []*domain.PassportLink
+	for _, l := range links {
+		if keep(l) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func primaryLanguage(tag string) string {
+	return strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+}
+
+// mediaTypeMatches reports whether a media range such as "text/*" covers the media type.
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(strings.ToLower(mediaType), strings.TrimSuffix(mediaRange, "*"))
+	}
+	return strings.EqualFold(mediaRange, mediaType)
+}
+
+// acceptedValues parses an Accept or Accept-Language header into its values, lowercased,
+// most preferred first. Values with q=0 are dropped.
+func acceptedValues(header string) []string {
+	type weighted struct {
+		value string
+		q     float64
+	}
+
+	var values []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(fields[0]))
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if k, v, ok := strings.Cut(strings.TrimSpace(param), "="); ok && strings.EqualFold(k, "q") {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			values = append(values, weighted{value: value, q: q})
+		}
+	}
+
+	sort.SliceStable(values, func(i, j int) bool { return values[i].q > values[j].q })
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = v.value
+	}
+	return out
+}
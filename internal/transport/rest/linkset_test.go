@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectLink(t *testing.T) {
+	enHTML := &domain.PassportLink{LinkType: domain.LinkTypePIP, Href: "https://example.com/en", Language: "en", MediaType: "text/html"}
+	enPDF := &domain.PassportLink{LinkType: domain.LinkTypePIP, Href: "https://example.com/en.pdf", Language: "en", MediaType: "application/pdf"}
+	frCA := &domain.PassportLink{LinkType: domain.LinkTypePIP, Href: "https://example.com/fr-ca", Language: "fr-CA"}
+	neutral := &domain.PassportLink{LinkType: domain.LinkTypePIP, Href: "https://example.com/any"}
+	other := &domain.PassportLink{LinkType: domain.LinkTypeInstructions, Href: "https://example.com/manual"}
+	links := []*domain.PassportLink{enHTML, enPDF, frCA, neutral, other}
+
+	tests := []struct {
+		name           string
+		links          []*domain.PassportLink
+		acceptLanguage string
+		accept         string
+		want           *domain.PassportLink
+	}{
+		{"Exact language", links, "fr-CA", "", frCA},
+		{"Primary language fallback", links, "fr-FR", "", frCA},
+		{"Quality order", links, "de, fr;q=0.4, en;q=0.8", "", enHTML},
+		{"Media type breaks ties", links, "en", "application/pdf", enPDF},
+		{"Media range", links, "en", "application/*;q=0.9, text/plain", enPDF},
+		{"Refused language", links, "fr;q=0, es", "", neutral},
+		{"No language preference", links, "", "", neutral},
+		{"Only other languages", []*domain.PassportLink{frCA}, "ja", "", frCA},
+		{"No link of the type", []*domain.PassportLink{other}, "en", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selectLink(tt.links, domain.LinkTypePIP, tt.acceptLanguage, tt.accept))
+		})
+	}
+}
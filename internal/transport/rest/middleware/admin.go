@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package middleware
+
+import "net/http"
+
+// RequireAdmin only lets platform administrator tenants through.
+// It must run after HybridAuthMiddleware, which sets the tenant identity.
+func RequireAdmin(adminTenantIDs []string) func(http.Handler) http.Handler {
+	admins := make(map[string]struct{}, len(adminTenantIDs))
+	for _, id := range adminTenantIDs {
+		admins[id] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID, ok := GetManufacturerID(r.Context())
+			if !ok || tenantID == "" {
+				http.Error(w, "unauthorized: missing manufacturer identity", http.StatusUnauthorized)
+				return
+			}
+			if _, ok := admins[tenantID]; !ok {
+				http.Error(w, "forbidden: administrator access required", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -46,6 +46,11 @@ func (m *MockAuthRepository) GetTenantName(ctx context.Context, tenantID string)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockAuthRepository) GetKeyAccessLevel(ctx context.Context, apiKeyHash string) (string, error) {
+	args := m.Called(ctx, apiKeyHash)
+	return args.String(0), args.Error(1)
+}
+
 func TestHybridAuthMiddleware(t *testing.T) {
 	secret := "test-secret"
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
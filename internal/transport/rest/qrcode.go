@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// QR code rendering bounds. The quiet zone is counted in modules (the spec asks for 4).
+const (
+	defaultQRSize      = 256
+	minQRSize          = 64
+	maxQRSize          = 2048
+	defaultQRQuietZone = 4
+	maxQRQuietZone     = 16
+)
+
+// qrRecoveryLevels maps the error correction levels of ISO/IEC 18004 (share of the code that may
+// be damaged: L 7%, M 15%, Q 25%, H 30%) to the encoder's.
+var qrRecoveryLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// qrOptions controls how GetQRCode renders a code.
+type qrOptions struct {
+	svg        bool
+	size       int // Width and height in pixels
+	level      qrcode.RecoveryLevel
+	quietZone  int
+	foreground color.RGBA
+	background color.RGBA
+}
+
+// parseQROptions reads ?format=png|svg, size, ecc=L|M|Q|H, quietZone and fg/bg (hex RGB, with or
+// without '#'). Missing parameters take the defaults: a 256px black-on-white PNG, level M.
+func parseQROptions(params url.Values) (qrOptions, error) {
+	opts := qrOptions{
+		size:       defaultQRSize,
+		level:      qrcode.Medium,
+		quietZone:  defaultQRQuietZone,
+		foreground: color.RGBA{A: 0xff},
+		background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+
+	switch format := strings.ToLower(params.Get("format")); format {
+	case "", "png":
+	case "svg":
+		opts.svg = true
+	default:
+		return opts, fmt.Errorf("invalid 'format' query parameter: %q is not png or svg", format)
+	}
+
+	var err error
+	if value := params.Get("size"); value != "" {
+		if opts.size, err = strconv.Atoi(value); err != nil || opts.size < minQRSize || opts.size > maxQRSize {
+			return opts, fmt.Errorf("invalid 'size' query parameter: expected %d to %d pixels", minQRSize, maxQRSize)
+		}
+	}
+	if value := params.Get("ecc"); value != "" {
+		level, ok := qrRecoveryLevels[strings.ToUpper(value)]
+		if !ok {
+			return opts, errors.New("invalid 'ecc' query parameter: expected L, M, Q or H")
+		}
+		opts.level = level
+	}
+	if value := params.Get("quietZone"); value != "" {
+		if opts.quietZone, err = strconv.Atoi(value); err != nil || opts.quietZone < 0 || opts.quietZone > maxQRQuietZone {
+			return opts, fmt.Errorf("invalid 'quietZone' query parameter: expected 0 to %d modules", maxQRQuietZone)
+		}
+	}
+
+	colors := map[string]*color.RGBA{"fg": &opts.foreground, "bg": &opts.background}
+	for name, field := range colors {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		c, err := parseHexColor(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid '%s' query parameter: expected a hex color such as 1a2b3c", name)
+		}
+		*field = c
+	}
+	if opts.foreground == opts.background {
+		return opts, errors.New("invalid colors: 'fg' and 'bg' must differ")
+	}
+	return opts, nil
+}
+
+// parseHexColor reads an opaque RGB color written as RRGGBB, optionally prefixed with '#'.
+func parseHexColor(value string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 6 {
+		return color.RGBA{}, errors.New("expected 6 hex digits")
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
+
+// renderQR encodes content as a PNG or SVG image and returns it with its media type. Modules are
+// drawn at a whole number of pixels each, so a PNG code is centered within the requested size
+// rather than stretched; a size too small for the code is an error.
+func renderQR(content string, opts qrOptions) ([]byte, string, error) {
+	code, err := qrcode.New(content, opts.level)
+	if err != nil {
+		return nil, "", err
+	}
+	code.DisableBorder = true // The quiet zone is drawn below
+	bitmap := code.Bitmap()
+	modules := len(bitmap) + 2*opts.quietZone
+
+	if opts.svg {
+		return renderQRSVG(bitmap, modules, opts), "image/svg+xml", nil
+	}
+
+	scale := opts.size / modules
+	if scale < 1 {
+		return nil, "", fmt.Errorf("size too small: this code needs at least %d pixels", modules)
+	}
+	offset := (opts.size-modules*scale)/2 + opts.quietZone*scale
+
+	// Palette index 0 (the background) fills the image
+	img := image.NewPaletted(image.Rect(0, 0, opts.size, opts.size), color.Palette{opts.background, opts.foreground})
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				for px := 0; px < scale; px++ {
+					img.SetColorIndex(offset+x*scale+px, offset+y*scale+py, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// renderQRSVG draws the code in module units (the viewBox), one path for the dark modules with
+// each horizontal run as a single rectangle.
+func renderQRSVG(bitmap [][]bool, modules int, opts qrOptions) []byte {
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			run := 1
+			for x+run < len(row) && row[x+run] {
+				run++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", x+opts.quietZone, y+opts.quietZone, run, run)
+			x += run
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.size, opts.size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, modules, modules, hexColor(opts.background))
+	fmt.Fprintf(&buf, `<path fill="%s" d="%s"/>`, hexColor(opts.foreground), path.String())
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
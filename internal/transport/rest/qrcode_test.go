@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQROptions(t *testing.T) {
+	opts, err := parseQROptions(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, qrOptions{
+		size:       256,
+		level:      qrcode.Medium,
+		quietZone:  4,
+		foreground: color.RGBA{A: 0xff},
+		background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}, opts)
+
+	opts, err = parseQROptions(url.Values{"format": {"SVG"}, "size": {"1024"}, "ecc": {"h"}, "quietZone": {"0"}, "fg": {"#1A2B3C"}, "bg": {"fafafa"}})
+	require.NoError(t, err)
+	assert.True(t, opts.svg)
+	assert.Equal(t, 1024, opts.size)
+	assert.Equal(t, qrcode.Highest, opts.level)
+	assert.Equal(t, 0, opts.quietZone)
+	assert.Equal(t, color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}, opts.foreground)
+
+	invalid := []url.Values{
+		{"format": {"jpeg"}},
+		{"size": {"32"}},
+		{"size": {"4096"}},
+		{"size": {"big"}},
+		{"ecc": {"X"}},
+		{"quietZone": {"-1"}},
+		{"quietZone": {"17"}},
+		{"fg": {"red"}},
+		{"bg": {"#fff"}},
+		{"fg": {"ffffff"}}, // Same as the default background
+	}
+	for _, params := range invalid {
+		_, err := parseQROptions(params)
+		assert.Error(t, err, params.Encode())
+	}
+}
+
+func TestRenderQR_PNG(t *testing.T) {
+	opts, err := parseQROptions(url.Values{"size": {"300"}, "fg": {"003366"}})
+	require.NoError(t, err)
+
+	data, contentType, err := renderQR("https://tapi.eu/r/123", opts)
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 300, img.Bounds().Dx())
+	assert.Equal(t, 300, img.Bounds().Dy())
+
+	// Every module is drawn at a whole number of pixels, inside the quiet zone
+	code, err := qrcode.New("https://tapi.eu/r/123", qrcode.Medium)
+	require.NoError(t, err)
+	code.DisableBorder = true
+	bitmap := code.Bitmap()
+	modules := len(bitmap) + 8
+	scale := 300 / modules
+	offset := (300-modules*scale)/2 + 4*scale
+	dark := color.RGBAModel.Convert(color.RGBA{B: 0x66, G: 0x33, A: 0xff})
+	light := color.RGBAModel.Convert(color.White)
+	for y, row := range bitmap {
+		for x, set := range row {
+			want := light
+			if set {
+				want = dark
+			}
+			got := color.RGBAModel.Convert(img.At(offset+x*scale+scale/2, offset+y*scale+scale/2))
+			require.Equal(t, want, got, "module %d,%d", x, y)
+		}
+	}
+	assert.Equal(t, light, color.RGBAModel.Convert(img.At(offset-1, offset-1)))
+
+	// Too small for a longer code once the quiet zone is added
+	opts.size, opts.quietZone = 64, 16
+	_, _, err = renderQR("https://tapi.eu/r/"+strings.Repeat("0", 100), opts)
+	assert.Error(t, err)
+}
+
+func TestRenderQR_SVG(t *testing.T) {
+	opts, err := parseQROptions(url.Values{"format": {"svg"}, "size": {"512"}, "quietZone": {"2"}, "bg": {"ffeecc"}})
+	require.NoError(t, err)
+
+	data, contentType, err := renderQR("https://tapi.eu/r/123", opts)
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", contentType)
+	svg := string(data)
+	code, _ := qrcode.New("https://tapi.eu/r/123", qrcode.Medium)
+	code.DisableBorder = true
+	modules := len(code.Bitmap()) + 4
+	assert.True(t, strings.HasPrefix(svg, `<?xml`))
+	assert.Contains(t, svg, `width="512" height="512"`)
+	assert.Contains(t, svg, `viewBox="0 0 `+strconv.Itoa(modules)+` `+strconv.Itoa(modules)+`"`)
+	assert.Contains(t, svg, `fill="#ffeecc"`)
+	assert.Contains(t, svg, `<path fill="#000000" d="M2 2h7v1h-7z`) // Top row of the finder pattern
+}
@@ -14,9 +14,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"time"
@@ -28,18 +32,20 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/skip2/go-qrcode"
 )
 
 type ResolverHandler struct {
 	service  ports.PassportService
+	links    ports.LinkService
 	authRepo ports.AuthRepository
+	scans    ports.ScanRecorder
 	log      *slog.Logger
 	cfg      *config.Config
 }
 
-func NewResolverHandler(s ports.PassportService, authRepo ports.AuthRepository, log *slog.Logger, cfg *config.Config) *ResolverHandler {
-	return &ResolverHandler{service: s, authRepo: authRepo, log: log, cfg: cfg}
+// NewResolverHandler wires the public resolver. scans may be nil (no scan analytics).
+func NewResolverHandler(s ports.PassportService, links ports.LinkService, authRepo ports.AuthRepository, scans ports.ScanRecorder, log *slog.Logger, cfg *config.Config) *ResolverHandler {
+	return &ResolverHandler{service: s, links: links, authRepo: authRepo, scans: scans, log: log, cfg: cfg}
 }
 
 func (h *ResolverHandler) RegisterResolverRoutes(r chi.Router) {
@@ -47,6 +53,14 @@ func (h *ResolverHandler) RegisterResolverRoutes(r chi.Router) {
 	r.Get("/r/{id}", h.ResolvePassport)
 	r.Get("/r/{id}/qr", h.GetQRCode)
 	r.Post("/auth/token", h.ExchangeToken)
+	r.Get("/search", h.SearchText)
+
+	// GS1 Digital Link (e.g., tapi.eu/01/09506000134352/21/ABC123)
+	r.Get("/01/{gtin}", h.ResolveDigitalLink)
+	r.Get("/01/{gtin}/10/{lot}", h.ResolveDigitalLink)
+	r.Get("/01/{gtin}/21/{serial}", h.ResolveDigitalLink)
+	r.Get("/01/{gtin}/10/{lot}/21/{serial}", h.ResolveDigitalLink)
+	r.Get("/{compressed:[A-Za-z0-9_-]{10,}}", h.ResolveCompressedDigitalLink)
 }
 
 func (h *ResolverHandler) ResolvePassport(w http.ResponseWriter, r *http.Request) {
@@ -57,59 +71,147 @@ func (h *ResolverHandler) ResolvePassport(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// 0. Determine Context (Public vs Restricted)
-	ctx := r.Context()
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-		if strings.HasPrefix(tokenString, "traceapi_") {
-			// Case A: Raw API Key
-			hash := sha256.Sum256([]byte(tokenString))
-			apiKeyHash := hex.EncodeToString(hash[:])
-			tenantID, valid, err := h.authRepo.ValidateKey(ctx, apiKeyHash)
-			if err == nil && valid {
-				ctx = context.WithValue(ctx, domain.ViewContextKey, domain.ViewContextRestricted)
-				ctx = context.WithValue(ctx, domain.ViewerTenantIDKey, tenantID)
-			}
-		} else {
-			// Case B: JWT Token
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return []byte(h.cfg.JWTSecret), nil
-			})
-
-			if err == nil && token.Valid {
-				ctx = context.WithValue(ctx, domain.ViewContextKey, domain.ViewContextRestricted)
-				if claims, ok := token.Claims.(jwt.MapClaims); ok {
-					if sub, ok := claims["sub"].(string); ok {
-						ctx = context.WithValue(ctx, domain.ViewerTenantIDKey, sub)
-					}
-				}
-			}
-		}
-	}
-
-	// 1. Fetch Data
-	passport, err := h.service.GetPassport(ctx, uid)
+	h.renderPassport(w, r, uid)
+}
+
+// SearchText handles GET /search?q=...[&limit=...]: full-text search over the passports the
+// caller may see, with their text filtered as on /r/{id}
+func (h *ResolverHandler) SearchText(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse the Query
+	query := r.URL.Query()
+	limit := 0
+	if param := query.Get("limit"); param != "" {
+		var err error
+		if limit, err = strconv.Atoi(param); err != nil {
+			http.Error(w, "invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// 2. Search as the Viewer (Public vs Restricted)
+	results, err := h.service.SearchText(h.viewerContext(r), query.Get("q"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.log.Error("failed to search passports", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// ResolveDigitalLink handles uncompressed GS1 Digital Link URIs (/01/{gtin}[/10/{lot}][/21/{serial}]).
+func (h *ResolverHandler) ResolveDigitalLink(w http.ResponseWriter, r *http.Request) {
+	var elements []digitalLinkElement
+	for _, key := range []struct{ ai, param string }{{aiGTIN, "gtin"}, {aiBatch, "lot"}, {aiSerial, "serial"}} {
+		raw := chi.URLParam(r, key.param)
+		if raw == "" {
+			continue
+		}
+		// Serial and batch numbers may contain percent-encoded characters such as "/"
+		value, err := url.PathUnescape(raw)
+		if err != nil {
+			http.Error(w, "Invalid Digital Link", http.StatusBadRequest)
+			return
+		}
+		elements = append(elements, digitalLinkElement{ai: key.ai, value: value})
+	}
+
+	h.resolveIdentifiers(w, r, identifiersFrom(elements))
+}
+
+// ResolveCompressedDigitalLink handles compressed GS1 Digital Link URIs (a single base64url segment).
+func (h *ResolverHandler) ResolveCompressedDigitalLink(w http.ResponseWriter, r *http.Request) {
+	elements, err := decompressDigitalLink(chi.URLParam(r, "compressed"))
+	if err != nil {
+		h.log.Debug("not a compressed digital link", "path", r.URL.Path, "error", err)
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	h.resolveIdentifiers(w, r, identifiersFrom(elements))
+}
+
+func (h *ResolverHandler) resolveIdentifiers(w http.ResponseWriter, r *http.Request, ids domain.ProductIdentifiers) {
+	uid, err := h.service.ResolveIdentifiers(r.Context(), ids)
+	if err != nil {
+		h.log.Warn("digital link not resolved", "gtin", ids.GTIN, "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, "Passport Not Found", http.StatusNotFound)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// The canonical short URL of what is being displayed
+	w.Header().Set("Content-Location", "/r/"+uid.String())
+	h.renderPassport(w, r, uid)
+}
+
+// renderPassport writes the filtered view of a passport (the latest revision of its chain).
+func (h *ResolverHandler) renderPassport(w http.ResponseWriter, r *http.Request, uid uuid.UUID) {
+	// 0. Determine Context (Public vs Restricted) and the viewer's Access Level
+	ctx := h.viewerContext(r)
+
+	// 1. Follow the revision chain: a printed QR code always shows the latest revision
+	versions, err := h.service.GetRevisionHistory(ctx, uid)
 	if err != nil {
 		h.log.Warn("passport not found", "id", uid, "error", err)
 		http.Error(w, "Passport Not Found", http.StatusNotFound)
 		return
 	}
+	latest := uid
+	if len(versions) > 0 {
+		latest = versions[len(versions)-1].PassportID
+	}
+	if latest != uid {
+		w.Header().Set("Content-Location", "/r/"+latest.String())
+	}
+
+	// 2. Fetch Data
+	passport, err := h.service.GetPassport(ctx, latest)
+	if err != nil {
+		h.log.Warn("passport not found", "id", latest, "error", err)
+		http.Error(w, "Passport Not Found", http.StatusNotFound)
+		return
+	}
+	h.recordScan(ctx, r, uid, domain.ScanSourcePassport)
 
-	// 2. Content Negotiation (The "Smart" Part)
+	// 3. GS1 Link Types (?linkType=gs1:pip, ?linkType=all). Recalls always show the passport.
+	w.Header().Set("Link", fmt.Sprintf(`<%s?linkType=all>; rel="linkset"; type="application/linkset+json"`, r.URL.Path))
+	if linkType := r.URL.Query().Get("linkType"); linkType != "" && passport.Status != domain.StatusRevoked {
+		if h.serveLink(w, r, passport, linkType) {
+			return
+		}
+	}
+
+	// 4. Content Negotiation (The "Smart" Part)
 	acceptHeader := r.Header.Get("Accept")
 
 	if strings.Contains(acceptHeader, "text/html") {
 		// --- RETURN HTML (Browser) ---
 		w.Header().Set("Content-Type", "text/html")
 
+		// Recalled products must never look valid to someone scanning an old QR code.
+		notice := ""
+		if passport.Status == domain.StatusRevoked {
+			notice = revocationNotice(passport)
+		}
+		history := versionHistory(versions, uid, latest)
+
 		// In a real app, use html/template here.
 		// For MVP, we inject the data into a simple string.
-		html := fmt.Sprintf(`
+		page := fmt.Sprintf(`
 			<!DOCTYPE html>
 			<html>
 			<head>
@@ -121,9 +223,11 @@ func (h *ResolverHandler) ResolvePassport(w http.ResponseWriter, r *http.Request
 					.status { display: inline-block; padding: 4px 8px; border-radius: 4px; background: #e0f7fa; color: #006064; font-size: 0.8em; font-weight: bold;}
 					h1 { font-size: 1.2em; margin-top: 0; }
 					pre { background: #f5f5f5; padding: 10px; overflow-x: auto; border-radius: 4px;}
+					.revoked { border: 2px solid #c62828; background: #ffebee; color: #b71c1c; border-radius: 8px; padding: 12px 16px; margin-bottom: 16px; }
 				</style>
 			</head>
 			<body>
+				%s
 				<div class="card">
 					<span class="status">%s</span>
 					<h1>Product Passport</h1>
@@ -132,57 +236,222 @@ func (h *ResolverHandler) ResolvePassport(w http.ResponseWriter, r *http.Request
 					<hr/>
 					<h3>Technical Data</h3>
 					<pre>%s</pre>
+					%s
 				</div>
 			</body>
 			</html>
-		`, passport.Status, passport.ID, passport.ProductCategory, passport.Attributes)
+		`, notice, passport.Status, passport.ID, passport.ProductCategory, passport.Attributes, history)
 
-		w.Write([]byte(html))
+		w.Write([]byte(page))
 
 	} else {
 		// --- RETURN JSON (API/App) ---
+		// Revoked passports carry their "revocation" block in the body.
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(passport)
+		json.NewEncoder(w).Encode(resolvedPassport{Passport: passport, Versions: versions})
+	}
+}
+
+// serveLink answers a linkType request with a redirect or the whole linkset. It returns false when
+// the passport page itself should be served: the GS1 default link, also used when no link of the
+// requested type is registered.
+func (h *ResolverHandler) serveLink(w http.ResponseWriter, r *http.Request, passport *domain.Passport, param string) bool {
+	// 1. Parse Link Type
+	linkType := domain.LinkType(param)
+	if linkType != domain.LinkTypeAll && param != "linkset" {
+		var err error
+		if linkType, err = domain.ParseLinkType(param); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return true
+		}
+		if linkType == domain.LinkTypeDefault {
+			return false
+		}
+	}
+
+	// 2. Fetch the links of the passport chain
+	links, err := h.links.ResolveLinks(r.Context(), passport.ID)
+	if err != nil {
+		h.log.Error("failed to resolve links", "id", passport.ID, "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return true
+	}
+
+	// 3a. Linkset
+	if linkType == domain.LinkTypeAll || param == "linkset" {
+		anchor := h.cfg.PublicBaseURL + r.URL.Path
+		passportURL := fmt.Sprintf("%s/r/%s", h.cfg.PublicBaseURL, passport.ID)
+		w.Header().Set("Content-Type", "application/linkset+json")
+		json.NewEncoder(w).Encode(buildLinkset(anchor, passportURL, links))
+		return true
 	}
+
+	// 3b. Redirect
+	link := selectLink(links, linkType, r.Header.Get("Accept-Language"), r.Header.Get("Accept"))
+	if link == nil {
+		return false
+	}
+	w.Header().Set("Vary", "Accept, Accept-Language")
+	http.Redirect(w, r, link.Href, http.StatusTemporaryRedirect)
+	return true
 }
 
+// viewerContext authenticates the optional credential of a resolver request.
+// Anonymous or invalid credentials get the public view.
+func (h *ResolverHandler) viewerContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		return ctx
+	}
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	if strings.HasPrefix(tokenString, "traceapi_") {
+		// Case A: Raw API Key
+		hash := sha256.Sum256([]byte(tokenString))
+		apiKeyHash := hex.EncodeToString(hash[:])
+		tenantID, valid, err := h.authRepo.ValidateKey(ctx, apiKeyHash)
+		if err != nil || !valid {
+			return ctx
+		}
+		level, err := h.authRepo.GetKeyAccessLevel(ctx, apiKeyHash)
+		if err != nil {
+			// Fail closed to the lowest tier; ownership still applies
+			h.log.Warn("failed to fetch api key access level", "error", err)
+			level = ""
+		}
+		ctx = context.WithValue(ctx, domain.ViewContextKey, domain.ViewContextRestricted)
+		ctx = context.WithValue(ctx, domain.ViewerTenantIDKey, tenantID)
+		return context.WithValue(ctx, domain.ViewerAccessLevelKey, level)
+	}
+
+	// Case B: JWT Token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(h.cfg.JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return ctx
+	}
+
+	ctx = context.WithValue(ctx, domain.ViewContextKey, domain.ViewContextRestricted)
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if sub, ok := claims["sub"].(string); ok {
+			ctx = context.WithValue(ctx, domain.ViewerTenantIDKey, sub)
+		}
+		if level, ok := claims["access_level"].(string); ok {
+			ctx = context.WithValue(ctx, domain.ViewerAccessLevelKey, level)
+		}
+	}
+	return ctx
+}
+
+// recordScan hands a resolver hit to scan analytics, counted against the identifier that was
+// requested (the printed one). Only coarse attributes of the request are kept.
+func (h *ResolverHandler) recordScan(ctx context.Context, r *http.Request, id uuid.UUID, source domain.ScanSource) {
+	if h.scans == nil {
+		return
+	}
+
+	tier := "public"
+	if viewContext, _ := ctx.Value(domain.ViewContextKey).(string); viewContext == domain.ViewContextRestricted {
+		tier = "authenticated"
+		if level, _ := ctx.Value(domain.ViewerAccessLevelKey).(string); level != "" {
+			tier = strings.ToLower(level)
+		}
+	}
+	country := ""
+	if h.cfg.ScanCountryHeader != "" {
+		country = domain.ScanCountry(r.Header.Get(h.cfg.ScanCountryHeader))
+	}
+
+	h.scans.Record(domain.ScanEvent{
+		PassportID: id,
+		Source:     source,
+		AgentClass: domain.ClassifyUserAgent(r.UserAgent()),
+		Country:    country,
+		Referrer:   domain.ScanReferrer(r.Referer()),
+		AccessTier: tier,
+		OccurredAt: time.Now().UTC(),
+	})
+}
+
+// resolvedPassport is the resolver's JSON view: the passport plus its revision history.
+type resolvedPassport struct {
+	*domain.Passport
+	Versions []domain.PassportVersion `json:"versions,omitempty"`
+}
+
+// versionHistory renders the revision list; empty for passports never revised.
+func versionHistory(versions []domain.PassportVersion, requested, latest uuid.UUID) string {
+	if len(versions) < 2 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(`<hr/><h3>Version History</h3>`)
+	if requested != latest {
+		b.WriteString(`<p><em>The scanned code refers to an older version. The latest version is shown above.</em></p>`)
+	}
+	b.WriteString(`<ul>`)
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		published := ""
+		if v.PublishedAt != nil {
+			published = " &middot; " + v.PublishedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(&b, `<li><a href="/r/%s">Version %d</a> &middot; %s%s</li>`, v.PassportID, v.Version, v.Status, published)
+	}
+	b.WriteString(`</ul>`)
+	return b.String()
+}
+
+// revocationNotice renders the warning banner shown above a revoked passport.
+func revocationNotice(passport *domain.Passport) string {
+	reason, revokedAt := "No reason given", ""
+	if passport.Revocation != nil {
+		if passport.Revocation.Reason != "" {
+			reason = passport.Revocation.Reason
+		}
+		revokedAt = passport.Revocation.RevokedAt.Format("2006-01-02")
+	}
+	return fmt.Sprintf(`<div class="revoked" role="alert">
+					<strong>This product passport has been REVOKED / RECALLED.</strong>
+					<p>The information below is no longer valid. Do not rely on it.</p>
+					<p><strong>Reason:</strong> %s</p>
+					<p><strong>Revoked on:</strong> %s</p>
+				</div>`, html.EscapeString(reason), html.EscapeString(revokedAt))
+}
+
+// GetQRCode handles GET /r/{id}/qr?format=svg&size=512&ecc=Q&quietZone=4&fg=000000&bg=ffffff
 func (h *ResolverHandler) GetQRCode(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 
-	// 1. Construct the Public URL
-	// In Prod, this comes from ENV VAR (e.g., "https://tapi.eu")
-	// In Dev, we use localhost or your LAN IP
-	baseURL := "http://localhost:8081"
-	targetURL := fmt.Sprintf("%s/r/%s", baseURL, idStr)
-
-	// 2. Generate QR Code (Recovery Level M is standard for industrial)
-	// 256 is the size in pixels (ignored for SVG, but required by API)
-	var png []byte
-	var err error
-
-	// Check if they want PNG or SVG? Let's default to PNG for easy testing,
-	// but if ?format=svg is passed, return SVG.
-	format := r.URL.Query().Get("format")
-
-	if format == "svg" {
-		// Logic for SVG (go-qrcode supports it differently, but for MVP let's stick to PNG first
-		// to keep code simple, or use the library's Write method)
-		// Actually, for simplicity, let's serve PNG to start.
-		png, err = qrcode.Encode(targetURL, qrcode.Medium, 256)
-	} else {
-		png, err = qrcode.Encode(targetURL, qrcode.Medium, 256)
+	// 1. Rendering Options
+	opts, err := parseQROptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
+	// 2. Generate the QR Code for the Public URL (the one printed on packaging)
+	targetURL := fmt.Sprintf("%s/r/%s", h.cfg.PublicBaseURL, idStr)
+	img, contentType, err := renderQR(targetURL, opts)
 	if err != nil {
-		h.log.Error("failed to generate qr", "error", err)
-		http.Error(w, "Failed to generate QR", http.StatusInternalServerError)
+		h.log.Warn("failed to generate qr", "error", err)
+		http.Error(w, "Failed to generate QR: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// 3. Return Image
+	// 3. Return Image (only the first fetch of each client is counted: the image is cached)
+	if uid, err := uuid.Parse(idStr); err == nil {
+		h.recordScan(h.viewerContext(r), r, uid, domain.ScanSourceQRCode)
+	}
 	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
-	w.Header().Set("Content-Type", "image/png")
-	w.Write(png)
+	w.Header().Set("Content-Type", contentType)
+	w.Write(img)
 }
 
 type ExchangeRequest struct {
@@ -221,12 +490,22 @@ func (h *ResolverHandler) ExchangeToken(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	level, err := h.authRepo.GetKeyAccessLevel(r.Context(), apiKeyHash)
+	if err != nil {
+		h.log.Error("Failed to fetch access level", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
 	// 2. Generate JWT
 	claims := jwt.MapClaims{
 		"sub": tenantID,
 		"iat": time.Now().Unix(),
 		"exp": time.Now().Add(1 * time.Hour).Unix(), // 1 Hour Expiration
 	}
+	if level != "" {
+		claims["access_level"] = level
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(h.cfg.JWTSecret))
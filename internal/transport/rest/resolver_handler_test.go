@@ -9,11 +9,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/TraceApi/api-core/internal/config"
+	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/transport/rest"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockAuthRepo (reused or defined here if not available in handler_test.go)
@@ -42,13 +48,18 @@ func (m *MockAuthRepo) GetTenantName(ctx context.Context, tenantID string) (stri
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockAuthRepo) GetKeyAccessLevel(ctx context.Context, apiKeyHash string) (string, error) {
+	args := m.Called(ctx, apiKeyHash)
+	return args.String(0), args.Error(1)
+}
+
 func TestExchangeToken(t *testing.T) {
 	// Setup
 	mockService := new(MockPassportService)
 	mockAuthRepo := new(MockAuthRepo)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	cfg := &config.Config{JWTSecret: "test-secret"}
-	handler := rest.NewResolverHandler(mockService, mockAuthRepo, logger, cfg)
+	handler := rest.NewResolverHandler(mockService, nil, mockAuthRepo, nil, logger, cfg)
 
 	t.Run("Valid API Key", func(t *testing.T) {
 		// Arrange
@@ -61,6 +72,7 @@ func TestExchangeToken(t *testing.T) {
 		// or we can duplicate it to be precise. Let's be precise.
 		// Actually, simpler: let the mock accept any string and return success.
 		mockAuthRepo.On("ValidateKey", mock.Anything, mock.Anything).Return("tenant-123", true, nil).Once()
+		mockAuthRepo.On("GetKeyAccessLevel", mock.Anything, mock.Anything).Return("notified_body", nil).Once()
 
 		reqBody := map[string]string{"apiKey": apiKey}
 		body, _ := json.Marshal(reqBody)
@@ -77,6 +89,14 @@ func TestExchangeToken(t *testing.T) {
 		var respBody map[string]string
 		json.NewDecoder(resp.Body).Decode(&respBody)
 		assert.NotEmpty(t, respBody["token"])
+
+		// The key's access level travels in the token
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(respBody["token"], claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("test-secret"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "notified_body", claims["access_level"])
 	})
 
 	t.Run("Invalid API Key", func(t *testing.T) {
@@ -121,3 +141,458 @@ func TestExchangeToken(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 	})
 }
+
+func TestResolvePassport_Revoked(t *testing.T) {
+	// Setup
+	mockService := new(MockPassportService)
+	mockAuthRepo := new(MockAuthRepo)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := rest.NewResolverHandler(mockService, nil, mockAuthRepo, nil, logger, cfg)
+
+	r := chi.NewRouter()
+	handler.RegisterResolverRoutes(r)
+
+	id := uuid.New()
+	revoked := &domain.Passport{
+		ID:              id,
+		ProductCategory: domain.CategoryBattery,
+		Status:          domain.StatusRevoked,
+		Attributes:      json.RawMessage(`{"batteryModel":"X1"}`),
+		Revocation: &domain.Revocation{
+			Reason:    "Cell defect <batch 42>",
+			RevokedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	mockService.On("GetRevisionHistory", mock.Anything, id).Return([]domain.PassportVersion{
+		{PassportID: id, Version: 1, Status: domain.StatusRevoked},
+	}, nil)
+	mockService.On("GetPassport", mock.Anything, id).Return(revoked, nil)
+
+	t.Run("HTML shows recall notice", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/r/"+id.String(), nil)
+		req.Header.Set("Accept", "text/html")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "REVOKED / RECALLED")
+		assert.Contains(t, w.Body.String(), "Cell defect &lt;batch 42&gt;")
+		assert.Contains(t, w.Body.String(), "2025-06-01")
+	})
+
+	t.Run("JSON carries revocation block", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/r/"+id.String(), nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		assert.Equal(t, "REVOKED", body["status"])
+		revocation, ok := body["revocation"].(map[string]interface{})
+		assert.True(t, ok, "revocation block should be present")
+		assert.Equal(t, "Cell defect <batch 42>", revocation["reason"])
+	})
+}
+
+func TestResolvePassport_FollowsRevisionChain(t *testing.T) {
+	// Setup
+	mockService := new(MockPassportService)
+	mockAuthRepo := new(MockAuthRepo)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := rest.NewResolverHandler(mockService, nil, mockAuthRepo, nil, logger, cfg)
+
+	r := chi.NewRouter()
+	handler.RegisterResolverRoutes(r)
+
+	// The printed QR code points at v1, which was superseded by v2
+	v1, v2 := uuid.New(), uuid.New()
+	mockService.On("GetRevisionHistory", mock.Anything, v1).Return([]domain.PassportVersion{
+		{PassportID: v1, Version: 1, Status: domain.StatusSuperseded, ImmutabilityHash: "h1"},
+		{PassportID: v2, Version: 2, Status: domain.StatusPublished, ImmutabilityHash: "h2"},
+	}, nil)
+	mockService.On("GetPassport", mock.Anything, v2).Return(&domain.Passport{
+		ID:         v2,
+		Status:     domain.StatusPublished,
+		Version:    2,
+		Attributes: json.RawMessage(`{}`),
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/r/"+v1.String(), nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/r/"+v2.String(), w.Header().Get("Content-Location"))
+
+	var body struct {
+		PassportID uuid.UUID                `json:"passportId"`
+		Version    int                      `json:"version"`
+		Versions   []domain.PassportVersion `json:"versions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, v2, body.PassportID)
+	assert.Equal(t, 2, body.Version)
+	assert.Len(t, body.Versions, 2)
+	mockService.AssertNotCalled(t, "GetPassport", mock.Anything, v1)
+}
+
+func TestResolvePassport_PassesViewerAccessLevel(t *testing.T) {
+	// Setup
+	mockService := new(MockPassportService)
+	mockAuthRepo := new(MockAuthRepo)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := rest.NewResolverHandler(mockService, nil, mockAuthRepo, nil, logger, cfg)
+
+	r := chi.NewRouter()
+	handler.RegisterResolverRoutes(r)
+
+	id := uuid.New()
+	mockService.On("GetRevisionHistory", mock.Anything, id).Return([]domain.PassportVersion{}, nil)
+	mockService.On("GetPassport", mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(domain.ViewContextKey) == domain.ViewContextRestricted &&
+			ctx.Value(domain.ViewerTenantIDKey) == "recycler-1" &&
+			ctx.Value(domain.ViewerAccessLevelKey) == "legitimate_interest"
+	}), id).Return(&domain.Passport{ID: id, Attributes: json.RawMessage(`{}`)}, nil)
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":          "recycler-1",
+		"access_level": "legitimate_interest",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+
+	req := httptest.NewRequest("GET", "/r/"+id.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+type MockScanRecorder struct {
+	mock.Mock
+}
+
+func (m *MockScanRecorder) Record(scan domain.ScanEvent) {
+	m.Called(scan)
+}
+
+func TestResolver_RecordsScans(t *testing.T) {
+	// Setup
+	mockService := new(MockPassportService)
+	scans := new(MockScanRecorder)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{JWTSecret: "test-secret", ScanCountryHeader: "CF-IPCountry", PublicBaseURL: "https://tapi.eu"}
+	handler := rest.NewResolverHandler(mockService, nil, new(MockAuthRepo), scans, logger, cfg)
+
+	r := chi.NewRouter()
+	handler.RegisterResolverRoutes(r)
+
+	id := uuid.New()
+	mockService.On("GetRevisionHistory", mock.Anything, id).Return([]domain.PassportVersion{}, nil)
+	mockService.On("GetPassport", mock.Anything, id).Return(&domain.Passport{ID: id, Attributes: json.RawMessage(`{}`)}, nil)
+
+	t.Run("Passport page", func(t *testing.T) {
+		scans.On("Record", mock.MatchedBy(func(scan domain.ScanEvent) bool {
+			return scan.PassportID == id && scan.Source == domain.ScanSourcePassport &&
+				scan.AgentClass == domain.AgentMobile && scan.Country == "FR" &&
+				scan.Referrer == "shop.example.com" && scan.AccessTier == "public" && !scan.OccurredAt.IsZero()
+		})).Once()
+
+		req := httptest.NewRequest("GET", "/r/"+id.String(), nil)
+		req.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148")
+		req.Header.Set("CF-IPCountry", "fr")
+		req.Header.Set("Referer", "https://shop.example.com/orders/42")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		scans.AssertExpectations(t)
+	})
+
+	t.Run("QR code of an authenticated viewer", func(t *testing.T) {
+		scans.On("Record", mock.MatchedBy(func(scan domain.ScanEvent) bool {
+			return scan.PassportID == id && scan.Source == domain.ScanSourceQRCode &&
+				scan.AgentClass == domain.AgentBot && scan.Country == "" && scan.AccessTier == "legitimate_interest"
+		})).Once()
+
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":          "recycler-1",
+			"access_level": "LEGITIMATE_INTEREST",
+			"exp":          time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("test-secret"))
+		req := httptest.NewRequest("GET", "/r/"+id.String()+"/qr", nil)
+		req.Header.Set("User-Agent", "curl/8.4.0")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		scans.AssertExpectations(t)
+	})
+
+	t.Run("Unknown passports are not counted", func(t *testing.T) {
+		unknown := uuid.New()
+		mockService.On("GetRevisionHistory", mock.Anything, unknown).Return(nil, domain.ErrNotFound)
+
+		req := httptest.NewRequest("GET", "/r/"+unknown.String(), nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		scans.AssertNumberOfCalls(t, "Record", 2)
+	})
+}
+
+func TestSearchText_Handler(t *testing.T) {
+	// Setup
+	mockService := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := rest.NewResolverHandler(mockService, nil, new(MockAuthRepo), nil, logger, &config.Config{JWTSecret: "test-secret"})
+
+	r := chi.NewRouter()
+	handler.RegisterResolverRoutes(r)
+
+	id := uuid.New()
+	mockService.On("SearchText", mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(domain.ViewerTenantIDKey) == "recycler-1"
+	}), "powercell lfp", 5).Return([]domain.TextSearchResult{{Score: 0.5, Passport: &domain.Passport{ID: id}}}, nil)
+	mockService.On("SearchText", mock.Anything, "", 0).Return(nil, domain.ErrInvalidInput)
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "recycler-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+
+	// Execute
+	req := httptest.NewRequest("GET", "/search?q=powercell+lfp&limit=5", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// Assert: searched as the authenticated viewer
+	assert.Equal(t, http.StatusOK, w.Code)
+	var results []domain.TextSearchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, id, results[0].Passport.ID)
+
+	for _, url := range []string{"/search", "/search?q=lfp&limit=x"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
+func TestResolveDigitalLink(t *testing.T) {
+	// Setup
+	mockService := new(MockPassportService)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := rest.NewResolverHandler(mockService, nil, new(MockAuthRepo), nil, logger, &config.Config{JWTSecret: "test-secret"})
+
+	r := chi.NewRouter()
+	handler.RegisterResolverRoutes(r)
+
+	rootID, latestID := uuid.New(), uuid.New()
+	ids := domain.ProductIdentifiers{GTIN: "09506000134352", SerialNumber: "ABC/1"}
+	mockService.On("ResolveIdentifiers", mock.Anything, ids).Return(rootID, nil)
+	mockService.On("GetRevisionHistory", mock.Anything, rootID).Return([]domain.PassportVersion{
+		{PassportID: rootID, Version: 1, Status: domain.StatusSuperseded},
+		{PassportID: latestID, Version: 2, Status: domain.StatusPublished},
+	}, nil)
+	mockService.On("GetPassport", mock.Anything, latestID).Return(&domain.Passport{
+		ID: latestID, Status: domain.StatusPublished, GTIN: ids.GTIN, SerialNumber: ids.SerialNumber,
+	}, nil)
+
+	t.Run("Uncompressed URI with encoded serial", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/01/09506000134352/21/ABC%2F1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/r/"+latestID.String(), w.Header().Get("Content-Location"))
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		assert.Equal(t, latestID.String(), body["passportId"])
+	})
+
+	t.Run("Lot and serial", func(t *testing.T) {
+		withLot := domain.ProductIdentifiers{GTIN: "09506000134352", BatchNumber: "L1", SerialNumber: "ABC/1"}
+		mockService.On("ResolveIdentifiers", mock.Anything, withLot).Return(rootID, nil).Once()
+
+		req := httptest.NewRequest("GET", "/01/09506000134352/10/L1/21/ABC%2F1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid check digit", func(t *testing.T) {
+		bad := domain.ProductIdentifiers{GTIN: "09506000134353"}
+		mockService.On("ResolveIdentifiers", mock.Anything, bad).Return(uuid.Nil, domain.ErrInvalidInput).Once()
+
+		req := httptest.NewRequest("GET", "/01/09506000134353", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unpublished draft", func(t *testing.T) {
+		draft := domain.ProductIdentifiers{GTIN: "04012345000009"}
+		mockService.On("ResolveIdentifiers", mock.Anything, draft).Return(uuid.Nil, domain.ErrNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/01/04012345000009", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertNotCalled(t, "GetPassport", mock.Anything, uuid.Nil)
+	})
+
+	t.Run("Compressed URI", func(t *testing.T) {
+		// (01) 09506000134352 (21) ABC/1, 7-bit ASCII serial
+		req := httptest.NewRequest("GET", "/ARFKk4XBoEMLBhQ17E", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/r/"+latestID.String(), w.Header().Get("Content-Location"))
+	})
+
+	t.Run("Not a compressed URI", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/favicon-icon-1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+type MockLinkService struct {
+	mock.Mock
+}
+
+func (m *MockLinkService) CreateLink(ctx context.Context, manufacturerID string, input domain.LinkInput) (*domain.PassportLink, error) {
+	args := m.Called(ctx, manufacturerID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PassportLink), args.Error(1)
+}
+
+func (m *MockLinkService) ListLinks(ctx context.Context, manufacturerID string) ([]*domain.PassportLink, error) {
+	args := m.Called(ctx, manufacturerID)
+	return args.Get(0).([]*domain.PassportLink), args.Error(1)
+}
+
+func (m *MockLinkService) DeleteLink(ctx context.Context, id uuid.UUID, manufacturerID string) error {
+	args := m.Called(ctx, id, manufacturerID)
+	return args.Error(0)
+}
+
+func (m *MockLinkService) ResolveLinks(ctx context.Context, passportID uuid.UUID) ([]*domain.PassportLink, error) {
+	args := m.Called(ctx, passportID)
+	return args.Get(0).([]*domain.PassportLink), args.Error(1)
+}
+
+func TestResolvePassport_LinkType(t *testing.T) {
+	// Setup
+	mockService := new(MockPassportService)
+	mockLinks := new(MockLinkService)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{JWTSecret: "test-secret", PublicBaseURL: "https://tapi.eu"}
+	handler := rest.NewResolverHandler(mockService, mockLinks, new(MockAuthRepo), nil, logger, cfg)
+
+	r := chi.NewRouter()
+	handler.RegisterResolverRoutes(r)
+
+	id := uuid.New()
+	mockService.On("GetRevisionHistory", mock.Anything, id).Return([]domain.PassportVersion{}, nil)
+	mockService.On("GetPassport", mock.Anything, id).Return(&domain.Passport{ID: id, Status: domain.StatusPublished, Attributes: json.RawMessage(`{}`)}, nil)
+	mockLinks.On("ResolveLinks", mock.Anything, id).Return([]*domain.PassportLink{
+		{LinkType: domain.LinkTypeInstructions, Href: "https://example.com/manual-en.pdf", Title: "Manual", Language: "en", MediaType: "application/pdf"},
+		{LinkType: domain.LinkTypeInstructions, Href: "https://example.com/manual-de.pdf", Title: "Anleitung", Language: "de", MediaType: "application/pdf"},
+		{LinkType: domain.LinkTypeSustainabilityInfo, Href: "https://example.com/recycling", Title: "Recycling"},
+	}, nil)
+
+	t.Run("Redirects to the preferred language", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/r/"+id.String()+"?linkType=gs1:instructions", nil)
+		req.Header.Set("Accept-Language", "de-AT, en;q=0.5")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "https://example.com/manual-de.pdf", w.Header().Get("Location"))
+	})
+
+	t.Run("Accepts the vocabulary URI", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/r/"+id.String()+"?linkType=https://gs1.org/voc/sustainabilityInfo", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "https://example.com/recycling", w.Header().Get("Location"))
+	})
+
+	t.Run("Missing type falls back to the passport", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/r/"+id.String()+"?linkType=gs1:pip", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("Linkset", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/r/"+id.String()+"?linkType=all", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/linkset+json", w.Header().Get("Content-Type"))
+
+		var body struct {
+			Linkset []map[string]json.RawMessage `json:"linkset"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Len(t, body.Linkset, 1)
+		assert.JSONEq(t, `"https://tapi.eu/r/`+id.String()+`"`, string(body.Linkset[0]["anchor"]))
+		assert.JSONEq(t, `[
+			{"href": "https://example.com/manual-en.pdf", "title": "Manual", "hreflang": ["en"], "type": "application/pdf"},
+			{"href": "https://example.com/manual-de.pdf", "title": "Anleitung", "hreflang": ["de"], "type": "application/pdf"}
+		]`, string(body.Linkset[0]["https://gs1.org/voc/instructions"]))
+		assert.Contains(t, body.Linkset[0], "https://gs1.org/voc/defaultLink")
+	})
+
+	t.Run("Unknown vocabulary", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/r/"+id.String()+"?linkType=schema:manual", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
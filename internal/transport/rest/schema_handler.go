@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+type SchemaHandler struct {
+	service ports.SchemaService
+	log     *slog.Logger
+}
+
+func NewSchemaHandler(s ports.SchemaService, log *slog.Logger) *SchemaHandler {
+	return &SchemaHandler{service: s, log: log}
+}
+
+// RegisterRoutes wires up the admin endpoints to the router.
+// The caller is responsible for restricting them to administrators.
+func (h *SchemaHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/admin/schemas/{category}", h.ListSchemas)
+	r.Get("/admin/schemas/{category}/{version}", h.GetSchema)
+	r.Put("/admin/schemas/{category}/{version}", h.UploadSchema)
+	r.Post("/admin/schemas/{category}/{version}/activate", h.ActivateSchema)
+}
+
+// ListSchemas handles GET /admin/schemas/{category}
+func (h *SchemaHandler) ListSchemas(w http.ResponseWriter, r *http.Request) {
+	category := domain.ProductCategory(chi.URLParam(r, "category"))
+
+	schemas, err := h.service.ListSchemas(r.Context(), category)
+	if err != nil {
+		h.writeError(w, "failed to list schemas", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schemas)
+}
+
+// GetSchema handles GET /admin/schemas/{category}/{version}
+func (h *SchemaHandler) GetSchema(w http.ResponseWriter, r *http.Request) {
+	category := domain.ProductCategory(chi.URLParam(r, "category"))
+	version := chi.URLParam(r, "version")
+
+	schema, err := h.service.GetSchema(r.Context(), category, version)
+	if err != nil {
+		h.writeError(w, "failed to get schema", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schema)
+}
+
+// UploadSchema handles PUT /admin/schemas/{category}/{version}
+// The request body is the JSON Schema document itself.
+func (h *SchemaHandler) UploadSchema(w http.ResponseWriter, r *http.Request) {
+	// 1. Parse Path
+	category := domain.ProductCategory(chi.URLParam(r, "category"))
+	version := chi.URLParam(r, "version")
+
+	// 2. Read Body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	// 3. Call Service
+	schema, err := h.service.UploadSchema(r.Context(), category, version, body)
+	if err != nil {
+		h.writeError(w, "failed to upload schema", err)
+		return
+	}
+
+	// 4. Respond
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schema)
+}
+
+// ActivateSchema handles POST /admin/schemas/{category}/{version}/activate
+func (h *SchemaHandler) ActivateSchema(w http.ResponseWriter, r *http.Request) {
+	category := domain.ProductCategory(chi.URLParam(r, "category"))
+	version := chi.URLParam(r, "version")
+
+	schema, err := h.service.ActivateSchema(r.Context(), category, version)
+	if err != nil {
+		h.writeError(w, "failed to activate schema", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schema)
+}
+
+func (h *SchemaHandler) writeError(w http.ResponseWriter, msg string, err error) {
+	h.log.Error(msg, "error", err)
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "schema not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
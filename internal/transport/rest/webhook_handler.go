@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2025 Alessandro Faranda Gancio (dba TraceApi)
+ *
+ * This source code is licensed under the Business Source License 1.1.
+ *
+ * Change Date: 2027-11-28
+ * Change License: AGPL-3.0
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/TraceApi/api-core/internal/core/domain"
+	"github.com/TraceApi/api-core/internal/core/ports"
+	"github.com/TraceApi/api-core/internal/transport/rest/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	service ports.WebhookService
+	log     *slog.Logger
+}
+
+func NewWebhookHandler(s ports.WebhookService, log *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{service: s, log: log}
+}
+
+// RegisterRoutes wires up the endpoints to the router
+func (h *WebhookHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/webhooks", h.CreateSubscription)
+	r.Get("/webhooks", h.ListSubscriptions)
+	r.Delete("/webhooks/{id}", h.DeleteSubscription)
+	r.Get("/webhooks/deliveries", h.ListDeliveries)
+	r.Post("/webhooks/deliveries/{id}/redeliver", h.Redeliver)
+}
+
+// CreateSubscription handles POST /webhooks
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	// 1. Get Tenant ID
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Decode Body
+	var input domain.WebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Call Service
+	sub, err := h.service.CreateSubscription(r.Context(), tenantID, input)
+	if err != nil {
+		h.writeError(w, "failed to create webhook subscription", err)
+		return
+	}
+
+	// 4. Respond (the only time the secret is shown)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// ListSubscriptions handles GET /webhooks
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subs, err := h.service.ListSubscriptions(r.Context(), tenantID)
+	if err != nil {
+		h.writeError(w, "failed to list webhook subscriptions", err)
+		return
+	}
+	if subs == nil {
+		subs = []*domain.WebhookSubscription{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subs)
+}
+
+// DeleteSubscription handles DELETE /webhooks/{id}
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	tenantID, id, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), id, tenantID); err != nil {
+		h.writeError(w, "failed to delete webhook subscription", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/deliveries?status=DEAD
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	status := domain.DeliveryStatus(strings.ToUpper(r.URL.Query().Get("status")))
+	deliveries, err := h.service.ListDeliveries(r.Context(), tenantID, status)
+	if err != nil {
+		h.writeError(w, "failed to list webhook deliveries", err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*domain.WebhookDelivery{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// Redeliver handles POST /webhooks/deliveries/{id}/redeliver
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	tenantID, id, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), id, tenantID)
+	if err != nil {
+		h.writeError(w, "failed to redeliver webhook", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+func (h *WebhookHandler) parseRequest(w http.ResponseWriter, r *http.Request) (string, uuid.UUID, bool) {
+	tenantID, ok := middleware.GetManufacturerID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return "", uuid.Nil, false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return "", uuid.Nil, false
+	}
+	return tenantID, id, true
+}
+
+func (h *WebhookHandler) writeError(w http.ResponseWriter, msg string, err error) {
+	h.log.Error(msg, "error", err)
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/TraceApi/api-core/internal/config"
 	"github.com/TraceApi/api-core/internal/core/domain"
 	"github.com/TraceApi/api-core/internal/core/service"
-	"github.com/TraceApi/api-core/internal/platform/bus"
 	"github.com/TraceApi/api-core/internal/platform/cache"
 	"github.com/TraceApi/api-core/internal/platform/logger"
 	"github.com/TraceApi/api-core/internal/platform/storage/postgres"
@@ -53,9 +52,6 @@ func setupIntegrationServer(t *testing.T) (*httptest.Server, func()) {
 	redisStore := cache.NewRedisStore(redisClient)
 	authRepo := cache.NewRedisAuthRepository(redisClient, dbPool)
 
-	// 2b. Event Bus
-	eventBus := bus.NewRedisEventBus(cfg.RedisAddr)
-
 	// 3. Blob Storage
 	blobStore, err := s3.NewBlobStore(ctx, s3.Config{
 		Endpoint:  cfg.S3Endpoint,
@@ -67,10 +63,12 @@ func setupIntegrationServer(t *testing.T) (*httptest.Server, func()) {
 
 	// 4. Wiring
 	passportRepo := postgres.NewPassportRepository(dbPool)
-	passportSvc, err := service.NewPassportService(passportRepo, redisStore, blobStore, eventBus, log)
+	schemaRegistry := postgres.NewSchemaRegistry(dbPool)
+	grantRepo := postgres.NewGrantRepository(dbPool)
+	passportSvc, err := service.NewPassportService(passportRepo, redisStore, blobStore, schemaRegistry, grantRepo, nil, nil, "", log)
 	require.NoError(t, err, "Failed to initialize service")
 
-	passportHandler := rest.NewPassportHandler(passportSvc, log)
+	passportHandler := rest.NewPassportHandler(passportSvc, nil, log)
 
 	// 5. Router
 	r := chi.NewRouter()